@@ -4,8 +4,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"reflect"
+	"slices"
+	"strings"
 )
 
 // Builder provides a fluent API for constructing a Config instance. It allows for
@@ -13,14 +17,23 @@ import (
 type Builder struct {
 	cfg             *Config
 	opts            LoadOptions
+	appName         string
 	defaults        any
 	tagName         string
 	fileFormat      string
 	securityOpts    *SecurityOptions
+	maxValueSize    *int64
 	prefix          string
+	autoEnv         bool
 	file            string
+	fileDiscovery   bool
+	profile         string
+	embedded        []byte
+	embeddedFormat  string
 	args            []string
+	logger          Logger
 	err             error
+	validateTypes   bool
 	validators      []ValidatorFunc
 	typedValidators []any
 }
@@ -29,6 +42,81 @@ type Builder struct {
 // It receives the fully loaded *Config object and should return an error if validation fails.
 type ValidatorFunc func(c *Config) error
 
+// FieldError describes a validation problem with one configuration path
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationError aggregates field errors. Validators return it to report several
+// problems at once; Build merges the field errors of all validators into one.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		msgs[i] = field.Error()
+	}
+	return fmt.Sprintf("%d invalid field(s): %s", len(e.Fields), strings.Join(msgs, "; "))
+}
+
+// MultiValidator collects field errors within a validator
+//
+//	var v config.MultiValidator
+//	if port < 1024 {
+//		v.Addf("server.port", "must be >= 1024, got %d", port)
+//	}
+//	return v.Err()
+type MultiValidator struct {
+	fields []FieldError
+}
+
+// Add records a problem with path
+func (v *MultiValidator) Add(path, message string) {
+	v.fields = append(v.fields, FieldError{Path: path, Message: message})
+}
+
+// Addf records a problem with path using a format string
+func (v *MultiValidator) Addf(path, format string, args ...any) {
+	v.Add(path, fmt.Sprintf(format, args...))
+}
+
+// Err returns a *ValidationError with the recorded problems, or nil if there are none
+func (v *MultiValidator) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: append([]FieldError(nil), v.fields...)}
+}
+
+// collectFieldErrors appends the field errors of a *ValidationError in err's chain
+// to fields, reporting whether err was one
+func collectFieldErrors(err error, fields *[]FieldError) bool {
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return false
+	}
+	*fields = append(*fields, verr.Fields...)
+	return true
+}
+
+// withFieldErrors joins the field errors collected before a fatal validator error
+// into it, so they are not lost when the build stops
+func withFieldErrors(err error, fields []FieldError) error {
+	if len(fields) == 0 {
+		return err
+	}
+	return errors.Join(err, &ValidationError{Fields: fields})
+}
+
 // NewBuilder creates a new configuration builder
 func NewBuilder() *Builder {
 	return &Builder{
@@ -46,6 +134,19 @@ func (b *Builder) Build() (*Config, error) {
 		return nil, b.err
 	}
 
+	// Derive the env prefix and config file from the app name unless set explicitly
+	if b.appName != "" {
+		prefix := appEnvPrefix(b.appName)
+		if b.opts.EnvPrefix == "" {
+			b.opts.EnvPrefix = prefix
+		}
+		if b.file == "" && !b.fileDiscovery {
+			opts := DefaultDiscoveryOptions(b.appName)
+			opts.EnvVar = prefix + "CONFIG"
+			b.WithFileDiscovery(opts)
+		}
+	}
+
 	// Use tagName if set, default to "toml"
 	tagName := b.tagName
 	if tagName == "" {
@@ -56,9 +157,15 @@ func (b *Builder) Build() (*Config, error) {
 	if b.fileFormat != "" {
 		b.cfg.fileFormat = b.fileFormat
 	}
+	if b.logger != nil {
+		b.cfg.SetLogger(b.logger)
+	}
 	if b.securityOpts != nil {
 		b.cfg.securityOpts = b.securityOpts
 	}
+	if b.maxValueSize != nil {
+		b.cfg.SetMaxValueSize(*b.maxValueSize)
+	}
 
 	// 1. Register defaults
 	// If WithDefaults() was called, it takes precedence.
@@ -76,9 +183,34 @@ func (b *Builder) Build() (*Config, error) {
 		}
 	}
 
+	// Catch drift between registered defaults and the target struct before loading
+	if b.cfg.structCache != nil && b.cfg.structCache.target != nil {
+		if err := b.cfg.checkTargetDefaults(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Record env var names for all registered paths
+	if b.autoEnv {
+		transform := b.opts.EnvTransform
+		if transform == nil {
+			transform = defaultEnvTransform(b.opts.EnvPrefix, b.opts.EnvSeparator)
+		}
+		b.cfg.recordEnvNames(transform)
+	}
+
+	// Install embedded content as the base file layer; an on-disk file overlays it
+	if b.embedded != nil {
+		b.cfg.options = b.opts // Strict and auto-register apply to embedded content
+		if err := b.cfg.loadFileBase(b.embedded, b.embeddedFormat); err != nil {
+			return nil, fmt.Errorf("failed to load embedded config: %w", err)
+		}
+	}
+
 	// Explicitly set the file path on the config object so the watcher can find it,
 	// even if the initial load fails with a non-fatal error (file not found).
 	b.cfg.configFilePath = b.file
+	b.cfg.profile = b.profile
 
 	// 2. Load configuration
 	loadErr := b.cfg.LoadWithOptions(b.file, b.args, b.opts)
@@ -87,12 +219,19 @@ func (b *Builder) Build() (*Config, error) {
 		return nil, loadErr
 	}
 
-	// 3. Run non-typed validators
-	for _, validator := range b.validators {
-		if err := validator(b.cfg); err != nil {
+	// 3. Check value types, then run non-typed validators
+	if b.validateTypes {
+		if err := b.cfg.ValidateTypes(); err != nil {
 			return nil, fmt.Errorf("configuration validation failed: %w", err)
 		}
 	}
+	// Field errors from all validators are reported together; other errors stop the build
+	var fieldErrors []FieldError
+	for _, validator := range b.validators {
+		if err := validator(b.cfg); err != nil && !collectFieldErrors(err, &fieldErrors) {
+			return nil, withFieldErrors(fmt.Errorf("configuration validation failed: %w", err), fieldErrors)
+		}
+	}
 
 	// 4. Populate target and run typed validators
 	if b.cfg.structCache != nil && b.cfg.structCache.target != nil && len(b.typedValidators) > 0 {
@@ -116,11 +255,17 @@ func (b *Builder) Build() (*Config, error) {
 			results := validatorFunc.Call([]reflect.Value{reflect.ValueOf(populatedTarget)})
 			if !results[0].IsNil() {
 				err := results[0].Interface().(error)
-				return nil, fmt.Errorf("typed configuration validation failed: %w", err)
+				if !collectFieldErrors(err, &fieldErrors) {
+					return nil, withFieldErrors(fmt.Errorf("typed configuration validation failed: %w", err), fieldErrors)
+				}
 			}
 		}
 	}
 
+	if len(fieldErrors) > 0 {
+		return nil, fmt.Errorf("configuration validation failed: %w", &ValidationError{Fields: fieldErrors})
+	}
+
 	// ErrConfigNotFound or nil
 	return b.cfg, loadErr
 }
@@ -175,24 +320,101 @@ func (b *Builder) WithSecurityOptions(opts SecurityOptions) *Builder {
 	return b
 }
 
+// WithMaxValueSize sets the maximum string value size in bytes (0 for unlimited)
+func (b *Builder) WithMaxValueSize(n int64) *Builder {
+	if n < 0 {
+		b.err = fmt.Errorf("max value size cannot be negative: %d", n)
+		return b
+	}
+	b.maxValueSize = &n
+	return b
+}
+
+// WithLogger sets the logger for internal diagnostics, see Config.SetLogger
+func (b *Builder) WithLogger(logger Logger) *Builder {
+	b.logger = logger
+	return b
+}
+
 // WithPrefix sets the prefix for struct registration
 func (b *Builder) WithPrefix(prefix string) *Builder {
 	b.prefix = prefix
 	return b
 }
 
+// WithAppName derives the env prefix, config file name, and file discovery from
+// an application name. For "myapp", env vars use the "MYAPP_" prefix and the config
+// file is discovered as myapp.toml (or .conf, .config) from --config, MYAPP_CONFIG,
+// the current directory, and the XDG paths. It is applied at build time, so
+// WithEnvPrefix, WithFile, and WithFileDiscovery override it in any order, even
+// when the explicit discovery finds no file.
+func (b *Builder) WithAppName(name string) *Builder {
+	b.appName = name
+	return b
+}
+
+// appEnvPrefix returns the env var prefix for an application name, such as
+// "MY_APP_" for "my-app"
+func appEnvPrefix(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(name)) + "_"
+}
+
 // WithEnvPrefix sets the environment variable prefix
 func (b *Builder) WithEnvPrefix(prefix string) *Builder {
 	b.opts.EnvPrefix = prefix
 	return b
 }
 
+// WithEnvSeparator sets the separator between path segments in env var names, such as "__"
+func (b *Builder) WithEnvSeparator(separator string) *Builder {
+	b.opts.EnvSeparator = separator
+	return b
+}
+
+// WithAutoEnv sets the environment variable prefix and records the env var name
+// of every registered path at build time. Explicit env tags keep their names.
+// The recorded mapping is reported by EnvMapping, DiscoverEnv, and Validate.
+func (b *Builder) WithAutoEnv(prefix string) *Builder {
+	b.opts.EnvPrefix = prefix
+	b.autoEnv = true
+	return b
+}
+
 // WithFile sets the configuration file path
 func (b *Builder) WithFile(path string) *Builder {
 	b.file = path
 	return b
 }
 
+// WithReader loads configuration content from a reader at SourceFile precedence.
+// Useful for defaults embedded with go:embed. If WithFile is also used, values
+// from the on-disk file take precedence over the reader content.
+func (b *Builder) WithReader(r io.Reader, format string) *Builder {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		b.err = fmt.Errorf("failed to read embedded config: %w", err)
+		return b
+	}
+	return b.WithBytes(data, format)
+}
+
+// WithBytes is like WithReader but takes the configuration content directly
+func (b *Builder) WithBytes(data []byte, format string) *Builder {
+	switch format {
+	case "toml", "json", "yaml", "auto":
+		b.embedded = data
+		b.embeddedFormat = format
+	default:
+		b.err = fmt.Errorf("unsupported file format %q", format)
+	}
+	return b
+}
+
 // WithArgs sets the command-line arguments
 func (b *Builder) WithArgs(args []string) *Builder {
 	b.args = args
@@ -205,6 +427,18 @@ func (b *Builder) WithSources(sources ...Source) *Builder {
 	return b
 }
 
+// WithStrict rejects config files containing keys that match no registered path
+func (b *Builder) WithStrict() *Builder {
+	b.opts.Strict = true
+	return b
+}
+
+// WithAutoRegister registers unknown keys found in config files as new paths
+func (b *Builder) WithAutoRegister() *Builder {
+	b.opts.AutoRegisterUnknown = true
+	return b
+}
+
 // WithEnvTransform sets a custom environment variable transformer
 func (b *Builder) WithEnvTransform(fn EnvTransformFunc) *Builder {
 	b.opts.EnvTransform = fn
@@ -250,7 +484,9 @@ func (b *Builder) WithTarget(target any) *Builder {
 // WithValidator adds a validation function that runs at the end of the build process
 // Multiple validators can be added and are executed in the order they are added
 // Validation runs after all sources are loaded
-// If any validator returns error, build fails without running subsequent validators
+// If any validator returns error, build fails without running subsequent validators,
+// except for *ValidationError, whose field errors are collected from all validators
+// and returned together
 func (b *Builder) WithValidator(fn ValidatorFunc) *Builder {
 	if fn != nil {
 		b.validators = append(b.validators, fn)
@@ -258,6 +494,13 @@ func (b *Builder) WithValidator(fn ValidatorFunc) *Builder {
 	return b
 }
 
+// WithTypeValidation runs ValidateTypes after loading, before any validators,
+// so values that cannot convert to their default's type fail the build
+func (b *Builder) WithTypeValidation() *Builder {
+	b.validateTypes = true
+	return b
+}
+
 // WithTypedValidator adds a type-safe validation function that runs at the end of the build process,
 // after the target struct has been populated. The provided function must accept a single argument
 // that is a pointer to the same type as the one provided to WithTarget, and must return an error.
@@ -275,4 +518,25 @@ func (b *Builder) WithTypedValidator(fn any) *Builder {
 
 	b.typedValidators = append(b.typedValidators, fn)
 	return b
+}
+
+// checkTargetDefaults decodes each registered default into a fresh value of the
+// target type and reports every default that does not fit its field, by path
+func (c *Config) checkTargetDefaults() error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var errs []error
+	for _, path := range slices.Sorted(maps.Keys(c.items)) {
+		def := c.items[path].defaultValue
+		if def == nil {
+			continue
+		}
+		data := make(map[string]any)
+		setNestedValue(data, path, def)
+		if err := c.decodeInto(reflect.New(c.structCache.targetType).Interface(), data, nil); err != nil {
+			errs = append(errs, fmt.Errorf("default for %s (%T) does not decode into %v: %w", path, def, c.structCache.targetType, err))
+		}
+	}
+	return errors.Join(errs...)
 }
\ No newline at end of file
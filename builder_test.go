@@ -2,10 +2,13 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -110,6 +113,37 @@ func TestBuilder(t *testing.T) {
 		assert.Equal(t, target, result)
 	})
 
+	t.Run("DefaultTargetMismatch", func(t *testing.T) {
+		type Target struct {
+			Port    int           `toml:"port"`
+			Timeout time.Duration `toml:"timeout"`
+			Host    string        `toml:"host"`
+		}
+		type Defaults struct {
+			Port    string `toml:"port"`
+			Timeout string `toml:"timeout"`
+			Host    string `toml:"host"`
+		}
+
+		// Defaults that convert, such as "8080" to int and "5s" to a duration, are fine
+		_, err := NewBuilder().
+			WithDefaults(&Defaults{Port: "8080", Timeout: "5s", Host: "localhost"}).
+			WithTarget(&Target{}).
+			Build()
+		require.NoError(t, err)
+
+		// Drifted defaults fail the build before any load, naming each path and type
+		_, err = NewBuilder().
+			WithDefaults(&Defaults{Port: "http", Timeout: "soon", Host: "localhost"}).
+			WithTarget(&Target{}).
+			WithFile("missing.toml").
+			Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "default for port (string)")
+		assert.Contains(t, err.Error(), "default for timeout (string)")
+		assert.NotContains(t, err.Error(), "default for host")
+	})
+
 	t.Run("BuilderWithValidator", func(t *testing.T) {
 		type UserConfig struct {
 			Port int `toml:"port"`
@@ -224,6 +258,24 @@ func TestFileDiscovery(t *testing.T) {
 		assert.Equal(t, "value", val)
 	})
 
+	t.Run("DiscoveryWithSingleDashFlag", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configFile := filepath.Join(tmpDir, "single.toml")
+		os.WriteFile(configFile, []byte(`test = "single"`), 0644)
+
+		cfg, err := NewBuilder().
+			WithDefaults(struct {
+				Test string `toml:"test"`
+			}{Test: "default"}).
+			WithArgs([]string{"-config", configFile}).
+			WithFileDiscovery(DefaultDiscoveryOptions("myapp")).
+			Build()
+
+		require.NoError(t, err)
+		val, _ := cfg.Get("test")
+		assert.Equal(t, "single", val)
+	})
+
 	// Rest of test cases remain the same...
 	t.Run("DiscoveryWithEnvVar", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -302,6 +354,300 @@ func TestFileDiscovery(t *testing.T) {
 		val, _ := cfg.Get("test")
 		assert.Equal(t, "clifile", val)
 	})
+
+	t.Run("WithAppName", func(t *testing.T) {
+		type appConfig struct {
+			Test string `toml:"test"`
+			Port int    `toml:"port"`
+		}
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		// File name and env prefix both derive from the app name
+		tmpDir := t.TempDir()
+		t.Chdir(tmpDir)
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "myapp.toml"), []byte(`test = "cwdvalue"`), 0644))
+		t.Setenv("MYAPP_PORT", "9090")
+
+		cfg, err := NewBuilder().
+			WithDefaults(appConfig{Test: "default", Port: 8080}).
+			WithAppName("myapp").
+			Build()
+		require.NoError(t, err)
+		val, _ := cfg.Get("test")
+		assert.Equal(t, "cwdvalue", val)
+		port, _ := cfg.Get("port")
+		assert.Equal(t, "9090", port)
+
+		// The discovery env var uses the same prefix
+		envFile := filepath.Join(t.TempDir(), "env.toml")
+		require.NoError(t, os.WriteFile(envFile, []byte(`test = "envfile"`), 0644))
+		t.Setenv("MYAPP_CONFIG", envFile)
+
+		cfg, err = NewBuilder().
+			WithDefaults(appConfig{Test: "default", Port: 8080}).
+			WithAppName("myapp").
+			Build()
+		require.NoError(t, err)
+		val, _ = cfg.Get("test")
+		assert.Equal(t, "envfile", val)
+
+		// Explicit setters override in any order
+		t.Setenv("OTHER_PORT", "7070")
+		explicitFile := filepath.Join(t.TempDir(), "explicit.toml")
+		require.NoError(t, os.WriteFile(explicitFile, []byte(`test = "explicit"`), 0644))
+
+		cfg, err = NewBuilder().
+			WithDefaults(appConfig{Test: "default", Port: 8080}).
+			WithEnvPrefix("OTHER_").
+			WithFile(explicitFile).
+			WithAppName("myapp").
+			Build()
+		require.NoError(t, err)
+		val, _ = cfg.Get("test")
+		assert.Equal(t, "explicit", val)
+		port, _ = cfg.Get("port")
+		assert.Equal(t, "7070", port)
+
+		// An explicit discovery that finds nothing disables the app name discovery
+		cfg, err = NewBuilder().
+			WithDefaults(appConfig{Test: "default", Port: 8080}).
+			WithFileDiscovery(FileDiscoveryOptions{Name: "other", Extensions: []string{".toml"}, UseCurrentDir: true}).
+			WithAppName("myapp").
+			Build()
+		require.NoError(t, err)
+		val, _ = cfg.Get("test")
+		assert.Equal(t, "default", val)
+	})
+}
+
+// TestProfiles tests profile overlays selected by environment variable or LoadProfile
+func TestProfiles(t *testing.T) {
+	type AppConfig struct {
+		Server struct {
+			Host string `toml:"host"`
+			Port int64  `toml:"port"`
+		} `toml:"server"`
+		Debug bool `toml:"debug"`
+	}
+
+	tmpDir := t.TempDir()
+	base := filepath.Join(tmpDir, "config.toml")
+	require.NoError(t, os.WriteFile(base, []byte("debug = true\n[server]\nhost = \"base\"\nport = 8080\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "config.prod.toml"), []byte("debug = false\n[server]\nhost = \"prod\"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "config.dev.toml"), []byte("[server]\nport = 3000\n"), 0644))
+
+	t.Run("ProdOverlayWins", func(t *testing.T) {
+		t.Setenv("APP_ENV", "prod")
+		cfg, err := NewBuilder().
+			WithDefaults(&AppConfig{}).
+			WithProfile("APP_ENV", base).
+			WithArgs(nil).
+			Build()
+		require.NoError(t, err)
+		assert.Equal(t, "prod", cfg.Profile())
+
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "prod", host)
+		debug, _ := cfg.Get("debug")
+		assert.Equal(t, false, debug)
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(8080), port, "keys absent from the overlay keep the base value")
+	})
+
+	t.Run("DevOverlay", func(t *testing.T) {
+		t.Setenv("APP_ENV", "dev")
+		cfg, err := NewBuilder().
+			WithDefaults(&AppConfig{}).
+			WithProfile("APP_ENV", base).
+			WithArgs(nil).
+			Build()
+		require.NoError(t, err)
+
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "base", host)
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(3000), port)
+	})
+
+	t.Run("MissingOverlaySkipped", func(t *testing.T) {
+		t.Setenv("APP_ENV", "staging")
+		cfg, err := NewBuilder().
+			WithDefaults(&AppConfig{}).
+			WithProfile("APP_ENV", base).
+			WithArgs(nil).
+			Build()
+		require.NoError(t, err)
+
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "base", host)
+	})
+
+	t.Run("NoProfile", func(t *testing.T) {
+		t.Setenv("APP_ENV", "")
+		cfg, err := NewBuilder().
+			WithDefaults(&AppConfig{}).
+			WithProfile("APP_ENV", base).
+			WithArgs(nil).
+			Build()
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Profile())
+
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "base", host)
+	})
+
+	t.Run("LoadProfileSwitches", func(t *testing.T) {
+		t.Setenv("APP_ENV", "")
+		cfg, err := NewBuilder().
+			WithDefaults(&AppConfig{}).
+			WithProfile("APP_ENV", base).
+			WithArgs(nil).
+			Build()
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.LoadProfile("prod"))
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "prod", host)
+
+		require.NoError(t, cfg.LoadProfile("dev"))
+		host, _ = cfg.Get("server.host")
+		assert.Equal(t, "base", host, "switching profiles drops the previous overlay")
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(3000), port)
+	})
+
+	t.Run("InvalidProfile", func(t *testing.T) {
+		t.Setenv("APP_ENV", "../prod")
+		_, err := NewBuilder().
+			WithDefaults(&AppConfig{}).
+			WithProfile("APP_ENV", base).
+			Build()
+		assert.Error(t, err)
+
+		t.Setenv("APP_ENV", "")
+		cfg, err := NewBuilder().
+			WithDefaults(&AppConfig{}).
+			WithProfile("APP_ENV", base).
+			WithArgs(nil).
+			Build()
+		require.NoError(t, err)
+		assert.Error(t, cfg.LoadProfile("a/b"))
+	})
+}
+
+// TestBuilderWithReader tests loading embedded configuration content
+func TestBuilderWithReader(t *testing.T) {
+	type AppConfig struct {
+		Host  string `yaml:"host"`
+		Port  int    `yaml:"port"`
+		Debug bool   `yaml:"debug"`
+	}
+
+	embedded := []byte("host: embedded.local\nport: 7000\ndebug: true\n")
+
+	t.Run("EmbeddedYAMLDefaults", func(t *testing.T) {
+		target := &AppConfig{Host: "localhost", Port: 8080}
+		cfg, err := NewBuilder().
+			WithTarget(target).
+			WithTagName("yaml").
+			WithReader(bytes.NewReader(embedded), "yaml").
+			WithArgs([]string{}).
+			Build()
+		require.NoError(t, err)
+
+		result, err := cfg.AsStruct()
+		require.NoError(t, err)
+		app := result.(*AppConfig)
+		assert.Equal(t, "embedded.local", app.Host)
+		assert.Equal(t, 7000, app.Port)
+		assert.True(t, app.Debug)
+
+		src, exists := cfg.GetSource("host", SourceFile)
+		assert.True(t, exists)
+		assert.Equal(t, "embedded.local", src)
+	})
+
+	t.Run("DiskFileOverlaysEmbedded", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte("port: 9000\n"), 0644))
+
+		cfg, err := NewBuilder().
+			WithDefaults(&AppConfig{Host: "localhost", Port: 8080}).
+			WithTagName("yaml").
+			WithBytes(embedded, "yaml").
+			WithFile(configFile).
+			WithArgs([]string{}).
+			Build()
+		require.NoError(t, err)
+
+		port, err := GetTyped[int](cfg, "port")
+		require.NoError(t, err)
+		assert.Equal(t, 9000, port)
+
+		// Keys absent from the disk file keep the embedded value
+		host, _ := cfg.Get("host")
+		assert.Equal(t, "embedded.local", host)
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		_, err := NewBuilder().
+			WithBytes(embedded, "xml").
+			Build()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported file format")
+	})
+
+	t.Run("LoadReader", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.port", int64(8080))
+
+		err := cfg.LoadReader(strings.NewReader("[server]\nport = 9090\n"), "toml")
+		require.NoError(t, err)
+
+		val, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(9090), val)
+	})
+}
+
+// TestBuilderWithAutoEnv tests recorded env var mapping
+func TestBuilderWithAutoEnv(t *testing.T) {
+	type AppConfig struct {
+		Server struct {
+			Host string `toml:"host"`
+			Port int64  `toml:"port"`
+		} `toml:"server"`
+		APIKey string `toml:"api_key" env:"CUSTOM_API_KEY"`
+	}
+
+	os.Setenv("AUTO_SERVER_PORT", "9090")
+	defer os.Unsetenv("AUTO_SERVER_PORT")
+
+	cfg, err := NewBuilder().
+		WithDefaults(&AppConfig{}).
+		WithAutoEnv("AUTO_").
+		WithArgs([]string{}).
+		Build()
+	require.NoError(t, err)
+
+	// Set variable is picked up
+	port, _ := cfg.Get("server.port")
+	assert.Equal(t, "9090", port)
+
+	// Mapping covers all paths, explicit tags keep their names
+	mapping := cfg.EnvMapping()
+	assert.Equal(t, map[string]string{
+		"server.host": "AUTO_SERVER_HOST",
+		"server.port": "AUTO_SERVER_PORT",
+		"api_key":     "CUSTOM_API_KEY",
+	}, mapping)
+
+	discovered := cfg.DiscoverEnv("AUTO_")
+	assert.Equal(t, map[string]string{"server.port": "AUTO_SERVER_PORT"}, discovered)
+
+	// Validate reports the env var operators should set
+	err = cfg.Validate("api_key")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "api_key (env CUSTOM_API_KEY)")
 }
 
 func TestBuilderWithTypedValidator(t *testing.T) {
@@ -361,4 +707,102 @@ func TestBuilderWithTypedValidator(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "typed validator signature")
 	})
+}
+
+// TestBuilderFieldErrors tests collecting field errors from several validators
+func TestBuilderFieldErrors(t *testing.T) {
+	type Cfg struct {
+		Host string `toml:"host"`
+		Port int    `toml:"port"`
+		Mode string `toml:"mode"`
+	}
+
+	t.Run("MultipleFieldErrors", func(t *testing.T) {
+		_, err := NewBuilder().
+			WithDefaults(&Cfg{Port: 80}).
+			WithArgs(nil).
+			WithValidator(func(c *Config) error {
+				var v MultiValidator
+				if host, _ := c.Get("host"); host == "" {
+					v.Add("host", "is required")
+				}
+				if port, _ := c.Get("port"); port.(int) < 1024 {
+					v.Addf("port", "must be >= 1024, got %d", port)
+				}
+				return v.Err()
+			}).
+			Build()
+
+		require.Error(t, err)
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, []FieldError{
+			{Path: "host", Message: "is required"},
+			{Path: "port", Message: "must be >= 1024, got 80"},
+		}, verr.Fields)
+		assert.Contains(t, err.Error(), "host: is required")
+		assert.Contains(t, err.Error(), "port: must be >= 1024, got 80")
+	})
+
+	t.Run("MergedAcrossValidators", func(t *testing.T) {
+		target := &Cfg{Host: "localhost", Port: 80, Mode: "fast"}
+		_, err := NewBuilder().
+			WithTarget(target).
+			WithArgs(nil).
+			WithValidator(func(c *Config) error {
+				var v MultiValidator
+				v.Add("port", "is privileged")
+				return v.Err()
+			}).
+			WithTypedValidator(func(c *Cfg) error {
+				var v MultiValidator
+				if c.Mode != "safe" {
+					v.Addf("mode", "unknown mode %q", c.Mode)
+				}
+				return v.Err()
+			}).
+			Build()
+
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Len(t, verr.Fields, 2)
+		assert.Equal(t, "port", verr.Fields[0].Path)
+		assert.Equal(t, "mode", verr.Fields[1].Path)
+	})
+
+	t.Run("PlainErrorStopsBuild", func(t *testing.T) {
+		called := false
+		_, err := NewBuilder().
+			WithDefaults(&Cfg{}).
+			WithArgs(nil).
+			WithValidator(func(c *Config) error { return fmt.Errorf("fatal") }).
+			WithValidator(func(c *Config) error { called = true; return nil }).
+			Build()
+
+		assert.ErrorContains(t, err, "fatal")
+		assert.False(t, called)
+	})
+
+	t.Run("PlainErrorKeepsFieldErrors", func(t *testing.T) {
+		_, err := NewBuilder().
+			WithTarget(&Cfg{Port: 80}).
+			WithArgs(nil).
+			WithValidator(func(c *Config) error {
+				var v MultiValidator
+				v.Add("port", "is privileged")
+				return v.Err()
+			}).
+			WithTypedValidator(func(c *Cfg) error { return fmt.Errorf("fatal") }).
+			Build()
+
+		assert.ErrorContains(t, err, "fatal")
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, []FieldError{{Path: "port", Message: "is privileged"}}, verr.Fields)
+	})
+
+	t.Run("NoErrors", func(t *testing.T) {
+		var v MultiValidator
+		assert.NoError(t, v.Err())
+	})
 }
\ No newline at end of file
@@ -7,12 +7,19 @@ package config
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
 )
 
-// Max config item value size to prevent misuse
+// MaxValueSize is the default limit on string value size, to prevent misuse.
+// Change it per instance with SetMaxValueSize.
 const MaxValueSize = 1024 * 1024 // 1MB
 
 // Errors
@@ -27,15 +34,101 @@ var (
 	// TODO: use in loader:loadEnv or remove
 	ErrEnvParse = errors.New("failed to parse environment variables")
 
-	// ErrValueSize indicates a value larger than MaxValueSize
-	ErrValueSize = fmt.Errorf("value size exceeds maximum %d bytes", MaxValueSize)
+	// ErrValueSize indicates a value larger than the configured maximum (MaxValueSize by default)
+	ErrValueSize = errors.New("value size exceeds maximum")
+
+	// ErrFrozen indicates a modification attempted after Freeze
+	ErrFrozen = errors.New("configuration is frozen")
+
+	// ErrPathNotRegistered indicates an operation on a path that is not registered.
+	// It is returned wrapped in a *PathError carrying the path.
+	ErrPathNotRegistered = errors.New("path not registered")
+
+	// ErrInvalidPathSegment indicates a path segment that is not a valid key.
+	// It is returned wrapped in a *PathError carrying the path and segment.
+	ErrInvalidPathSegment = errors.New("invalid path segment")
+
+	// ErrInvalidSource indicates a Source argument that is not a known source
+	ErrInvalidSource = errors.New("invalid source")
 )
 
+// StrictError reports configuration keys that do not match any registered path
+type StrictError struct {
+	Origin string   // Where the keys were found, e.g. the config file path
+	Keys   []string // Unknown dotted paths, sorted
+}
+
+// Error implements the error interface
+func (e *StrictError) Error() string {
+	return fmt.Sprintf("unknown configuration keys in %s: %s", e.Origin, strings.Join(e.Keys, ", "))
+}
+
+// PathError reports a problem with a configuration path. Err is ErrPathNotRegistered
+// or ErrInvalidPathSegment, so callers can match it with errors.Is.
+type PathError struct {
+	Path    string // Path as given by the caller
+	Segment string // Offending segment, set with ErrInvalidPathSegment
+	Err     error
+}
+
+// Error implements the error interface
+func (e *PathError) Error() string {
+	switch e.Err {
+	case ErrPathNotRegistered:
+		return fmt.Sprintf("path %s is not registered", e.Path)
+	case ErrInvalidPathSegment:
+		return fmt.Sprintf("invalid path segment %q in path %q", e.Segment, e.Path)
+	default:
+		return fmt.Sprintf("path %s: %v", e.Path, e.Err)
+	}
+}
+
+// Unwrap returns the underlying sentinel error
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// errNotRegistered returns a PathError for an unregistered path
+func errNotRegistered(path string) error {
+	return &PathError{Path: path, Err: ErrPathNotRegistered}
+}
+
+// errInvalidSegment returns a PathError for an invalid segment of path
+func errInvalidSegment(path, segment string) error {
+	return &PathError{Path: path, Segment: segment, Err: ErrInvalidPathSegment}
+}
+
 // configItem holds configuration values from different sources
 type configItem struct {
 	defaultValue any
-	values       map[Source]any // Values from each source
-	currentValue any            // Computed value based on precedence
+	values       map[Source]any       // Values from each source
+	currentValue any                  // Computed value based on precedence
+	usage        string               // Description from the usage struct tag
+	required     bool                 // Set by RegisterRequired or the required struct tag
+	requiredIf   []requiredCondition  // Added by RegisterRequiredIf
+	sensitive    bool                 // Set by the sensitive struct tag; hides the default in DocumentEnv
+	lazyDefault  *lazyDefault         // Set by RegisterFunc until its result becomes defaultValue
+	setTimes     map[Source]time.Time // When each source value was last set
+	untyped      bool                 // Registered from an interface-typed struct field; any value type is accepted
+	cell         *valueCell           // Publishes currentValue to lock-free readers; set by storeItem
+}
+
+// setSource stores a source value and records when it was set
+func (item *configItem) setSource(source Source, value any, at time.Time) {
+	if item.values == nil {
+		item.values = make(map[Source]any)
+	}
+	if item.setTimes == nil {
+		item.setTimes = make(map[Source]time.Time)
+	}
+	item.values[source] = value
+	item.setTimes[source] = at
+}
+
+// unsetSource removes a source value and its timestamp
+func (item *configItem) unsetSource(source Source) {
+	delete(item.values, source)
+	delete(item.setTimes, source)
 }
 
 // structCache manages the typed representation of configuration
@@ -47,11 +140,36 @@ type structCache struct {
 	mu         sync.RWMutex
 }
 
+// DefaultMaxDepth is the nesting limit for file data when SecurityOptions.MaxDepth is 0
+const DefaultMaxDepth = 32
+
+// DefaultMaxYAMLNodes is the limit on expanded YAML nodes when SecurityOptions.MaxYAMLNodes is 0
+const DefaultMaxYAMLNodes = 1_000_000
+
 // SecurityOptions for enhanced file loading security
 type SecurityOptions struct {
 	PreventPathTraversal bool  // Prevent ../ in paths
 	EnforceFileOwnership bool  // Unix only: ensure file owned by current user
 	MaxFileSize          int64 // Maximum config file size (0 = no limit)
+	MaxDepth             int   // Maximum nesting of tables/arrays (0 = DefaultMaxDepth, <0 = no limit)
+	MaxYAMLNodes         int64 // Maximum YAML nodes with aliases expanded (0 = DefaultMaxYAMLNodes, <0 = no limit)
+	// AllowedDir, when set, confines config files to this directory after resolving symlinks
+	AllowedDir string
+}
+
+// valueCell holds a path's current value for lock-free reads. Writers store the new
+// value under the write lock whenever they store the item.
+type valueCell struct {
+	value atomic.Pointer[any]
+	lazy  atomic.Pointer[lazyDefault] // Set while the value awaits a RegisterFunc default
+}
+
+// load returns the published value, nil if none was stored
+func (v *valueCell) load() any {
+	if p := v.value.Load(); p != nil {
+		return *p
+	}
+	return nil
 }
 
 // Config manages application configuration. It can be used in two primary ways:
@@ -62,13 +180,30 @@ type Config struct {
 	tagName      string
 	fileFormat   string // Separate from tagName: "toml", "json", "yaml", or "auto"
 	securityOpts *SecurityOptions
+	maxValueSize int64 // String value size limit in bytes; 0 is unlimited
 	mutex        sync.RWMutex
-	options      LoadOptions    // Current load options
-	fileData     map[string]any // Cached file data
-	envData      map[string]any // Cached env data
-	cliData      map[string]any // Cached CLI data
+	options      LoadOptions               // Current load options
+	fileData     map[string]any            // Cached file data
+	envData      map[string]any            // Cached env data
+	cliData      map[string]any            // Cached CLI data
+	customData   map[Source]map[string]any // Sources added with RegisterSource and their cached data
+	fileBase     map[string]any            // Embedded file layer that loaded files overlay
+	profile      string                    // Selects the <base>.<profile>.<ext> overlay merged over loaded files
+	envNames     map[string]string         // Recorded env var names by path (explicit tags, auto env)
+	aliases      map[string]string         // Flag aliases to their target paths
+	remotes      map[string]*remote        // Remote sources by name, added with AddRemoteSource
 	version      atomic.Int64
+	cells        atomic.Pointer[map[string]*valueCell] // Immutable path index read by Get
+	pathsChanged bool                                  // Paths were added or removed since cells was built
 	structCache  *structCache
+	frozen       atomic.Bool            // Set by Freeze; rejects writes
+	allowReload  atomic.Bool            // FreezeOptions.AllowReload
+	log          atomic.Pointer[Logger] // Set by SetLogger; nil discards diagnostics
+	lazyPending  atomic.Int64           // Items whose RegisterFunc default awaits evaluation or recording
+
+	// Application decode hooks added with AddDecodeHook
+	preDecodeHooks  []mapstructure.DecodeHookFunc
+	postDecodeHooks []mapstructure.DecodeHookFunc
 
 	// File watching support
 	watcher        *watcher
@@ -86,10 +221,13 @@ func New() *Config {
 		// 	EnforceFileOwnership: false,
 		// 	MaxFileSize:          0,
 		// },
-		options:  DefaultLoadOptions(),
-		fileData: make(map[string]any),
-		envData:  make(map[string]any),
-		cliData:  make(map[string]any),
+		options:      DefaultLoadOptions(),
+		maxValueSize: MaxValueSize,
+		fileData:     make(map[string]any),
+		envData:      make(map[string]any),
+		cliData:      make(map[string]any),
+		envNames:     make(map[string]string),
+		aliases:      make(map[string]string),
 	}
 }
 
@@ -100,78 +238,203 @@ func NewWithOptions(opts LoadOptions) *Config {
 	return c
 }
 
-// SetLoadOptions updates the load options and recomputes current values
+// FreezeOptions controls what remains permitted after Freeze
+type FreezeOptions struct {
+	// AllowReload permits Load* calls and watcher reloads to update source values
+	AllowReload bool
+}
+
+// Freeze makes the configuration read-only. Afterwards Set, SetSource, Reset,
+// Register*, Unregister, and loads (including watcher reloads) return ErrFrozen.
+// Reads and Scan are unaffected. There is no unfreeze; Clone returns an unfrozen copy.
+func (c *Config) Freeze() {
+	c.FreezeWithOptions(FreezeOptions{})
+}
+
+// FreezeWithOptions is like Freeze but allows reloads when opts.AllowReload is set
+func (c *Config) FreezeWithOptions(opts FreezeOptions) {
+	c.allowReload.Store(opts.AllowReload)
+	c.frozen.Store(true)
+}
+
+// IsFrozen returns true if Freeze has been called
+func (c *Config) IsFrozen() bool {
+	return c.frozen.Load()
+}
+
+// checkWritable returns ErrFrozen if the config is frozen
+func (c *Config) checkWritable() error {
+	if c.frozen.Load() {
+		return ErrFrozen
+	}
+	return nil
+}
+
+// checkLoadable returns ErrFrozen if the config is frozen without AllowReload
+func (c *Config) checkLoadable() error {
+	if c.frozen.Load() && !c.allowReload.Load() {
+		return ErrFrozen
+	}
+	return nil
+}
+
+// SetLoadOptions updates the load options and recomputes current values.
+// Only a change of Sources takes effect immediately. Env options apply to the next
+// load, unless opts.ReloadEnv is set; file options such as Strict apply to the next
+// file load or reload.
 func (c *Config) SetLoadOptions(opts LoadOptions) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 
+	c.mutex.Lock()
+	precedenceChanged := !slices.Equal(c.options.Sources, opts.Sources)
+	reloadEnv := opts.ReloadEnv && envOptionsChanged(c.options, opts)
 	c.options = opts
 
-	// Recompute all current values based on new precedence
+	// Current values only depend on precedence, so other option changes skip the rescan
+	if precedenceChanged {
+		c.notifyChanges(c.recomputeAll())
+		c.invalidateCache()
+	}
+	c.mutex.Unlock()
+
+	if reloadEnv {
+		return c.replaceEnv(opts)
+	}
+	return nil
+}
+
+// envOptionsChanged reports whether new env options may map paths to other variables.
+// Transform functions cannot be compared, so any set transform counts as a change.
+func envOptionsChanged(old, new LoadOptions) bool {
+	return old.EnvPrefix != new.EnvPrefix || old.EnvSeparator != new.EnvSeparator ||
+		!maps.Equal(old.EnvWhitelist, new.EnvWhitelist) ||
+		new.EnvTransform != nil || old.EnvTransform != nil
+}
+
+// replaceEnv re-reads environment variables under opts and replaces the env source,
+// notifying watchers of changed values
+func (c *Config) replaceEnv(opts LoadOptions) error {
+	found, _, err := c.readEnv(opts)
+	if err != nil {
+		return err
+	}
+	values := c.envStoredValues(found, opts)
+	oldValues := c.snapshot()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	for path, item := range c.items {
-		item.currentValue = c.computeValue(item)
-		c.items[path] = item
+		if _, exists := values[path]; !exists {
+			c.unsetItemSource(SourceEnv, path, item)
+		}
+	}
+	c.applyEnvValues(values, time.Now())
+	c.notifyChanges(c.changesSince(oldValues, ChangeCauseReload))
+	c.invalidateCache()
+	return nil
+}
+
+// RegisterSource adds a custom source that can be placed anywhere in the precedence
+// order with SetPrecedence or LoadOptions.Sources. Loads never fill a custom source;
+// set its values with SetSource or AddRemoteSource.
+func (c *Config) RegisterSource(name Source) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	if name == "" || name.Valid() {
+		return fmt.Errorf("%w: %q cannot be registered as a custom source", ErrInvalidSource, name)
 	}
 
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.customData[name]; exists {
+		return fmt.Errorf("source %q already registered", name)
+	}
+	if c.customData == nil {
+		c.customData = make(map[Source]map[string]any)
+	}
+	c.customData[name] = make(map[string]any)
 	return nil
 }
 
+// validateSource returns ErrInvalidSource, naming the source, if it is neither
+// built-in nor registered with RegisterSource. Must be called with the lock held.
+func (c *Config) validateSource(source Source) error {
+	if source.Valid() {
+		return nil
+	}
+	if _, custom := c.customData[source]; custom {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidSource, source)
+}
+
+// sourceData returns the cached data of a source, or nil for SourceDefault and
+// unknown sources. Must be called with the lock held.
+func (c *Config) sourceData(source Source) map[string]any {
+	switch source {
+	case SourceFile:
+		return c.fileData
+	case SourceEnv:
+		return c.envData
+	case SourceCLI:
+		return c.cliData
+	}
+	return c.customData[source]
+}
+
+// clearSourceData empties the cached data of a source. Must be called with the lock held.
+func (c *Config) clearSourceData(source Source) {
+	switch source {
+	case SourceFile:
+		c.fileData = make(map[string]any)
+	case SourceEnv:
+		c.envData = make(map[string]any)
+	case SourceCLI:
+		c.cliData = make(map[string]any)
+	default:
+		if _, custom := c.customData[source]; custom {
+			c.customData[source] = make(map[string]any)
+		}
+	}
+}
+
 // SetPrecedence updates source precedence with validation
 func (c *Config) SetPrecedence(sources ...Source) error {
-	// Validate all required sources present
-	required := map[Source]bool{
-		SourceDefault: false,
-		SourceFile:    false,
-		SourceEnv:     false,
-		SourceCLI:     false,
+	if err := c.checkWritable(); err != nil {
+		return err
 	}
 
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Validate all sources are built-in or registered
 	for _, s := range sources {
-		if _, valid := required[s]; !valid {
-			return fmt.Errorf("invalid source: %s", s)
+		if err := c.validateSource(s); err != nil {
+			return err
 		}
-		required[s] = true
 	}
 
 	// Ensure SourceDefault is included
-	if !required[SourceDefault] {
+	if !slices.Contains(sources, SourceDefault) {
 		sources = append(sources, SourceDefault)
 	}
 
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
 	// FIXED: Check if precedence actually changed
 	oldPrecedence := c.options.Sources
 	if reflect.DeepEqual(oldPrecedence, sources) {
 		return nil // No change needed
 	}
 
-	// Track value changes before updating precedence
-	oldValues := make(map[string]any)
-	for path, item := range c.items {
-		oldValues[path] = item.currentValue
-	}
-
 	// Update precedence
 	c.options.Sources = sources
 
-	// Recompute values and track changes
-	changedPaths := make([]string, 0)
-	for path, item := range c.items {
-		item.currentValue = c.computeValue(item)
-		if !reflect.DeepEqual(oldValues[path], item.currentValue) {
-			changedPaths = append(changedPaths, path)
-		}
-		c.items[path] = item
-	}
-
-	// Notify watchers of precedence change
-	if c.watcher != nil && len(changedPaths) > 0 {
-		for _, path := range changedPaths {
-			c.watcher.notifyWatchers("precedence:" + path)
-		}
-	}
+	// Recompute values and notify watchers of changes
+	c.notifyChanges(c.recomputeAll())
 
 	c.invalidateCache()
 	return nil
@@ -187,6 +450,57 @@ func (c *Config) GetPrecedence() []Source {
 	return result
 }
 
+// notifyChanges sends change notifications to watchers, if any, in the given order.
+// Must be called with the lock held.
+func (c *Config) notifyChanges(changes []ChangeEvent) {
+	if c.watcher == nil {
+		return
+	}
+	for _, change := range changes {
+		c.watcher.notifyChange(change)
+	}
+}
+
+// recomputeAll re-resolves every item after a precedence change and returns the
+// changes to current values, sorted by path. Items with at most a default value cannot
+// change and are skipped. Must be called with the write lock held.
+func (c *Config) recomputeAll() []ChangeEvent {
+	var changes []ChangeEvent
+	for path, item := range c.items {
+		if len(item.values) == 0 {
+			continue
+		}
+		value := c.computeValue(item)
+		if reflect.DeepEqual(item.currentValue, value) {
+			continue
+		}
+		changes = append(changes, ChangeEvent{
+			Path:     path,
+			OldValue: item.currentValue,
+			NewValue: value,
+			Cause:    ChangeCausePrecedence,
+		})
+		item.currentValue = value
+		c.storeItem(path, item)
+	}
+	slices.SortFunc(changes, func(a, b ChangeEvent) int { return strings.Compare(a.Path, b.Path) })
+	return changes
+}
+
+// changesSince compares current values with old, a snapshot taken before a reload,
+// and returns the differences with the given cause, sorted by path. Must be called
+// with the lock held.
+func (c *Config) changesSince(old map[string]any, cause string) []ChangeEvent {
+	var changes []ChangeEvent
+	for path, item := range c.items {
+		if !reflect.DeepEqual(old[path], item.currentValue) {
+			changes = append(changes, ChangeEvent{Path: path, OldValue: old[path], NewValue: item.currentValue, Cause: cause})
+		}
+	}
+	slices.SortFunc(changes, func(a, b ChangeEvent) int { return strings.Compare(a.Path, b.Path) })
+	return changes
+}
+
 // computeValue determines the current value based on precedence
 func (c *Config) computeValue(item configItem) any {
 	// Check sources in precedence order
@@ -196,7 +510,11 @@ func (c *Config) computeValue(item configItem) any {
 		}
 	}
 
-	// No source had a value, use default
+	// No source had a value, use default; an unevaluated RegisterFunc default is
+	// computed outside the lock by the next read
+	if item.lazyDefault != nil && item.lazyDefault.done.Load() {
+		return item.lazyDefault.value
+	}
 	return item.defaultValue
 }
 
@@ -217,6 +535,20 @@ func (c *Config) SetFileFormat(format string) error {
 	return nil
 }
 
+// SetMaxValueSize sets the maximum size in bytes of string values accepted by Set,
+// SetSource, and environment loading. Zero or a negative value removes the limit.
+func (c *Config) SetMaxValueSize(n int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.maxValueSize = max(n, 0)
+}
+
+// exceedsValueSize reports whether s is over the value size limit.
+// Must be called with the lock held.
+func (c *Config) exceedsValueSize(s string) bool {
+	return c.maxValueSize > 0 && int64(len(s)) > c.maxValueSize
+}
+
 // SetSecurityOptions configures security checks for file loading
 func (c *Config) SetSecurityOptions(opts SecurityOptions) {
 	c.mutex.Lock()
@@ -225,30 +557,159 @@ func (c *Config) SetSecurityOptions(opts SecurityOptions) {
 }
 
 // Get retrieves a configuration value using the path and indicator if the path was registered
+// Reads are lock-free. Slice and map values are returned as copies, so callers can
+// modify them without changing the config.
 func (c *Config) Get(path string) (any, bool) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	base, rest, indexed := splitIndexedPath(path)
+	cells := c.valueCells()
+	target := path
+	if indexed {
+		target = base
+	}
+	if cell, registered := cells[target]; registered && cell.lazy.Load() != nil {
+		c.resolveLazyDefaults(target) // Updates the same cell
+	}
 
-	item, registered := c.items[path]
+	if cell, registered := cells[path]; registered {
+		return copyValue(cell.load()), true
+	}
+
+	// Element access such as "server.hosts[0]"
+	if !indexed {
+		return nil, false
+	}
+	cell, registered := cells[base]
 	if !registered {
 		return nil, false
 	}
+	segments, err := parseIndexedPath(rest)
+	if err != nil {
+		return nil, false
+	}
+	elem, found := getIndexedValue(cell.load(), segments)
+	return copyValue(elem), found
+}
+
+// Lookup is like Get but separates a missing registration from a nil value.
+// registered reports whether the path (or element path) exists; hasValue is false
+// when the resolved value is nil, such as an optional field with no default or override.
+func (c *Config) Lookup(path string) (value any, registered bool, hasValue bool) {
+	value, registered = c.Get(path)
+	return value, registered, registered && value != nil
+}
+
+// Has reports whether path is registered
+func (c *Config) Has(path string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	_, registered := c.items[path]
+	return registered
+}
+
+// IsSet reports whether any source (file, env, CLI, or a remote) provides a value for
+// path. It is false for unregistered paths and for paths resolving to their default.
+func (c *Config) IsSet(path string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, value := range c.items[path].values {
+		if value != nil {
+			return true
+		}
+	}
+	return false
+}
 
-	return item.currentValue, true
+// valueCells returns the published path-to-cell index. Writers replace it when paths
+// are added or removed; before the first read needs it, it is built here once under
+// the write lock, so registering many paths at startup does not rebuild it each time.
+func (c *Config) valueCells() map[string]*valueCell {
+	if cells := c.cells.Load(); cells != nil {
+		return *cells
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if cells := c.cells.Load(); cells != nil {
+		return *cells
+	}
+	c.publishCells()
+	return *c.cells.Load()
 }
 
-// GetSource retrieves a value from a specific source
+// publishCells builds and publishes a new path-to-cell index. Must be called with the
+// write lock held.
+func (c *Config) publishCells() {
+	cells := make(map[string]*valueCell, len(c.items))
+	for path, item := range c.items {
+		cells[path] = item.cell
+	}
+	c.cells.Store(&cells)
+	c.pathsChanged = false
+}
+
+// storeItem stores item at path and publishes its current value to lock-free readers.
+// A new item gets a cell, and the path index is rebuilt by the next invalidateCache.
+// Must be called with the write lock held.
+func (c *Config) storeItem(path string, item configItem) {
+	if existing, exists := c.items[path]; exists && item.cell == nil {
+		item.cell = existing.cell
+	}
+	if item.cell == nil {
+		item.cell = &valueCell{}
+		c.pathsChanged = true
+	}
+	value := item.currentValue
+	item.cell.value.Store(&value)
+	c.items[path] = item
+
+	// Count items awaiting their RegisterFunc default, so reads skip resolution when none do
+	var lazy *lazyDefault
+	if c.lazyDefaultUnresolved(item) {
+		lazy = item.lazyDefault
+	}
+	if old := item.cell.lazy.Swap(lazy); (old == nil) != (lazy == nil) {
+		if lazy != nil {
+			c.lazyPending.Add(1)
+		} else {
+			c.lazyPending.Add(-1)
+		}
+	}
+}
+
+// deleteItem removes the item at path. Must be called with the write lock held.
+func (c *Config) deleteItem(path string) {
+	item, exists := c.items[path]
+	if !exists {
+		return
+	}
+	if item.cell.lazy.Load() != nil {
+		c.lazyPending.Add(-1)
+	}
+	delete(c.items, path)
+	c.pathsChanged = true
+}
+
+// GetSource retrieves a value from a specific source. Like Get, it returns copies of
+// slice and map values.
 func (c *Config) GetSource(path string, source Source) (any, bool) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
 	item, registered := c.items[path]
 	if !registered {
+		if base, rest, ok := c.resolveIndexedPath(path); ok {
+			if val, exists := c.items[base].values[source]; exists {
+				elem, found := getIndexedValue(val, rest)
+				return copyValue(elem), found
+			}
+		}
 		return nil, false
 	}
 
 	val, exists := item.values[source]
-	return val, exists
+	return copyValue(val), exists
 }
 
 // Set updates a configuration value for the given path.
@@ -259,43 +720,222 @@ func (c *Config) Set(path string, value any) error {
 	return c.SetSource(c.options.Sources[0], path, value)
 }
 
-// SetSource sets a value for a specific source
-func (c *Config) SetSource(source Source, path string, value any) error {
+// CompareAndSwap sets path to new in the highest priority source, like Set, but only if
+// the resolved value still equals old (compared with reflect.DeepEqual).
+// Returns whether the swap happened. The comparison and write happen under one write lock.
+func (c *Config) CompareAndSwap(path string, old, new any) (bool, error) {
+	if err := c.checkWritable(); err != nil {
+		return false, err
+	}
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
 	item, registered := c.items[path]
 	if !registered {
-		return fmt.Errorf("path %s is not registered", path)
+		return false, errNotRegistered(path)
+	}
+	if str, ok := new.(string); ok && c.exceedsValueSize(str) {
+		return false, ErrValueSize
+	}
+	if !reflect.DeepEqual(item.currentValue, old) {
+		return false, nil
+	}
+
+	c.setItemSource(c.options.Sources[0], path, item, new)
+	c.invalidateCache()
+	return true, nil
+}
+
+// SetDefault replaces the default value of a registered path, keeping all source values.
+// The resolved value changes only when no source provides a value for the path.
+func (c *Config) SetDefault(path string, value any) error {
+	if err := c.checkWritable(); err != nil {
+		return err
 	}
 
-	if str, ok := value.(string); ok && len(str) > MaxValueSize {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, registered := c.items[path]
+	if !registered {
+		return errNotRegistered(path)
+	}
+
+	item.defaultValue = value
+	item.lazyDefault = nil
+	item.currentValue = c.computeValue(item)
+	c.storeItem(path, item)
+	c.invalidateCache()
+	return nil
+}
+
+// GetRawSource returns a source's value for path as text. Strings, such as env and
+// CLI values, are returned as stored; parsed values, such as those from files, use
+// the same text form as flag defaults: comma-joined slices, durations like "30s",
+// and text marshalers' output. Returns false if the source has no value for path.
+func (c *Config) GetRawSource(path string, source Source) (string, bool) {
+	value, exists := c.GetSource(path, source)
+	if !exists {
+		return "", false
+	}
+	return rawText(value), true
+}
+
+// SourceTimestamp returns when source last set a value for path, by a load, a
+// SetSource or Set call, or a remote update. Loads record the time for every path
+// they set, even if its value is unchanged. Returns false if the source has no
+// value for path.
+func (c *Config) SourceTimestamp(path string, source Source) (time.Time, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	item, registered := c.items[path]
+	if !registered {
+		return time.Time{}, false
+	}
+	at, exists := item.setTimes[source]
+	return at, exists
+}
+
+// rawText returns the text form of a source value, joining slice elements with commas
+func rawText(value any) string {
+	if text, ok := flagDefault(value); ok {
+		return text
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice {
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			parts[i] = rawText(rv.Index(i).Interface())
+		}
+		return strings.Join(parts, ",")
+	}
+	return textDefault(value)
+}
+
+// SetSource sets a value for a specific source
+func (c *Config) SetSource(source Source, path string, value any) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.validateSource(source); err != nil {
+		return err
+	}
+
+	if str, ok := value.(string); ok && c.exceedsValueSize(str) {
 		return ErrValueSize
 	}
 
-	if item.values == nil {
-		item.values = make(map[Source]any)
+	item, registered := c.items[path]
+	if !registered {
+		// Element write such as "server.hosts[1]", growing the slice as needed
+		if base, rest, ok := c.resolveIndexedPath(path); ok {
+			if err := c.setIndexedSource(source, base, rest, value); err != nil {
+				return err
+			}
+			c.invalidateCache()
+			return nil
+		}
+		// Subtree write such as "servers" with a map covering registered children
+		if c.hasChildren(path) {
+			if err := c.setSubtreeSource(source, path, value); err != nil {
+				return err
+			}
+			c.invalidateCache()
+			return nil
+		}
+		return errNotRegistered(path)
 	}
 
-	item.values[source] = value
+	c.setItemSource(source, path, item, value)
+	c.invalidateCache() // Invalidate cache after changes
+	return nil
+}
+
+// setItemSource stores a source value for a registered item and updates the source cache.
+// Must be called with the lock held.
+func (c *Config) setItemSource(source Source, path string, item configItem, value any) {
+	item.setSource(source, value, time.Now())
 	item.currentValue = c.computeValue(item)
-	c.items[path] = item
+	c.storeItem(path, item)
 
 	// Update source cache
-	switch source {
-	case SourceFile:
-		c.fileData[path] = value
-	case SourceEnv:
-		c.envData[path] = value
-	case SourceCLI:
-		c.cliData[path] = value
+	if data := c.sourceData(source); data != nil {
+		data[path] = value
 	}
+}
 
-	c.invalidateCache() // Invalidate cache after changes
+// hasChildren reports whether any registered path lies under path.
+// Must be called with the lock held.
+func (c *Config) hasChildren(path string) bool {
+	prefix := path + "."
+	for itemPath := range c.items {
+		if strings.HasPrefix(itemPath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// setSubtreeSource distributes a map or struct value over the registered paths beneath path.
+// All leaves must be registered; nothing is written otherwise. Must be called with the lock held.
+func (c *Config) setSubtreeSource(source Source, path string, value any) error {
+	leaves := make(map[string]any)
+	c.flattenSubtree(reflect.ValueOf(value), path, leaves)
+
+	for leafPath := range leaves {
+		if _, registered := c.items[leafPath]; !registered {
+			return errNotRegistered(leafPath)
+		}
+	}
+
+	for leafPath, leafValue := range leaves {
+		c.setItemSource(source, leafPath, c.items[leafPath], leafValue)
+	}
 	return nil
 }
 
-// GetSources returns all sources that have a value for the given path
+// flattenSubtree maps a nested value onto dotted paths, stopping at registered paths.
+// Must be called with the lock held.
+func (c *Config) flattenSubtree(v reflect.Value, path string, leaves map[string]any) {
+	if _, registered := c.items[path]; registered || !v.IsValid() {
+		if v.IsValid() {
+			leaves[path] = v.Interface()
+		} else {
+			leaves[path] = nil
+		}
+		return
+	}
+
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			leaves[path] = nil
+			return
+		}
+		if !isAtomicStructType(v.Type()) {
+			c.flattenSubtree(v.Elem(), path, leaves)
+			return
+		}
+	}
+
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		for _, key := range v.MapKeys() {
+			c.flattenSubtree(v.MapIndex(key), path+"."+key.String(), leaves)
+		}
+	case v.Kind() == reflect.Struct && isNestedStructType(v.Type()):
+		walkStructFields(v, path+".", "", c.tagName, func(f structField) {
+			c.flattenSubtree(f.value, f.path, leaves)
+		})
+	default:
+		leaves[path] = v.Interface()
+	}
+}
+
+// GetSources returns all sources that have a value for the given path, copying slice and map values
 func (c *Config) GetSources(path string) map[Source]any {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
@@ -307,59 +947,203 @@ func (c *Config) GetSources(path string) map[Source]any {
 
 	result := make(map[Source]any)
 	for source, value := range item.values {
-		result[source] = value
+		result[source] = copyValue(value)
 	}
 	return result
 }
 
+// GetMap returns the subtree under path as a nested map. If path itself holds a
+// map[string]any, a copy of that value is returned; otherwise the map is built from
+// the current values of registered paths beneath it. Returns false if neither applies.
+func (c *Config) GetMap(path string) (map[string]any, bool) {
+	c.resolveLazyDefaults()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if item, registered := c.items[path]; registered {
+		if m, isMap := item.currentValue.(map[string]any); isMap {
+			return copyValue(m).(map[string]any), true
+		}
+		return nil, false
+	}
+
+	result := make(map[string]any)
+	prefix := path + "."
+	for itemPath, item := range c.items {
+		if relPath, found := strings.CutPrefix(itemPath, prefix); found {
+			setNestedValue(result, relPath, copyValue(item.currentValue))
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, false
+	}
+	return result, true
+}
+
 // Reset clears all non-default values and resets to defaults
-func (c *Config) Reset() {
+func (c *Config) Reset() error {
+	return c.ResetExcept()
+}
+
+// ResetExcept clears the values of every source except those listed, for example
+// to re-apply file and env values while keeping command-line overrides
+func (c *Config) ResetExcept(keep ...Source) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	for _, source := range keep {
+		if err := c.validateSource(source); err != nil {
+			return err
+		}
+	}
+
 	// Clear source caches
-	c.fileData = make(map[string]any)
-	c.envData = make(map[string]any)
-	c.cliData = make(map[string]any)
+	cleared := append([]Source{SourceFile, SourceEnv, SourceCLI}, slices.Collect(maps.Keys(c.customData))...)
+	for _, source := range cleared {
+		if !slices.Contains(keep, source) {
+			c.clearSourceData(source)
+		}
+	}
 
-	// Reset all items to default values
+	// Reset all items to their kept values or defaults
 	for path, item := range c.items {
-		item.values = make(map[Source]any)
-		item.currentValue = item.defaultValue
-		c.items[path] = item
+		for source := range item.values {
+			if !slices.Contains(keep, source) {
+				item.unsetSource(source)
+			}
+		}
+		item.currentValue = c.computeValue(item)
+		c.storeItem(path, item)
 	}
 
 	c.invalidateCache() // Invalidate cache after changes
+	return nil
 }
 
 // ResetSource clears all values from a specific source
-func (c *Config) ResetSource(source Source) {
+func (c *Config) ResetSource(source Source) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	// Clear source cache
-	switch source {
-	case SourceFile:
-		c.fileData = make(map[string]any)
-	case SourceEnv:
-		c.envData = make(map[string]any)
-	case SourceCLI:
-		c.cliData = make(map[string]any)
+	if err := c.validateSource(source); err != nil {
+		return err
 	}
 
-	// Remove source values from all items
+	c.clearSourceData(source)
+
+	// Remove source values from the items that hold one
 	for path, item := range c.items {
-		delete(item.values, source)
+		if _, exists := item.values[source]; !exists {
+			continue
+		}
+		item.unsetSource(source)
 		item.currentValue = c.computeValue(item)
-		c.items[path] = item
+		c.storeItem(path, item)
+	}
+
+	c.invalidateCache() // Invalidate cache after changes
+	return nil
+}
+
+// UnsetSource removes the value of a specific source for a single path.
+// Returns an error if the path is not registered; no-op if the source had no value.
+func (c *Config) UnsetSource(source Source, path string) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.validateSource(source); err != nil {
+		return err
+	}
+
+	item, registered := c.items[path]
+	if !registered {
+		return errNotRegistered(path)
+	}
+
+	if _, exists := item.values[source]; !exists {
+		return nil
 	}
 
+	c.unsetItemSource(source, path, item)
 	c.invalidateCache() // Invalidate cache after changes
+	return nil
+}
+
+// unsetItemSource removes a source value from a registered item and updates the source cache.
+// Must be called with the lock held.
+func (c *Config) unsetItemSource(source Source, path string, item configItem) {
+	item.unsetSource(source)
+	item.currentValue = c.computeValue(item)
+	c.storeItem(path, item)
+
+	// Update source cache
+	delete(c.sourceData(source), path)
+}
+
+// resolveIndexedPath splits an element path like "server.hosts[1]" into its registered
+// base path and the parsed index segments. Must be called with the lock held.
+func (c *Config) resolveIndexedPath(path string) (string, []pathSegment, bool) {
+	base, rest, ok := splitIndexedPath(path)
+	if !ok {
+		return "", nil, false
+	}
+	if _, registered := c.items[base]; !registered {
+		return "", nil, false
+	}
+	segments, err := parseIndexedPath(rest)
+	if err != nil {
+		return "", nil, false
+	}
+	return base, segments, true
 }
 
-// Override Set methods to invalidate cache
+// setIndexedSource writes value into an element of a base path's value for the given source.
+// The source's existing value is used as the starting point, falling back to the current value.
+// Must be called with the write lock held.
+func (c *Config) setIndexedSource(source Source, base string, rest []pathSegment, value any) error {
+	item := c.items[base]
+
+	start, exists := item.values[source]
+	if !exists || start == nil {
+		start = item.currentValue
+	}
+
+	updated, err := setIndexedValue(start, rest, value)
+	if err != nil {
+		return fmt.Errorf("failed to set element of path %s: %w", base, err)
+	}
+
+	item.setSource(source, updated, time.Now())
+	item.currentValue = c.computeValue(item)
+	c.storeItem(base, item)
+
+	// Update source cache
+	if data := c.sourceData(source); data != nil {
+		data[base] = updated
+	}
+
+	return nil
+}
+
+// invalidateCache bumps the version after any change to registered items or values,
+// invalidating the struct cache, and republishes the path index read by Get if paths
+// were added or removed. Must be called with the write lock held, after the change.
 func (c *Config) invalidateCache() {
 	c.version.Add(1)
+	if c.pathsChanged && c.cells.Load() != nil {
+		c.publishCells()
+	}
 }
 
 // AsStruct returns the populated struct if in type-aware mode
@@ -382,6 +1166,28 @@ func (c *Config) AsStruct() (any, error) {
 	return c.structCache.target, nil
 }
 
+// RLockTarget calls fn with the WithTarget struct, refreshed if the config changed,
+// while holding the target's read lock. Use it to read a target that
+// WatchOptions.RefreshTarget updates in place; fn must not retain the pointer or call
+// AsStruct. It fails if no target is configured or the target is not a *T.
+func RLockTarget[T any](c *Config, fn func(*T)) error {
+	if c.structCache == nil || c.structCache.target == nil {
+		return fmt.Errorf("no target struct configured")
+	}
+	target, ok := c.structCache.target.(*T)
+	if !ok {
+		return fmt.Errorf("target struct is %T, not %T", c.structCache.target, (*T)(nil))
+	}
+	if _, err := c.AsStruct(); err != nil {
+		return err
+	}
+
+	c.structCache.mu.RLock()
+	defer c.structCache.mu.RUnlock()
+	fn(target)
+	return nil
+}
+
 // Target populates the provided struct with current configuration
 func (c *Config) Target(out any) error {
 	return c.Scan(out)
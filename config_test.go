@@ -7,7 +7,9 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -143,6 +145,123 @@ func TestComplexStructRegistration(t *testing.T) {
 		assert.Contains(t, err.Error(), "unsupported tag name")
 	})
 
+	t.Run("EmbeddedStructs", func(t *testing.T) {
+		type CommonOpts struct {
+			LogLevel string `toml:"log_level"`
+			Debug    bool   `toml:"debug"`
+		}
+		type limits struct {
+			MaxConns int `toml:"max_conns"`
+		}
+		type Metrics struct {
+			Enabled bool `toml:"enabled"`
+		}
+		type AppConfig struct {
+			CommonOpts
+			*limits
+			Metrics `toml:"metrics"` // Tagged embeds stay nested
+			Name    string           `toml:"name"`
+		}
+
+		cfg := New()
+		err := cfg.RegisterStruct("app.", AppConfig{
+			CommonOpts: CommonOpts{LogLevel: "info"},
+			limits:     &limits{MaxConns: 10},
+			Name:       "svc",
+		})
+		require.NoError(t, err)
+
+		paths := cfg.GetRegisteredPaths("app.")
+		assert.True(t, paths["app.log_level"])
+		assert.True(t, paths["app.debug"])
+		assert.True(t, paths["app.max_conns"])
+		assert.True(t, paths["app.metrics.enabled"])
+		assert.True(t, paths["app.name"])
+		assert.False(t, paths["app.CommonOpts.log_level"])
+
+		val, _ := cfg.Get("app.log_level")
+		assert.Equal(t, "info", val)
+		val, _ = cfg.Get("app.max_conns")
+		assert.Equal(t, 10, val)
+	})
+
+	t.Run("InlineTags", func(t *testing.T) {
+		type Limits struct {
+			MaxConns int `yaml:"max_conns"`
+		}
+		type AppConfig struct {
+			Name   string         `yaml:"name,omitempty"`
+			Limits Limits         `yaml:",inline"`
+			Extra  map[string]any `yaml:",inline"`
+			Labels map[string]any `yaml:"labels,omitempty"`
+		}
+
+		cfg := New()
+		err := cfg.RegisterStructWithTags("", AppConfig{
+			Name:   "svc",
+			Limits: Limits{MaxConns: 10},
+			Extra:  map[string]any{"region": "eu", "zone": 2},
+			Labels: map[string]any{"team": "core"},
+		}, "yaml")
+		require.NoError(t, err)
+
+		paths := cfg.GetRegisteredPaths("")
+		assert.True(t, paths["name"], "omitempty does not change the path")
+		assert.True(t, paths["max_conns"])
+		assert.True(t, paths["region"])
+		assert.True(t, paths["zone"])
+		assert.True(t, paths["labels"], "non-inline maps stay a single value")
+		assert.False(t, paths["Limits.max_conns"])
+		assert.False(t, paths["Extra"])
+
+		val, _ := cfg.Get("region")
+		assert.Equal(t, "eu", val)
+		val, _ = cfg.Get("max_conns")
+		assert.Equal(t, 10, val)
+	})
+
+	t.Run("MapOfStructs", func(t *testing.T) {
+		type Backend struct {
+			Host string `toml:"host"`
+			Port int    `toml:"port"`
+		}
+		type AppConfig struct {
+			Servers map[string]Backend `toml:"servers"`
+		}
+
+		cfg := New()
+		err := cfg.RegisterStruct("", AppConfig{Servers: map[string]Backend{
+			"web": {Host: "web.local", Port: 80},
+			"api": {Host: "api.local", Port: 8080},
+		}})
+		require.NoError(t, err)
+
+		paths := cfg.GetRegisteredPaths("")
+		assert.True(t, paths["servers.web.port"])
+		assert.True(t, paths["servers.api.host"])
+		assert.False(t, paths["servers"])
+
+		require.NoError(t, cfg.LoadCLI([]string{"--servers.web.port=9090"}))
+
+		var result AppConfig
+		require.NoError(t, cfg.Scan(&result))
+		assert.Equal(t, Backend{Host: "web.local", Port: 9090}, result.Servers["web"])
+		assert.Equal(t, Backend{Host: "api.local", Port: 8080}, result.Servers["api"])
+
+		// The whole map can be set when its keys are registered
+		err = cfg.SetSource(SourceFile, "servers", map[string]Backend{
+			"api": {Host: "api.example.com", Port: 8443},
+		})
+		require.NoError(t, err)
+		val, _ := cfg.GetSource("servers.api.port", SourceFile)
+		assert.Equal(t, 8443, val)
+
+		// Keys absent from the defaults are not registered
+		err = cfg.Set("servers", map[string]any{"db": map[string]any{"port": 5432}})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "servers.db.port is not registered")
+	})
+
 	t.Run("WithPrefix", func(t *testing.T) {
 		cfg := New()
 		err := cfg.RegisterStruct("server", defaultConfig)
@@ -152,6 +271,83 @@ func TestComplexStructRegistration(t *testing.T) {
 		assert.True(t, paths["server.name"])
 		assert.True(t, paths["server.db.host"])
 	})
+
+	t.Run("MergeStruct", func(t *testing.T) {
+		type CoreConfig struct {
+			Host string `toml:"host"`
+			Port int    `toml:"port"`
+		}
+		type PluginConfig struct {
+			Port    int    `toml:"port"`
+			Metrics string `toml:"metrics"`
+		}
+
+		cfg := New()
+		require.NoError(t, cfg.RegisterStruct("server", CoreConfig{Host: "localhost", Port: 8080}))
+		cfg.SetSource(SourceFile, "server.port", 9090)
+
+		// Shared paths keep their defaults and source values; new paths are added
+		require.NoError(t, cfg.MergeStruct("server", PluginConfig{Port: 1, Metrics: "/metrics"}))
+
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, 9090, port)
+		defaults := cfg.GetRegisteredPathsWithDefaults("server.")
+		assert.Equal(t, 8080, defaults["server.port"])
+		assert.Equal(t, "/metrics", defaults["server.metrics"])
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "localhost", host)
+
+		// A different default type for a registered path is a conflict
+		type Conflicting struct {
+			Port string `toml:"port"`
+		}
+		err := cfg.MergeStruct("server", Conflicting{Port: "80"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "type conflict")
+		port, _ = cfg.Get("server.port")
+		assert.Equal(t, 9090, port)
+
+		assert.Error(t, cfg.MergeStruct("server", "not-a-struct"))
+	})
+
+	t.Run("RegisterStructStrict", func(t *testing.T) {
+		type Server struct {
+			Host string `toml:"host"`
+			Bad  int    `toml:"bad key"`
+		}
+		type Strict struct {
+			Name   string `toml:"name"`
+			Broken bool   `toml:"broken!"`
+			Server Server `toml:"server"`
+		}
+
+		cfg := New()
+		fieldErrs, err := cfg.RegisterStructStrict("app.", Strict{Name: "svc", Server: Server{Host: "localhost"}})
+		require.NoError(t, err)
+		require.Len(t, fieldErrs, 2)
+
+		assert.Equal(t, "Broken", fieldErrs[0].Field)
+		assert.Equal(t, "app.broken!", fieldErrs[0].Path)
+		assert.ErrorContains(t, fieldErrs[0].Err, "broken!")
+		assert.Equal(t, "Server.Bad", fieldErrs[1].Field)
+		assert.Equal(t, "app.server.bad key", fieldErrs[1].Path)
+		assert.ErrorContains(t, fieldErrs[1], "field Server.Bad (path app.server.bad key)")
+
+		// Fields without errors are still registered
+		name, _ := cfg.Get("app.name")
+		assert.Equal(t, "svc", name)
+		host, _ := cfg.Get("app.server.host")
+		assert.Equal(t, "localhost", host)
+
+		// The combined form reports the same fields in one error
+		err = New().RegisterStruct("app.", Strict{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to register 2 field(s)")
+		assert.Contains(t, err.Error(), "field Server.Bad (path app.server.bad key)")
+
+		_, err = cfg.RegisterStructStrict("", "not-a-struct")
+		assert.Error(t, err)
+	})
 }
 
 // TestSourcePrecedence tests configuration source precedence
@@ -187,6 +383,116 @@ func TestSourcePrecedence(t *testing.T) {
 	assert.Equal(t, "from-env", sources[SourceEnv])
 }
 
+// TestSetSourceArgumentOrder tests the canonical source-first SetSource signature
+func TestSetSourceArgumentOrder(t *testing.T) {
+	// Fails to compile if the signatures drift from (source, path, value)
+	var setSource func(*Config, Source, string, any) error = (*Config).SetSource
+	var viewSetSource func(*ConfigView, Source, string, any) error = (*ConfigView).SetSource
+
+	cfg := New()
+	cfg.Register("app.value", "default")
+
+	require.NoError(t, setSource(cfg, SourceFile, "app.value", "from-file"))
+	val, _ := cfg.GetSource("app.value", SourceFile)
+	assert.Equal(t, "from-file", val)
+
+	require.NoError(t, viewSetSource(cfg.Subtree("app"), SourceEnv, "value", "from-env"))
+	val, _ = cfg.GetSource("app.value", SourceEnv)
+	assert.Equal(t, "from-env", val)
+
+	// Swapped arguments name an unregistered path rather than silently succeeding
+	assert.Error(t, cfg.SetSource(Source("app.value"), string(SourceFile), "x"))
+}
+
+// TestInvalidSource tests that unknown Source values are rejected
+func TestInvalidSource(t *testing.T) {
+	cfg := New()
+	require.NoError(t, cfg.Register("app.value", "default"))
+
+	typo := Source("fil")
+	assert.False(t, typo.Valid())
+	assert.True(t, SourceFile.Valid())
+
+	err := cfg.SetSource(typo, "app.value", "x")
+	assert.ErrorIs(t, err, ErrInvalidSource)
+	assert.Contains(t, err.Error(), `"fil"`)
+	assert.Empty(t, cfg.GetSources("app.value"))
+
+	assert.ErrorIs(t, cfg.UnsetSource(typo, "app.value"), ErrInvalidSource)
+	assert.ErrorIs(t, cfg.ResetSource(typo), ErrInvalidSource)
+
+	var target struct {
+		Value string `toml:"value"`
+	}
+	assert.ErrorIs(t, cfg.ScanSource(typo, &target, "app"), ErrInvalidSource)
+
+	_, err = cfg.SaveSourcePreview(typo)
+	assert.ErrorIs(t, err, ErrInvalidSource)
+	assert.ErrorIs(t, cfg.SaveSource(filepath.Join(t.TempDir(), "out.toml"), typo), ErrInvalidSource)
+
+	assert.ErrorIs(t, cfg.SetPrecedence(typo, SourceFile), ErrInvalidSource)
+}
+
+// TestRegisterSource tests custom sources placed between the built-in sources
+func TestRegisterSource(t *testing.T) {
+	const sourceRemote Source = "remote"
+
+	cfg := New()
+	require.NoError(t, cfg.Register("server.port", 8080))
+
+	// Unregistered custom sources are rejected
+	assert.ErrorIs(t, cfg.SetSource(sourceRemote, "server.port", 7000), ErrInvalidSource)
+	assert.ErrorIs(t, cfg.SetPrecedence(SourceEnv, sourceRemote, SourceFile), ErrInvalidSource)
+
+	require.NoError(t, cfg.RegisterSource(sourceRemote))
+	assert.Error(t, cfg.RegisterSource(sourceRemote))
+	assert.ErrorIs(t, cfg.RegisterSource(SourceFile), ErrInvalidSource)
+	assert.ErrorIs(t, cfg.RegisterSource(""), ErrInvalidSource)
+
+	require.NoError(t, cfg.SetPrecedence(SourceCLI, SourceEnv, sourceRemote, SourceFile, SourceDefault))
+	assert.Equal(t, []Source{SourceCLI, SourceEnv, sourceRemote, SourceFile, SourceDefault}, cfg.GetPrecedence())
+
+	t.Run("PrecedenceResolution", func(t *testing.T) {
+		require.NoError(t, cfg.SetSource(SourceFile, "server.port", 9000))
+		require.NoError(t, cfg.SetSource(sourceRemote, "server.port", 7000))
+		val, _ := cfg.Get("server.port")
+		assert.Equal(t, 7000, val, "remote overrides file")
+
+		require.NoError(t, cfg.SetSource(SourceEnv, "server.port", 6000))
+		val, _ = cfg.Get("server.port")
+		assert.Equal(t, 6000, val, "env overrides remote")
+
+		require.NoError(t, cfg.UnsetSource(SourceEnv, "server.port"))
+		val, _ = cfg.Get("server.port")
+		assert.Equal(t, 7000, val)
+
+		val, exists := cfg.GetSource("server.port", sourceRemote)
+		assert.True(t, exists)
+		assert.Equal(t, 7000, val)
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		clone := cfg.Clone()
+		val, _ := clone.Get("server.port")
+		assert.Equal(t, 7000, val)
+		assert.NoError(t, clone.SetSource(sourceRemote, "server.port", 7100))
+	})
+
+	t.Run("LoadLeavesCustomSource", func(t *testing.T) {
+		opts := DefaultLoadOptions()
+		opts.Sources = cfg.GetPrecedence()
+		require.NoError(t, cfg.LoadWithOptions("", nil, opts))
+		val, _ := cfg.Get("server.port")
+		assert.Equal(t, 7000, val)
+	})
+
+	t.Run("ResetSource", func(t *testing.T) {
+		require.NoError(t, cfg.ResetSource(sourceRemote))
+		val, _ := cfg.Get("server.port")
+		assert.Equal(t, 9000, val, "file applies once remote is cleared")
+	})
+}
+
 // TestSetPrecedence tests runtime precedence switching
 func TestSetPrecedence(t *testing.T) {
 	t.Run("BasicPrecedenceSwitch", func(t *testing.T) {
@@ -325,6 +631,65 @@ func TestSetPrecedence(t *testing.T) {
 		}
 	})
 
+	t.Run("ChangeEvents", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configFile, []byte("a = \"file-a\"\nb = \"file-b\"\nc = \"file-c\"\n"), 0644))
+
+		cfg := New()
+		for _, path := range []string{"a", "b", "c"} {
+			cfg.Register(path, "default")
+		}
+		require.NoError(t, cfg.LoadFile(configFile))
+		cfg.SetSource(SourceCLI, "a", "cli-a")
+		cfg.SetSource(SourceEnv, "c", "env-c")
+
+		changes := cfg.Watch()
+		events := cfg.WatchEvents()
+		defer cfg.StopAutoUpdate()
+
+		// drain returns what is queued; notifications are sent before the setter returns
+		drain := func() ([]string, []ChangeEvent) {
+			var paths []string
+			var received []ChangeEvent
+			for {
+				select {
+				case path := <-changes:
+					paths = append(paths, path)
+				case event := <-events:
+					received = append(received, event)
+				default:
+					return paths, received
+				}
+			}
+		}
+
+		// File first: a and c change, b already resolved from the file
+		require.NoError(t, cfg.SetPrecedence(SourceFile, SourceCLI, SourceEnv, SourceDefault))
+		paths, received := drain()
+		assert.Equal(t, []string{"precedence:a", "precedence:c"}, paths)
+		assert.Equal(t, []ChangeEvent{
+			{Path: "a", OldValue: "cli-a", NewValue: "file-a", Cause: ChangeCausePrecedence},
+			{Path: "c", OldValue: "env-c", NewValue: "file-c", Cause: ChangeCausePrecedence},
+		}, received)
+
+		// SetLoadOptions notifies the same way
+		opts := DefaultLoadOptions()
+		require.NoError(t, cfg.SetLoadOptions(opts))
+		paths, received = drain()
+		assert.Equal(t, []string{"precedence:a", "precedence:c"}, paths)
+		assert.Equal(t, []ChangeEvent{
+			{Path: "a", OldValue: "file-a", NewValue: "cli-a", Cause: ChangeCausePrecedence},
+			{Path: "c", OldValue: "file-c", NewValue: "env-c", Cause: ChangeCausePrecedence},
+		}, received)
+
+		// Options that keep the precedence send nothing
+		opts.EnvPrefix = "OTHER_"
+		require.NoError(t, cfg.SetLoadOptions(opts))
+		paths, received = drain()
+		assert.Empty(t, paths)
+		assert.Empty(t, received)
+	})
+
 	t.Run("ConcurrentPrecedenceChanges", func(t *testing.T) {
 		cfg := New()
 		cfg.Register("test", "default")
@@ -382,6 +747,84 @@ func TestSetPrecedence(t *testing.T) {
 	})
 }
 
+// TestSetLoadOptionsReloadEnv tests re-reading env vars when env options change
+func TestSetLoadOptionsReloadEnv(t *testing.T) {
+	t.Setenv("OLD_SERVER_PORT", "8081")
+	t.Setenv("NEW_SERVER_PORT", "9090")
+	t.Setenv("NEW_SERVER_HOST", "new-host")
+
+	t.Run("WithoutFlag", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.port", 8080)
+		cfg.Register("server.host", "localhost")
+		cfg.Register("debug", false)
+		require.NoError(t, cfg.LoadEnv("OLD_"))
+
+		opts := DefaultLoadOptions()
+		opts.EnvPrefix = "NEW_"
+		require.NoError(t, cfg.SetLoadOptions(opts))
+
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, "8081", port, "env is not re-read without ReloadEnv")
+	})
+
+	t.Run("PrefixChange", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.port", 8080)
+		cfg.Register("server.host", "localhost")
+		cfg.Register("debug", false)
+		require.NoError(t, cfg.LoadEnv("OLD_"))
+
+		opts := DefaultLoadOptions()
+		opts.EnvPrefix = "NEW_"
+		opts.ReloadEnv = true
+		require.NoError(t, cfg.SetLoadOptions(opts))
+
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, "9090", port)
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "new-host", host)
+	})
+
+	t.Run("StaleValuesRemoved", func(t *testing.T) {
+		t.Setenv("OLD_DEBUG", "true")
+		cfg := New()
+		cfg.Register("server.port", 8080)
+		cfg.Register("server.host", "localhost")
+		cfg.Register("debug", false)
+		require.NoError(t, cfg.LoadEnv("OLD_"))
+
+		opts := DefaultLoadOptions()
+		opts.EnvPrefix = "NEW_"
+		opts.ReloadEnv = true
+		require.NoError(t, cfg.SetLoadOptions(opts))
+
+		debug, _ := cfg.Get("debug")
+		assert.Equal(t, false, debug, "OLD_DEBUG no longer matches")
+		_, exists := cfg.GetSource("debug", SourceEnv)
+		assert.False(t, exists)
+	})
+
+	t.Run("Whitelist", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.port", 8080)
+		cfg.Register("server.host", "localhost")
+		cfg.Register("debug", false)
+		require.NoError(t, cfg.LoadEnv("OLD_"))
+
+		opts := DefaultLoadOptions()
+		opts.EnvPrefix = "NEW_"
+		opts.EnvWhitelist = map[string]bool{"server.host": true}
+		opts.ReloadEnv = true
+		require.NoError(t, cfg.SetLoadOptions(opts))
+
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, 8080, port)
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "new-host", host)
+	})
+}
+
 // TestPrecedenceWithAutoUpdate verifies no conflicts between precedence and auto-update
 func TestPrecedenceWithAutoUpdate(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -488,7 +931,7 @@ func TestTypeConversion(t *testing.T) {
 	cfg.SetSource(SourceEnv, "ipnet", "10.0.0.0/8")
 	cfg.SetSource(SourceEnv, "url", "https://example.com:8080/path")
 	cfg.SetSource(SourceEnv, "strings", "x,y,z")
-	// cfg.SetSource("ints", SourceEnv, "7,8,9") // failure due to mapstructure limitation
+	cfg.SetSource(SourceEnv, "ints", "7,8,9")
 
 	// Scan into struct
 	var result TestConfig
@@ -504,7 +947,7 @@ func TestTypeConversion(t *testing.T) {
 	assert.Equal(t, "10.0.0.0/8", result.IPNet.String())
 	assert.Equal(t, "https://example.com:8080/path", result.URL.String())
 	assert.Equal(t, []string{"x", "y", "z"}, result.StringSlice)
-	// Note: String to int slice conversion through env requires handling in the test
+	assert.Equal(t, []int{7, 8, 9}, result.IntSlice)
 }
 
 // TestConcurrentAccess tests thread safety
@@ -576,6 +1019,76 @@ func TestConcurrentAccess(t *testing.T) {
 	assert.Empty(t, errs, "Concurrent access should not produce errors")
 }
 
+// TestLockFreeReads tests that Get observes every kind of write while readers run concurrently
+func TestLockFreeReads(t *testing.T) {
+	cfg := New()
+	cfg.Register("counter", int64(0))
+
+	t.Run("ReadYourWrites", func(t *testing.T) {
+		for i := int64(1); i <= 100; i++ {
+			require.NoError(t, cfg.Set("counter", i))
+			val, _ := cfg.Get("counter")
+			require.Equal(t, i, val)
+		}
+
+		require.NoError(t, cfg.Register("late", "value"))
+		val, exists := cfg.Get("late")
+		assert.True(t, exists)
+		assert.Equal(t, "value", val)
+
+		require.NoError(t, cfg.Unregister("late"))
+		_, exists = cfg.Get("late")
+		assert.False(t, exists)
+
+		require.NoError(t, cfg.ResetSource(SourceCLI))
+		val, _ = cfg.Get("counter")
+		assert.Equal(t, int64(0), val)
+	})
+
+	t.Run("ConcurrentReadersAndWriters", func(t *testing.T) {
+		var wg sync.WaitGroup
+		done := make(chan struct{})
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var last int64
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					val, exists := cfg.Get("counter")
+					if !assert.True(t, exists) {
+						return
+					}
+					// A single writer only increases the counter
+					n := val.(int64)
+					if !assert.GreaterOrEqual(t, n, last) {
+						return
+					}
+					last = n
+					_, _ = cfg.Get(fmt.Sprintf("dynamic%d", n%10))
+				}
+			}()
+		}
+
+		for i := int64(1); i <= 500; i++ {
+			require.NoError(t, cfg.Set("counter", i))
+			if i%50 == 0 {
+				require.NoError(t, cfg.Register(fmt.Sprintf("dynamic%d", i%10), i))
+			}
+		}
+		close(done)
+		wg.Wait()
+
+		val, _ := cfg.Get("counter")
+		assert.Equal(t, int64(500), val)
+	})
+}
+
 // TestUnregister tests path unregistration
 func TestUnregister(t *testing.T) {
 	cfg := New()
@@ -615,8 +1128,55 @@ func TestUnregister(t *testing.T) {
 
 	t.Run("UnregisterNonExistentPath", func(t *testing.T) {
 		err := cfg.Unregister("nonexistent.path")
+		assert.ErrorIs(t, err, ErrPathNotRegistered)
+	})
+
+	t.Run("KeepValues", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.timeout", "30")
+		cfg.SetSource(SourceFile, "server.timeout", "45")
+		cfg.SetSource(SourceEnv, "server.timeout", "60")
+
+		saved, err := cfg.UnregisterKeepValues("server.timeout")
+		require.NoError(t, err)
+		assert.Equal(t, map[Source]any{SourceFile: "45", SourceEnv: "60"}, saved)
+		_, exists := cfg.Get("server.timeout")
+		assert.False(t, exists)
+
+		// Re-register with a new default type and restore the overrides
+		require.NoError(t, cfg.Register("server.timeout", 30))
+		for source, value := range saved {
+			require.NoError(t, cfg.SetSource(source, "server.timeout", value))
+		}
+
+		val, _ := cfg.Get("server.timeout")
+		assert.Equal(t, "60", val)
+		fileVal, _ := cfg.GetSource("server.timeout", SourceFile)
+		assert.Equal(t, "45", fileVal)
+
+		var result struct {
+			Server struct {
+				Timeout int `toml:"timeout"`
+			} `toml:"server"`
+		}
+		require.NoError(t, cfg.Scan(&result))
+		assert.Equal(t, 60, result.Server.Timeout)
+
+		// Only exact registered paths can be kept
+		cfg.Register("db.host", "localhost")
+		_, err = cfg.UnregisterKeepValues("db")
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "path not registered")
+		_, err = cfg.UnregisterKeepValues("missing")
+		assert.Error(t, err)
+
+		// A path with children is kept, along with the children's values
+		cfg.Register("cache", map[string]any{})
+		cfg.Register("cache.size", 64)
+		require.NoError(t, cfg.Set("cache.size", 128))
+		_, err = cfg.UnregisterKeepValues("cache")
+		assert.ErrorContains(t, err, "has registered children")
+		size, _ := cfg.Get("cache.size")
+		assert.Equal(t, 128, size)
 	})
 }
 
@@ -657,6 +1217,177 @@ func TestResetFunctionality(t *testing.T) {
 		sources := cfg.GetSources("test1")
 		assert.Empty(t, sources)
 	})
+
+	t.Run("ResetExcept", func(t *testing.T) {
+		cfg.SetSource(SourceFile, "test1", "file1")
+		cfg.SetSource(SourceEnv, "test1", "env1")
+		cfg.SetSource(SourceCLI, "test1", "cli1")
+		cfg.SetSource(SourceEnv, "test2", "env2")
+
+		require.NoError(t, cfg.ResetExcept(SourceCLI))
+
+		// CLI values are kept, file and env values are cleared
+		val1, _ := cfg.Get("test1")
+		val2, _ := cfg.Get("test2")
+		assert.Equal(t, "cli1", val1)
+		assert.Equal(t, "default2", val2)
+		assert.Equal(t, map[Source]any{SourceCLI: "cli1"}, cfg.GetSources("test1"))
+		assert.Empty(t, cfg.fileData)
+		assert.Empty(t, cfg.envData)
+		assert.Equal(t, map[string]any{"test1": "cli1"}, cfg.cliData)
+
+		// Re-applied file values stay below the kept CLI value
+		cfg.SetSource(SourceFile, "test1", "file2")
+		val1, _ = cfg.Get("test1")
+		assert.Equal(t, "cli1", val1)
+
+		assert.ErrorIs(t, cfg.ResetExcept(Source("cl")), ErrInvalidSource)
+	})
+}
+
+// TestUnsetSource tests removal of a single source value for a single path
+func TestUnsetSource(t *testing.T) {
+	cfg := New()
+	cfg.Register("server.port", 8080)
+	cfg.Register("server.host", "localhost")
+
+	cfg.SetSource(SourceFile, "server.port", 9000)
+	cfg.SetSource(SourceEnv, "server.port", "9090")
+	cfg.SetSource(SourceEnv, "server.host", "envhost")
+
+	t.Run("UnsetTargetedPath", func(t *testing.T) {
+		err := cfg.UnsetSource(SourceEnv, "server.port")
+		require.NoError(t, err)
+
+		// Env value for the targeted path is gone, file value wins
+		_, exists := cfg.GetSource("server.port", SourceEnv)
+		assert.False(t, exists)
+		val, _ := cfg.Get("server.port")
+		assert.Equal(t, 9000, val)
+
+		// Other sources of the same path remain
+		val, exists = cfg.GetSource("server.port", SourceFile)
+		assert.True(t, exists)
+		assert.Equal(t, 9000, val)
+
+		// Same source of other paths remains
+		val, _ = cfg.Get("server.host")
+		assert.Equal(t, "envhost", val)
+		assert.NotContains(t, cfg.envData, "server.port")
+		assert.Contains(t, cfg.envData, "server.host")
+	})
+
+	t.Run("UnsetMissingSourceValue", func(t *testing.T) {
+		err := cfg.UnsetSource(SourceCLI, "server.port")
+		assert.NoError(t, err)
+		val, _ := cfg.Get("server.port")
+		assert.Equal(t, 9000, val)
+	})
+
+	t.Run("UnsetUnregisteredPath", func(t *testing.T) {
+		err := cfg.UnsetSource(SourceEnv, "nonexistent")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not registered")
+	})
+}
+
+// TestCompareAndSwap tests optimistic updates of a single path
+func TestCompareAndSwap(t *testing.T) {
+	t.Run("Swap", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("feature.x", false)
+
+		swapped, err := cfg.CompareAndSwap("feature.x", false, true)
+		require.NoError(t, err)
+		assert.True(t, swapped)
+		val, _ := cfg.Get("feature.x")
+		assert.Equal(t, true, val)
+
+		// Written to the highest priority source, like Set
+		cliVal, exists := cfg.GetSource("feature.x", SourceCLI)
+		assert.True(t, exists)
+		assert.Equal(t, true, cliVal)
+	})
+
+	t.Run("ValueChanged", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("feature.x", "a")
+		cfg.SetSource(SourceEnv, "feature.x", "b")
+
+		swapped, err := cfg.CompareAndSwap("feature.x", "a", "c")
+		require.NoError(t, err)
+		assert.False(t, swapped)
+		val, _ := cfg.Get("feature.x")
+		assert.Equal(t, "b", val)
+	})
+
+	t.Run("UnregisteredPath", func(t *testing.T) {
+		cfg := New()
+		_, err := cfg.CompareAndSwap("missing", nil, 1)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not registered")
+	})
+
+	t.Run("Contention", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("counter", 0)
+
+		const workers, increments = 8, 100
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for n := 0; n < increments; {
+					current, _ := cfg.Get("counter")
+					swapped, err := cfg.CompareAndSwap("counter", current, current.(int)+1)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if swapped {
+						n++
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		val, _ := cfg.Get("counter")
+		assert.Equal(t, workers*increments, val)
+	})
+}
+
+// TestSetDefault tests replacing a default after registration
+func TestSetDefault(t *testing.T) {
+	cfg := New()
+	cfg.Register("server.port", 8080)
+	cfg.Register("server.host", "localhost")
+	cfg.SetSource(SourceFile, "server.host", "filehost")
+
+	t.Run("NoSourceValue", func(t *testing.T) {
+		require.NoError(t, cfg.SetDefault("server.port", 9090))
+		val, _ := cfg.Get("server.port")
+		assert.Equal(t, 9090, val)
+		assert.Equal(t, 9090, cfg.GetRegisteredPathsWithDefaults()["server.port"])
+	})
+
+	t.Run("SourceValueWins", func(t *testing.T) {
+		require.NoError(t, cfg.SetDefault("server.host", "0.0.0.0"))
+		val, _ := cfg.Get("server.host")
+		assert.Equal(t, "filehost", val)
+
+		// The new default shows once the override is removed
+		require.NoError(t, cfg.UnsetSource(SourceFile, "server.host"))
+		val, _ = cfg.Get("server.host")
+		assert.Equal(t, "0.0.0.0", val)
+	})
+
+	t.Run("UnregisteredPath", func(t *testing.T) {
+		err := cfg.SetDefault("nonexistent", 1)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not registered")
+	})
 }
 
 // TestValueSizeLimit tests the MaxValueSize constraint
@@ -673,6 +1404,149 @@ func TestValueSizeLimit(t *testing.T) {
 	err := cfg.Set("test", string(largeValue))
 	assert.Error(t, err)
 	assert.Equal(t, ErrValueSize, err)
+
+	t.Run("RaisedLimit", func(t *testing.T) {
+		c := New()
+		c.Register("test", "")
+		c.SetMaxValueSize(2 * MaxValueSize)
+
+		assert.NoError(t, c.Set("test", string(largeValue)))
+		assert.Equal(t, ErrValueSize, c.Set("test", strings.Repeat("x", 2*MaxValueSize+1)))
+	})
+
+	t.Run("LoweredLimit", func(t *testing.T) {
+		c := New()
+		c.Register("test", "")
+		c.SetMaxValueSize(8)
+
+		assert.NoError(t, c.Set("test", "12345678"))
+		assert.Equal(t, ErrValueSize, c.SetSource(SourceFile, "test", "123456789"))
+
+		t.Setenv("SIZE_TEST", "123456789")
+		err := c.LoadEnv("SIZE_")
+		assert.Equal(t, ErrValueSize, err)
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		c := New()
+		c.Register("test", "")
+		c.SetMaxValueSize(0)
+
+		assert.NoError(t, c.Set("test", string(largeValue)))
+	})
+
+	t.Run("Builder", func(t *testing.T) {
+		c, err := NewBuilder().
+			WithDefaults(struct {
+				Test string `toml:"test"`
+			}{}).
+			WithMaxValueSize(4).
+			WithArgs(nil).
+			Build()
+		require.NoError(t, err)
+		assert.Equal(t, ErrValueSize, c.Set("test", "12345"))
+		assert.Equal(t, int64(4), c.Clone().maxValueSize)
+
+		_, err = NewBuilder().WithMaxValueSize(-1).Build()
+		assert.Error(t, err)
+	})
+}
+
+// TestLookup tests distinguishing unregistered paths from nil values
+func TestLookup(t *testing.T) {
+	cfg := New()
+	cfg.Register("tls.cert", nil)
+	cfg.Register("server.port", 8080)
+	cfg.Register("server.hosts", []string{"a", "b"})
+
+	t.Run("Unregistered", func(t *testing.T) {
+		value, registered, hasValue := cfg.Lookup("missing.path")
+		assert.Nil(t, value)
+		assert.False(t, registered)
+		assert.False(t, hasValue)
+	})
+
+	t.Run("RegisteredNil", func(t *testing.T) {
+		value, registered, hasValue := cfg.Lookup("tls.cert")
+		assert.Nil(t, value)
+		assert.True(t, registered)
+		assert.False(t, hasValue)
+	})
+
+	t.Run("RegisteredSet", func(t *testing.T) {
+		value, registered, hasValue := cfg.Lookup("server.port")
+		assert.Equal(t, 8080, value)
+		assert.True(t, registered)
+		assert.True(t, hasValue)
+
+		require.NoError(t, cfg.SetSource(SourceFile, "tls.cert", "/etc/cert.pem"))
+		value, registered, hasValue = cfg.Lookup("tls.cert")
+		assert.Equal(t, "/etc/cert.pem", value)
+		assert.True(t, registered)
+		assert.True(t, hasValue)
+	})
+
+	t.Run("ElementPath", func(t *testing.T) {
+		value, registered, hasValue := cfg.Lookup("server.hosts[1]")
+		assert.Equal(t, "b", value)
+		assert.True(t, registered)
+		assert.True(t, hasValue)
+
+		_, registered, _ = cfg.Lookup("server.hosts[5]")
+		assert.False(t, registered)
+	})
+}
+
+// TestPathErrors tests that path problems are reported with matchable errors
+func TestPathErrors(t *testing.T) {
+	cfg := New()
+	require.NoError(t, cfg.Register("server.port", 8080))
+	require.NoError(t, cfg.Register("server.hosts", []string{"a"}))
+
+	const missing = "server.missing"
+	notRegistered := map[string]error{
+		"Set":           cfg.Set(missing, 1),
+		"SetSource":     cfg.SetSource(SourceEnv, missing, 1),
+		"UnsetSource":   cfg.UnsetSource(SourceEnv, missing),
+		"SetDefault":    cfg.SetDefault(missing, 1),
+		"Unregister":    cfg.Unregister(missing),
+		"RegisterAlias": cfg.RegisterAlias("m", missing),
+	}
+	_, notRegistered["UnregisterKeepValues"] = cfg.UnregisterKeepValues(missing)
+	_, notRegistered["CompareAndSwap"] = cfg.CompareAndSwap(missing, nil, 1)
+	_, notRegistered["GetTyped"] = GetTyped[string](cfg, missing)
+
+	for method, err := range notRegistered {
+		t.Run(method, func(t *testing.T) {
+			require.ErrorIs(t, err, ErrPathNotRegistered)
+			var pathErr *PathError
+			require.ErrorAs(t, err, &pathErr)
+			assert.Equal(t, missing, pathErr.Path)
+			assert.Equal(t, "path server.missing is not registered", err.Error())
+		})
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		_, registered := cfg.Get(missing)
+		assert.False(t, registered)
+	})
+
+	t.Run("MustString", func(t *testing.T) {
+		assert.PanicsWithValue(t, "config value server.missing unavailable: path server.missing is not registered",
+			func() { cfg.MustString(missing) })
+	})
+
+	t.Run("InvalidSegment", func(t *testing.T) {
+		err := cfg.Register("server..port", 1)
+		require.ErrorIs(t, err, ErrInvalidPathSegment)
+		var pathErr *PathError
+		require.ErrorAs(t, err, &pathErr)
+		assert.Equal(t, "server..port", pathErr.Path)
+		assert.Equal(t, "", pathErr.Segment)
+
+		_, err = parseIndexedPath("[0].bad!")
+		assert.ErrorIs(t, err, ErrInvalidPathSegment)
+	})
 }
 
 // TestGetRegisteredPaths tests path listing functionality
@@ -714,4 +1588,685 @@ func TestGetRegisteredPaths(t *testing.T) {
 		assert.Contains(t, defaults, "database.host")
 		assert.Contains(t, defaults, "database.port")
 	})
+}
+
+// TestIndexedPathAccess tests [n] element access for slice values
+func TestIndexedPathAccess(t *testing.T) {
+	cfg := New()
+	cfg.Register("server.hosts", []string{"a.example.com", "b.example.com"})
+	cfg.Register("servers", []any{
+		map[string]any{"name": "web", "port": int64(80)},
+	})
+
+	t.Run("ReadElement", func(t *testing.T) {
+		val, exists := cfg.Get("server.hosts[1]")
+		assert.True(t, exists)
+		assert.Equal(t, "b.example.com", val)
+
+		val, exists = cfg.Get("servers[0].name")
+		assert.True(t, exists)
+		assert.Equal(t, "web", val)
+	})
+
+	t.Run("OutOfRangeRead", func(t *testing.T) {
+		_, exists := cfg.Get("server.hosts[5]")
+		assert.False(t, exists)
+
+		_, exists = cfg.Get("unregistered[0]")
+		assert.False(t, exists)
+	})
+
+	t.Run("SetElement", func(t *testing.T) {
+		require.NoError(t, cfg.Set("server.hosts[0]", "x.example.com"))
+
+		val, _ := cfg.Get("server.hosts")
+		assert.Equal(t, []any{"x.example.com", "b.example.com"}, val)
+
+		// Default is untouched
+		defaults := cfg.GetRegisteredPathsWithDefaults("server.")
+		assert.Equal(t, []string{"a.example.com", "b.example.com"}, defaults["server.hosts"])
+	})
+
+	t.Run("SetGrowsSlice", func(t *testing.T) {
+		require.NoError(t, cfg.SetSource(SourceFile, "server.hosts[3]", "d.example.com"))
+
+		val, exists := cfg.GetSource("server.hosts[3]", SourceFile)
+		assert.True(t, exists)
+		assert.Equal(t, "d.example.com", val)
+
+		fileVal, _ := cfg.GetSource("server.hosts", SourceFile)
+		assert.Len(t, fileVal, 4)
+	})
+
+	t.Run("InvalidIndex", func(t *testing.T) {
+		err := cfg.Set("server.hosts[-1]", "x")
+		assert.Error(t, err)
+		_, exists := cfg.Get("server.hosts[a]")
+		assert.False(t, exists)
+	})
+}
+
+// TestSubtree tests scoped views over a parent config
+func TestSubtree(t *testing.T) {
+	cfg := New()
+	cfg.Register("server.host", "localhost")
+	cfg.Register("server.port", int64(8080))
+	cfg.Register("server.tls.enabled", false)
+
+	sub := cfg.Subtree("server")
+	assert.Equal(t, "server", sub.Prefix())
+
+	port, exists := sub.Get("port")
+	assert.True(t, exists)
+	parentPort, _ := cfg.Get("server.port")
+	assert.Equal(t, parentPort, port)
+
+	// Writes through the view propagate to the parent
+	require.NoError(t, sub.Set("host", "subhost"))
+	host, _ := cfg.Get("server.host")
+	assert.Equal(t, "subhost", host)
+
+	require.NoError(t, sub.SetSource(SourceEnv, "port", int64(9090)))
+	assert.Equal(t, int64(9090), sub.GetSources("port")[SourceEnv])
+
+	// Parent writes are visible through the view
+	cfg.Set("server.tls.enabled", true)
+	enabled, _ := sub.Subtree("tls").Get("enabled")
+	assert.Equal(t, true, enabled)
+
+	var server struct {
+		Host string `toml:"host"`
+		Port int    `toml:"port"`
+	}
+	require.NoError(t, sub.Scan(&server))
+	assert.Equal(t, "subhost", server.Host)
+	assert.Equal(t, 9090, server.Port)
+
+	var tls struct {
+		Enabled bool `toml:"enabled"`
+	}
+	require.NoError(t, sub.Scan(&tls, "tls"))
+	assert.True(t, tls.Enabled)
+
+	_, exists = sub.Get("missing")
+	assert.False(t, exists)
+}
+
+// TestFreeze tests read-only behavior after Freeze
+func TestFreeze(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "frozen.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`port = 9090`), 0644))
+
+	cfg := New()
+	cfg.Register("port", 8080)
+	cfg.Register("host", "localhost")
+	require.NoError(t, cfg.SetSource(SourceEnv, "host", "envhost"))
+
+	assert.False(t, cfg.IsFrozen())
+	cfg.Freeze()
+	assert.True(t, cfg.IsFrozen())
+
+	t.Run("RejectsWrites", func(t *testing.T) {
+		assert.ErrorIs(t, cfg.Set("port", 1), ErrFrozen)
+		assert.ErrorIs(t, cfg.SetSource(SourceFile, "port", 1), ErrFrozen)
+		assert.ErrorIs(t, cfg.UnsetSource(SourceEnv, "host"), ErrFrozen)
+		assert.ErrorIs(t, cfg.Reset(), ErrFrozen)
+		assert.ErrorIs(t, cfg.ResetSource(SourceEnv), ErrFrozen)
+		assert.ErrorIs(t, cfg.Register("new", 1), ErrFrozen)
+		assert.ErrorIs(t, cfg.RegisterStruct("s.", struct {
+			A int `toml:"a"`
+		}{}), ErrFrozen)
+		assert.ErrorIs(t, cfg.Unregister("port"), ErrFrozen)
+		assert.ErrorIs(t, cfg.LoadFile(configFile), ErrFrozen)
+		assert.ErrorIs(t, cfg.LoadCLI([]string{"--port=1"}), ErrFrozen)
+	})
+
+	t.Run("ServesReads", func(t *testing.T) {
+		port, exists := cfg.Get("port")
+		assert.True(t, exists)
+		assert.Equal(t, 8080, port)
+
+		var result struct {
+			Host string `toml:"host"`
+			Port int    `toml:"port"`
+		}
+		require.NoError(t, cfg.Scan(&result))
+		assert.Equal(t, "envhost", result.Host)
+		assert.Equal(t, 8080, result.Port)
+
+		// Clones are writable
+		clone := cfg.Clone()
+		assert.False(t, clone.IsFrozen())
+		assert.NoError(t, clone.Set("port", 1))
+	})
+
+	t.Run("AllowReload", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("port", 8080)
+		cfg.FreezeWithOptions(FreezeOptions{AllowReload: true})
+
+		require.NoError(t, cfg.LoadFile(configFile))
+		port, _ := cfg.Get("port")
+		assert.Equal(t, int64(9090), port)
+		assert.ErrorIs(t, cfg.Set("port", 1), ErrFrozen)
+	})
+}
+
+// TestGetMap tests subtree retrieval as nested maps
+func TestGetMap(t *testing.T) {
+	cfg := New()
+	cfg.Register("plugins.foo.enabled", false)
+	cfg.Register("plugins.foo.limits.rate", 10)
+	cfg.Register("plugins.bar.name", "bar")
+	cfg.Register("metadata", map[string]any{"version": "1.0", "tags": []any{"a"}})
+
+	t.Run("SynthesizedSubtree", func(t *testing.T) {
+		cfg.Set("plugins.foo.enabled", true)
+
+		foo, ok := cfg.GetMap("plugins.foo")
+		require.True(t, ok)
+		assert.Equal(t, map[string]any{
+			"enabled": true,
+			"limits":  map[string]any{"rate": 10},
+		}, foo)
+
+		plugins, ok := cfg.GetMap("plugins")
+		require.True(t, ok)
+		assert.Contains(t, plugins, "bar")
+	})
+
+	t.Run("MapValue", func(t *testing.T) {
+		metadata, ok := cfg.GetMap("metadata")
+		require.True(t, ok)
+		assert.Equal(t, "1.0", metadata["version"])
+
+		// Mutating the result does not affect the config
+		metadata["version"] = "2.0"
+		metadata["tags"].([]any)[0] = "changed"
+		again, _ := cfg.GetMap("metadata")
+		assert.Equal(t, "1.0", again["version"])
+		assert.Equal(t, []any{"a"}, again["tags"])
+	})
+
+	t.Run("NotAMap", func(t *testing.T) {
+		_, ok := cfg.GetMap("plugins.bar.name")
+		assert.False(t, ok)
+		_, ok = cfg.GetMap("missing")
+		assert.False(t, ok)
+	})
+}
+
+// BenchmarkLargeConfig benchmarks value updates on a config with 5000 registered paths
+func BenchmarkLargeConfig(b *testing.B) {
+	cfg := New()
+	for i := 0; i < 5000; i++ {
+		cfg.Register(fmt.Sprintf("section%d.value%d", i%50, i), int64(i))
+	}
+	for i := 0; i < 5000; i += 10 {
+		cfg.SetSource(SourceFile, fmt.Sprintf("section%d.value%d", i%50, i), int64(-i))
+	}
+
+	b.Run("Set", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = cfg.Set("section1.value1", int64(i))
+		}
+	})
+
+	b.Run("ResetSource", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = cfg.SetSource(SourceEnv, "section1.value1", int64(i))
+			_ = cfg.ResetSource(SourceEnv)
+		}
+	})
+
+	b.Run("SetPrecedence", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if i%2 == 0 {
+				_ = cfg.SetPrecedence(SourceFile, SourceEnv, SourceCLI, SourceDefault)
+			} else {
+				_ = cfg.SetPrecedence(SourceCLI, SourceEnv, SourceFile, SourceDefault)
+			}
+		}
+	})
+
+	b.Run("SetLoadOptionsSamePrecedence", func(b *testing.B) {
+		opts := DefaultLoadOptions()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			opts.EnvPrefix = fmt.Sprintf("APP%d_", i%2)
+			_ = cfg.SetLoadOptions(opts)
+		}
+	})
+}
+
+// BenchmarkConcurrentGet benchmarks parallel reads through the lock-free Get against the locked GetSource
+func BenchmarkConcurrentGet(b *testing.B) {
+	cfg := New()
+	paths := make([]string, 100)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("value%d", i)
+		cfg.Register(paths[i], int64(i))
+		cfg.SetSource(SourceDefault, paths[i], int64(i))
+	}
+
+	b.Run("Get", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				_, _ = cfg.Get(paths[i%len(paths)])
+				i++
+			}
+		})
+	})
+
+	b.Run("GetSource", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				_, _ = cfg.GetSource(paths[i%len(paths)], SourceDefault)
+				i++
+			}
+		})
+	})
+}
+
+// BenchmarkSetThenGet benchmarks reads that follow writes on a large config, serially
+// and with readers running alongside a writer
+func BenchmarkSetThenGet(b *testing.B) {
+	cfg := New()
+	paths := make([]string, 5000)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("value%d", i)
+		cfg.Register(paths[i], int64(i))
+	}
+
+	b.Run("Serial", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = cfg.Set(paths[i%len(paths)], int64(i))
+			_, _ = cfg.Get(paths[(i+1)%len(paths)])
+		}
+	})
+
+	b.Run("Parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				if i%10 == 0 {
+					_ = cfg.Set(paths[i%len(paths)], int64(i))
+				} else {
+					_, _ = cfg.Get(paths[i%len(paths)])
+				}
+				i++
+			}
+		})
+	})
+}
+
+// TestRegisterFunc tests lazily computed defaults
+func TestRegisterFunc(t *testing.T) {
+	counter := func() (*atomic.Int32, func() any) {
+		var calls atomic.Int32
+		return &calls, func() any {
+			calls.Add(1)
+			return "generated"
+		}
+	}
+
+	t.Run("NotCalledWhenOverridden", func(t *testing.T) {
+		calls, fn := counter()
+		cfg := New()
+		require.NoError(t, cfg.RegisterFunc("node.id", fn))
+		t.Setenv("APP_NODE_ID", "from-env")
+		require.NoError(t, cfg.LoadEnv("APP_"))
+
+		value, _ := cfg.Get("node.id")
+		assert.Equal(t, "from-env", value)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+
+	t.Run("CalledOnce", func(t *testing.T) {
+		calls, fn := counter()
+		cfg := New()
+		require.NoError(t, cfg.RegisterFunc("node.id", fn))
+		assert.Equal(t, int32(0), calls.Load(), "not called at registration")
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, _ := cfg.Get("node.id")
+				assert.Equal(t, "generated", value)
+			}()
+		}
+		wg.Wait()
+
+		var target struct {
+			Node struct {
+				ID string `toml:"id"`
+			} `toml:"node"`
+		}
+		require.NoError(t, cfg.Scan(&target))
+		assert.Equal(t, "generated", target.Node.ID)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Empty(t, cfg.ModifiedPaths(), "the result is the default")
+	})
+
+	t.Run("FallbackAfterUnset", func(t *testing.T) {
+		calls, fn := counter()
+		cfg := New()
+		require.NoError(t, cfg.RegisterFunc("node.id", fn))
+		require.NoError(t, cfg.Set("node.id", "explicit"))
+		assert.Equal(t, int32(0), calls.Load())
+
+		require.NoError(t, cfg.UnsetSource(SourceCLI, "node.id"))
+		value, _ := cfg.Get("node.id")
+		assert.Equal(t, "generated", value)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("SetDefaultReplacesFunc", func(t *testing.T) {
+		calls, fn := counter()
+		cfg := New()
+		require.NoError(t, cfg.RegisterFunc("node.id", fn))
+		require.NoError(t, cfg.SetDefault("node.id", "fixed"))
+
+		value, _ := cfg.Get("node.id")
+		assert.Equal(t, "fixed", value)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+
+	t.Run("OnlyRequestedPath", func(t *testing.T) {
+		calls, fn := counter()
+		cfg := New()
+		require.NoError(t, cfg.RegisterFunc("node.id", fn))
+		require.NoError(t, cfg.RegisterFunc("node.name", func() any { return "node" }))
+
+		value, _ := cfg.Get("node.name")
+		assert.Equal(t, "node", value)
+		assert.Equal(t, int32(0), calls.Load(), "other paths are not evaluated")
+	})
+
+	t.Run("PendingCount", func(t *testing.T) {
+		_, fn := counter()
+		cfg := New()
+		require.NoError(t, cfg.RegisterFunc("node.id", fn))
+		require.NoError(t, cfg.RegisterFunc("node.name", fn))
+		assert.Equal(t, int64(2), cfg.lazyPending.Load())
+
+		cfg.Get("node.id")
+		assert.Equal(t, int64(1), cfg.lazyPending.Load())
+
+		require.NoError(t, cfg.Set("node.name", "explicit"))
+		assert.Equal(t, int64(0), cfg.lazyPending.Load(), "overridden defaults are not pending")
+
+		require.NoError(t, cfg.UnsetSource(SourceCLI, "node.name"))
+		assert.Equal(t, int64(1), cfg.lazyPending.Load())
+		require.NoError(t, cfg.Unregister("node.name"))
+		assert.Equal(t, int64(0), cfg.lazyPending.Load(), "lock-free reads resume once nothing is pending")
+	})
+
+	t.Run("FuncReadsConfig", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("node.host", "db1"))
+		require.NoError(t, cfg.RegisterFunc("node.id", func() any {
+			host, _ := cfg.Get("node.host")
+			return host.(string) + "-1"
+		}))
+
+		done := make(chan any, 1)
+		go func() {
+			value, _ := cfg.Get("node.id")
+			done <- value
+		}()
+		select {
+		case value := <-done:
+			assert.Equal(t, "db1-1", value)
+		case <-time.After(time.Second):
+			t.Fatal("default function reading the config deadlocked")
+		}
+	})
+
+	t.Run("NilFunc", func(t *testing.T) {
+		assert.Error(t, New().RegisterFunc("node.id", nil))
+	})
+}
+
+// TestGetOrRegister tests atomic register-if-absent access
+func TestGetOrRegister(t *testing.T) {
+	t.Run("RegistersThenReturnsOverride", func(t *testing.T) {
+		cfg := New()
+		value, err := cfg.GetOrRegister("plugin.cache.size", int64(64))
+		require.NoError(t, err)
+		assert.Equal(t, int64(64), value)
+		assert.True(t, cfg.GetRegisteredPaths("plugin.")["plugin.cache.size"])
+
+		require.NoError(t, cfg.SetSource(SourceEnv, "plugin.cache.size", "128"))
+		value, err = cfg.GetOrRegister("plugin.cache.size", int64(32))
+		require.NoError(t, err)
+		assert.Equal(t, "128", value)
+
+		defaults := cfg.GetRegisteredPathsWithDefaults("plugin.")
+		assert.Equal(t, int64(64), defaults["plugin.cache.size"], "existing default kept")
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		cfg := New()
+		results := make(chan any, 20)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				value, err := cfg.GetOrRegister("plugin.id", i)
+				assert.NoError(t, err)
+				results <- value
+			}(i)
+		}
+		wg.Wait()
+		close(results)
+
+		registered, _ := cfg.Get("plugin.id")
+		for value := range results {
+			assert.Equal(t, registered, value, "all callers see the single registration")
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cfg := New()
+		_, err := cfg.GetOrRegister("bad..path", 1)
+		assert.ErrorIs(t, err, ErrInvalidPathSegment)
+
+		require.NoError(t, cfg.Register("existing", "value"))
+		cfg.Freeze()
+		value, err := cfg.GetOrRegister("existing", "other")
+		require.NoError(t, err, "reading a registered path works while frozen")
+		assert.Equal(t, "value", value)
+		_, err = cfg.GetOrRegister("new.path", 1)
+		assert.ErrorIs(t, err, ErrFrozen)
+	})
+	t.Run("ReturnsCopy", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.hosts", []string{"a", "b"}))
+
+		value, err := cfg.GetOrRegister("server.hosts", nil)
+		require.NoError(t, err)
+		value.([]string)[0] = "mutated"
+
+		hosts, _ := cfg.Get("server.hosts")
+		assert.Equal(t, []string{"a", "b"}, hosts)
+	})
+}
+
+// TestHasAndIsSet tests registration and override checks
+func TestHasAndIsSet(t *testing.T) {
+	cfg := New()
+	require.NoError(t, cfg.Register("server.port", 8080))
+	require.NoError(t, cfg.Register("server.host", "localhost"))
+
+	// Registered with only a default
+	assert.True(t, cfg.Has("server.port"))
+	assert.False(t, cfg.IsSet("server.port"))
+
+	// Registered and overridden, even with the default's value
+	require.NoError(t, cfg.SetSource(SourceFile, "server.host", "localhost"))
+	assert.True(t, cfg.Has("server.host"))
+	assert.True(t, cfg.IsSet("server.host"))
+
+	// Unregistered
+	assert.False(t, cfg.Has("server.missing"))
+	assert.False(t, cfg.IsSet("server.missing"))
+	assert.False(t, cfg.Has("server"), "parent segments are not paths")
+
+	// Unsetting the source clears IsSet
+	require.NoError(t, cfg.UnsetSource(SourceFile, "server.host"))
+	assert.False(t, cfg.IsSet("server.host"))
+}
+
+// TestCopyOnRead tests that returned slices and maps are detached from stored values
+func TestCopyOnRead(t *testing.T) {
+	cfg := New()
+	require.NoError(t, cfg.Register("metadata", map[string]any{"version": "1.0", "tags": []any{"a"}}))
+	require.NoError(t, cfg.Register("ports", []int{80, 443}))
+	require.NoError(t, cfg.Register("servers", []any{map[string]any{"host": "a"}}))
+	require.NoError(t, cfg.SetSource(SourceFile, "metadata", map[string]any{"version": "1.1", "tags": []any{"b"}}))
+
+	t.Run("Get", func(t *testing.T) {
+		value, _ := cfg.Get("metadata")
+		metadata := value.(map[string]any)
+		metadata["version"] = "mutated"
+		metadata["tags"].([]any)[0] = "mutated"
+		metadata["extra"] = true
+
+		again, _ := cfg.Get("metadata")
+		assert.Equal(t, map[string]any{"version": "1.1", "tags": []any{"b"}}, again)
+
+		ports, _ := cfg.Get("ports")
+		ports.([]int)[0] = 8080
+		again, _ = cfg.Get("ports")
+		assert.Equal(t, []int{80, 443}, again)
+	})
+
+	t.Run("ElementPath", func(t *testing.T) {
+		server, ok := cfg.Get("servers[0]")
+		require.True(t, ok)
+		server.(map[string]any)["host"] = "mutated"
+
+		value, _ := cfg.Get("servers")
+		assert.Equal(t, []any{map[string]any{"host": "a"}}, value)
+	})
+
+	t.Run("GetSourceAndGetSources", func(t *testing.T) {
+		value, _ := cfg.GetSource("metadata", SourceFile)
+		value.(map[string]any)["version"] = "mutated"
+
+		sources := cfg.GetSources("metadata")
+		sources[SourceFile].(map[string]any)["tags"].([]any)[0] = "mutated"
+
+		stored, _ := cfg.GetSource("metadata", SourceFile)
+		assert.Equal(t, map[string]any{"version": "1.1", "tags": []any{"b"}}, stored)
+		current, _ := cfg.Get("metadata")
+		assert.Equal(t, stored, current)
+	})
+}
+
+// TestGetRawSource tests text forms of raw env strings and parsed file values
+func TestGetRawSource(t *testing.T) {
+	t.Setenv("RAW_SERVER_PORT", "08080")
+	t.Setenv("RAW_TIMEOUT", "90s")
+
+	cfg := New()
+	cfg.Register("server.port", 8080)
+	cfg.Register("timeout", 30*time.Second)
+	cfg.Register("hosts", []string{"localhost"})
+
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte("hosts = [\"a\", \"b\"]\ntimeout = \"45s\"\n[server]\nport = 9090\n"), 0644))
+	require.NoError(t, cfg.LoadFile(configPath))
+	require.NoError(t, cfg.LoadEnv("RAW_"))
+
+	// Env strings are returned verbatim
+	raw, ok := cfg.GetRawSource("server.port", SourceEnv)
+	assert.True(t, ok)
+	assert.Equal(t, "08080", raw)
+
+	// Parsed file values are stringified
+	fileValue, _ := cfg.GetSource("server.port", SourceFile)
+	assert.Equal(t, int64(9090), fileValue)
+	raw, ok = cfg.GetRawSource("server.port", SourceFile)
+	assert.True(t, ok)
+	assert.Equal(t, "9090", raw)
+
+	raw, _ = cfg.GetRawSource("hosts", SourceFile)
+	assert.Equal(t, "a,b", raw)
+
+	require.NoError(t, cfg.SetSource(SourceCLI, "timeout", 2*time.Minute))
+	raw, _ = cfg.GetRawSource("timeout", SourceCLI)
+	assert.Equal(t, "2m0s", raw)
+
+	// Sources without a value report false
+	_, ok = cfg.GetRawSource("hosts", SourceEnv)
+	assert.False(t, ok)
+}
+
+// TestSourceTimestamp tests per-source timestamps across loads and writes
+func TestSourceTimestamp(t *testing.T) {
+	t.Setenv("TS_LOG_LEVEL", "debug")
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte("[server]\nport = 9090\n"), 0644))
+
+	cfg := New()
+	cfg.Register("server.port", 8080)
+	cfg.Register("log.level", "info")
+
+	before := time.Now()
+	require.NoError(t, cfg.LoadFile(configPath))
+	require.NoError(t, cfg.LoadEnv("TS_"))
+
+	fileTime, ok := cfg.SourceTimestamp("server.port", SourceFile)
+	require.True(t, ok)
+	assert.False(t, fileTime.Before(before))
+	envTime, ok := cfg.SourceTimestamp("log.level", SourceEnv)
+	require.True(t, ok)
+
+	// Sources without a value have no timestamp
+	_, ok = cfg.SourceTimestamp("server.port", SourceEnv)
+	assert.False(t, ok)
+	_, ok = cfg.SourceTimestamp("missing", SourceFile)
+	assert.False(t, ok)
+
+	t.Run("FileReloadLeavesEnv", func(t *testing.T) {
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, cfg.LoadFile(configPath))
+
+		reloaded, _ := cfg.SourceTimestamp("server.port", SourceFile)
+		assert.True(t, reloaded.After(fileTime), "file timestamp updates on reload")
+		unchanged, _ := cfg.SourceTimestamp("log.level", SourceEnv)
+		assert.Equal(t, envTime, unchanged, "env timestamp is untouched by a file reload")
+	})
+
+	t.Run("SetAndUnset", func(t *testing.T) {
+		require.NoError(t, cfg.SetSource(SourceCLI, "server.port", 7070))
+		cliTime, ok := cfg.SourceTimestamp("server.port", SourceCLI)
+		assert.True(t, ok)
+		assert.False(t, cliTime.Before(envTime))
+
+		require.NoError(t, cfg.UnsetSource(SourceCLI, "server.port"))
+		_, ok = cfg.SourceTimestamp("server.port", SourceCLI)
+		assert.False(t, ok)
+
+		clone := cfg.Clone()
+		cloned, _ := clone.SourceTimestamp("log.level", SourceEnv)
+		assert.Equal(t, envTime, cloned)
+
+		require.NoError(t, cfg.ResetSource(SourceEnv))
+		_, ok = cfg.SourceTimestamp("log.level", SourceEnv)
+		assert.False(t, ok)
+	})
 }
\ No newline at end of file
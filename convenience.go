@@ -2,12 +2,20 @@
 package config
 
 import (
+	"encoding"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/mitchellh/mapstructure"
+	"io"
+	"maps"
 	"os"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -56,7 +64,10 @@ func MustQuick(structDefaults any, envPrefix, configFile string) *Config {
 	return cfg
 }
 
-// GenerateFlags creates flag.FlagSet entries for all registered paths
+// GenerateFlags creates flag.FlagSet entries for all registered paths.
+// Scalars, time.Duration, slices of scalars (comma-separated), and types with a
+// text or string form are supported. Paths with other defaults, such as maps,
+// structs, or nil, are skipped. The usage struct tag provides the description.
 func (c *Config) GenerateFlags() *flag.FlagSet {
 	fs := flag.NewFlagSet("config", flag.ContinueOnError)
 
@@ -64,36 +75,99 @@ func (c *Config) GenerateFlags() *flag.FlagSet {
 	defer c.mutex.RUnlock()
 
 	for path, item := range c.items {
+		usage := item.usage
+		if usage == "" {
+			usage = fmt.Sprintf("Config: %s", path)
+		}
+
 		// Create flag based on default value type
 		switch v := item.defaultValue.(type) {
 		case bool:
-			fs.Bool(path, v, fmt.Sprintf("Config: %s", path))
+			fs.Bool(path, v, usage)
 		case int64:
-			fs.Int64(path, v, fmt.Sprintf("Config: %s", path))
+			fs.Int64(path, v, usage)
 		case int:
-			fs.Int(path, v, fmt.Sprintf("Config: %s", path))
+			fs.Int(path, v, usage)
 		case float64:
-			fs.Float64(path, v, fmt.Sprintf("Config: %s", path))
+			fs.Float64(path, v, usage)
 		case string:
-			fs.String(path, v, fmt.Sprintf("Config: %s", path))
+			fs.String(path, v, usage)
+		case time.Duration:
+			fs.Duration(path, v, usage)
 		default:
-			// For other types, use string flag
-			fs.String(path, fmt.Sprintf("%v", v), fmt.Sprintf("Config: %s", path))
+			if sf := newSliceFlag(v); sf != nil {
+				fs.Var(sf, path, usage)
+			} else if def, ok := flagDefault(v); ok {
+				fs.String(path, def, usage)
+			}
+			// Unsupported types (maps, structs, nil) get no flag
+		}
+	}
+
+	// Alias flags share the canonical flag's value
+	for alias, path := range c.aliases {
+		if f := fs.Lookup(path); f != nil {
+			fs.Var(f.Value, alias, f.Usage)
 		}
 	}
 
 	return fs
 }
 
+// PrintUsage writes one line per registered path, sorted by path, in the form
+// "--path (type, default: X) usage". Sensitive paths omit their default.
+func (c *Config) PrintUsage(w io.Writer) {
+	fmt.Fprint(w, c.UsageString())
+}
+
+// UsageString returns the output of PrintUsage as a string
+func (c *Config) UsageString() string {
+	c.mutex.RLock()
+	paths := make([]string, 0, len(c.items))
+	for path := range c.items {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	for _, path := range paths {
+		item := c.items[path]
+		b.WriteString("--" + path + " (")
+		if item.defaultValue == nil {
+			b.WriteString("any")
+		} else {
+			b.WriteString(fmt.Sprintf("%T", item.defaultValue))
+			if !item.sensitive {
+				def := textDefault(item.defaultValue)
+				if _, isString := item.defaultValue.(string); isString {
+					def = strconv.Quote(def)
+				}
+				b.WriteString(", default: " + def)
+			}
+		}
+		b.WriteString(")")
+		if item.usage != "" {
+			b.WriteString(" " + item.usage)
+		}
+		b.WriteString("\n")
+	}
+	c.mutex.RUnlock()
+
+	return b.String()
+}
+
 // BindFlags updates configuration from parsed flag.FlagSet
 func (c *Config) BindFlags(fs *flag.FlagSet) error {
 	var errors []error
 	needsInvalidation := false
 
 	fs.Visit(func(f *flag.Flag) {
-		value := f.Value.String()
+		var value any = f.Value.String()
+		if sf, ok := f.Value.(*sliceFlag); ok {
+			value = sf.Get() // Typed slice
+		}
 		// Let mapstructure handle type conversion
-		if err := c.SetSource(SourceCLI, f.Name, value); err != nil {
+		if err := c.SetSource(SourceCLI, c.resolveAlias(f.Name), value); err != nil {
 			errors = append(errors, fmt.Errorf("flag %s: %w", f.Name, err))
 		} else {
 			needsInvalidation = true
@@ -111,6 +185,545 @@ func (c *Config) BindFlags(fs *flag.FlagSet) error {
 	return nil
 }
 
+// BindToFlagSet defines a flag on fs for each registered path whose Set writes the
+// parsed value straight to SourceCLI, so fs.Parse alone populates the config. Flag
+// types follow the registered defaults as in GenerateFlags, and aliases get flags
+// of their own. Returns an error, defining no flags, if a name is already defined in fs.
+func (c *Config) BindToFlagSet(fs *flag.FlagSet) error {
+	flags := c.configFlags()
+	names := slices.Sorted(maps.Keys(flags))
+	for _, name := range names {
+		if fs.Lookup(name) != nil {
+			return fmt.Errorf("flag %s already defined", name)
+		}
+	}
+	for _, name := range names {
+		fs.Var(flags[name], name, flags[name].usage)
+	}
+	return nil
+}
+
+// RegisterFlagsFunc calls register for each path and alias that would get a flag,
+// sorted by name, with the text form of its default and its usage. It lets flag
+// libraries such as spf13/pflag define the flags without this package importing them:
+//
+//	cfg.RegisterFlagsFunc(func(name, value, usage string) {
+//		cmd.Flags().String(name, value, usage)
+//	})
+func (c *Config) RegisterFlagsFunc(register func(name string, value string, usage string)) {
+	flags := c.configFlags()
+	for _, name := range slices.Sorted(maps.Keys(flags)) {
+		f := flags[name]
+		register(name, f.defValue, f.usage)
+	}
+}
+
+// ApplyFlagValues sets SourceCLI values for the flags defined by RegisterFlagsFunc.
+// get returns the parsed text of a flag and whether the user set it, such as a
+// pflag Changed check. Values are parsed as the registered default's type; when
+// both a path and its alias are set, the path wins. Every flag that fails to parse
+// is reported in the joined error.
+func (c *Config) ApplyFlagValues(get func(name string) (string, bool)) error {
+	flags := c.configFlags()
+	applied := make(map[string]bool)
+	var errs []error
+
+	// Canonical names first, so aliases only fill paths not set directly
+	names := slices.SortedFunc(maps.Keys(flags), func(a, b string) int {
+		aliasA, aliasB := flags[a].path != a, flags[b].path != b
+		if aliasA != aliasB {
+			if aliasA {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a, b)
+	})
+	for _, name := range names {
+		f := flags[name]
+		if applied[f.path] {
+			continue
+		}
+		value, ok := get(name)
+		if !ok {
+			continue
+		}
+		if err := f.Set(value); err != nil {
+			errs = append(errs, fmt.Errorf("flag %s: %w", name, err))
+			continue
+		}
+		applied[f.path] = true
+	}
+
+	return errors.Join(errs...)
+}
+
+// configFlags returns a flag for each path with a supported default and for each
+// alias of one, keyed by flag name
+func (c *Config) configFlags() map[string]*configFlag {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	flags := make(map[string]*configFlag)
+	for path, item := range c.items {
+		if f := newConfigFlag(c, path, item); f != nil {
+			flags[path] = f
+		}
+	}
+	for alias, path := range c.aliases {
+		if f, ok := flags[path]; ok {
+			flags[alias] = f
+		}
+	}
+	return flags
+}
+
+// configFlag is a flag.Value that writes each parsed value to a path at SourceCLI
+type configFlag struct {
+	config   *Config
+	path     string
+	usage    string
+	defValue string // Text form of the registered default
+	isBool   bool
+	parse    func(string) (any, error)
+}
+
+// newConfigFlag returns a flag for an item's default type, or nil if the type gets no flag.
+// Must be called with the lock held.
+func newConfigFlag(c *Config, path string, item configItem) *configFlag {
+	f := &configFlag{config: c, path: path, usage: item.usage, defValue: textDefault(item.defaultValue)}
+	if f.usage == "" {
+		f.usage = fmt.Sprintf("Config: %s", path)
+	}
+
+	def := item.defaultValue
+	if sf := newSliceFlag(def); sf != nil {
+		f.parse = func(s string) (any, error) {
+			if err := sf.Set(s); err != nil {
+				return nil, err
+			}
+			return sf.Get(), nil
+		}
+		return f
+	}
+
+	switch reflect.ValueOf(def).Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		// Parse as a one-element slice to reuse the element conversions, including durations
+		sliceType := reflect.SliceOf(reflect.TypeOf(def))
+		f.isBool = sliceType.Elem().Kind() == reflect.Bool
+		f.parse = func(s string) (any, error) {
+			v, err := convertElems([]string{s}, sliceType)
+			if err != nil {
+				return nil, err
+			}
+			return reflect.ValueOf(v).Index(0).Interface(), nil
+		}
+		return f
+	}
+
+	if _, ok := flagDefault(def); ok {
+		// Text forms are decoded to the target type on Scan, as with BindFlags
+		f.parse = func(s string) (any, error) { return s, nil }
+		return f
+	}
+	return nil
+}
+
+// String returns the text form of the path's current value
+func (f *configFlag) String() string {
+	if f == nil || f.config == nil {
+		return ""
+	}
+	value, _ := f.config.Get(f.path)
+	return textDefault(value)
+}
+
+// Set parses s as the path's type and stores it at SourceCLI
+func (f *configFlag) Set(s string) error {
+	value, err := f.parse(s)
+	if err != nil {
+		return err
+	}
+	return f.config.SetSource(SourceCLI, f.path, value)
+}
+
+// IsBoolFlag lets bool paths be given as -name without a value
+func (f *configFlag) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// sliceFlag is a flag.Value for slice-typed paths, parsed from comma-separated values
+type sliceFlag struct {
+	sliceType reflect.Type
+	elems     []string
+}
+
+// newSliceFlag creates a slice flag for a slice of scalars, or nil for other values
+func newSliceFlag(v any) *sliceFlag {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil
+	}
+	switch rv.Type().Elem().Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return nil
+	}
+
+	sf := &sliceFlag{sliceType: rv.Type()}
+	for i := 0; i < rv.Len(); i++ {
+		sf.elems = append(sf.elems, fmt.Sprintf("%v", rv.Index(i).Interface()))
+	}
+	return sf
+}
+
+// String returns the comma-joined elements
+func (f *sliceFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(f.elems, ",")
+}
+
+// Set parses a comma-separated list, validating each element against the slice type
+func (f *sliceFlag) Set(s string) error {
+	var elems []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			elems = append(elems, part)
+		}
+	}
+	if _, err := convertElems(elems, f.sliceType); err != nil {
+		return err
+	}
+	f.elems = elems
+	return nil
+}
+
+// Get returns the elements as a slice of the default's type
+func (f *sliceFlag) Get() any {
+	v, _ := convertElems(f.elems, f.sliceType)
+	return v
+}
+
+// convertElems converts string elements into a slice of the given type
+func convertElems(elems []string, sliceType reflect.Type) (any, error) {
+	out := reflect.MakeSlice(sliceType, len(elems), len(elems))
+	elemType := sliceType.Elem()
+
+	for i, s := range elems {
+		elem := out.Index(i)
+		if elemType == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid duration element %q", s)
+			}
+			elem.SetInt(int64(d))
+			continue
+		}
+		switch elemType.Kind() {
+		case reflect.String:
+			elem.SetString(s)
+		case reflect.Bool:
+			b, err := parseBool(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bool element %q", s)
+			}
+			elem.SetBool(b)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(s, 10, elemType.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer element %q", s)
+			}
+			elem.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseUint(s, 10, elemType.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("invalid unsigned element %q", s)
+			}
+			elem.SetUint(n)
+		case reflect.Float32, reflect.Float64:
+			n, err := strconv.ParseFloat(s, elemType.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("invalid float element %q", s)
+			}
+			elem.SetFloat(n)
+		default:
+			return nil, fmt.Errorf("unsupported slice element type %v", elemType)
+		}
+	}
+
+	return out.Interface(), nil
+}
+
+// flagDefault returns the string form of a default for a string flag,
+// or false if the value has no faithful string representation
+func flagDefault(v any) (string, bool) {
+	switch t := v.(type) {
+	case nil:
+		return "", false
+	case encoding.TextMarshaler:
+		text, err := t.MarshalText()
+		if err != nil {
+			return "", false
+		}
+		return string(text), true
+	case fmt.Stringer:
+		return t.String(), true
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%v", v), true
+	default:
+		return "", false
+	}
+}
+
+// isSet reports whether an item's value differs from its default, or any source
+// provided a value
+func isSet(item configItem) bool {
+	if !reflect.DeepEqual(item.currentValue, item.defaultValue) {
+		return true
+	}
+	for _, val := range item.values {
+		if val != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isModified reports whether an item's value differs from its default, comparing
+// numbers in canonical form so int(8080) and int64(8080) are equal
+func isModified(item configItem) bool {
+	return !reflect.DeepEqual(normalizeNumber(copyValue(item.currentValue)), normalizeNumber(copyValue(item.defaultValue)))
+}
+
+// ModifiedPaths returns the sorted registered paths whose current value differs
+// from the registered default
+func (c *Config) ModifiedPaths() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var paths []string
+	for path, item := range c.items {
+		if isModified(item) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// ModifiedValues returns copies of the current values of the paths reported by ModifiedPaths
+func (c *Config) ModifiedValues() map[string]any {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	values := make(map[string]any)
+	for path, item := range c.items {
+		if isModified(item) {
+			values[path] = copyValue(item.currentValue)
+		}
+	}
+	return values
+}
+
+// ItemInfo describes a registered path as visited by Range. Values are copies.
+type ItemInfo struct {
+	Default   any            // Registered default value
+	Current   any            // Resolved value
+	Sources   map[Source]any // Value from each source that has one
+	Source    Source         // Source of the resolved value, SourceDefault if no source has one
+	Usage     string         // Description from the usage struct tag
+	Required  bool           // Set by RegisterRequired or the required struct tag
+	Sensitive bool           // Set by the sensitive struct tag
+}
+
+// Range calls fn for each registered path in sorted order until fn returns false.
+// It visits a copy taken under a read lock, so fn may call other Config methods;
+// changes made meanwhile are not reflected in later visits.
+func (c *Config) Range(fn func(path string, info ItemInfo) bool) {
+	c.resolveLazyDefaults()
+	c.mutex.RLock()
+	paths := slices.Sorted(maps.Keys(c.items))
+	infos := make([]ItemInfo, len(paths))
+	for i, path := range paths {
+		item := c.items[path]
+		info := ItemInfo{
+			Default:   copyValue(item.defaultValue),
+			Current:   copyValue(item.currentValue),
+			Sources:   make(map[Source]any, len(item.values)),
+			Source:    SourceDefault,
+			Usage:     item.usage,
+			Required:  item.required,
+			Sensitive: item.sensitive,
+		}
+		for source, value := range item.values {
+			info.Sources[source] = copyValue(value)
+		}
+		// Mirror computeValue: first non-nil source value in precedence order wins
+		for _, source := range c.options.Sources {
+			if value, exists := item.values[source]; exists && value != nil {
+				info.Source = source
+				break
+			}
+		}
+		infos[i] = info
+	}
+	c.mutex.RUnlock()
+
+	for i, path := range paths {
+		if !fn(path, infos[i]) {
+			return
+		}
+	}
+}
+
+// ValidateRequired checks that every path registered with RegisterRequired or the
+// required struct tag, and every path whose RegisterRequiredIf condition holds, has
+// a value from some source. All missing paths are returned in one *ValidationError.
+func (c *Config) ValidateRequired() error {
+	c.mutex.RLock()
+	decodeHook := c.getDecodeHook()
+	tagName := c.tagName
+
+	var v MultiValidator
+	for _, path := range slices.Sorted(maps.Keys(c.items)) {
+		item := c.items[path]
+		if isSet(item) {
+			continue
+		}
+		if item.required {
+			v.Add(path, c.requiredMessage(path, ""))
+			continue
+		}
+		for _, cond := range item.requiredIf {
+			condItem, exists := c.items[cond.path]
+			if exists && conditionHolds(condItem, cond.equals, tagName, decodeHook) {
+				v.Add(path, c.requiredMessage(path, fmt.Sprintf(" when %s is %v", cond.path, cond.describe())))
+				break
+			}
+		}
+	}
+	c.mutex.RUnlock()
+
+	return v.Err()
+}
+
+// requiredMessage returns the message for a missing required path, naming its env var
+// when one is recorded. Must be called with the lock held.
+func (c *Config) requiredMessage(path, when string) string {
+	if envVar, recorded := c.envNames[path]; recorded {
+		return fmt.Sprintf("is required%s (env %s)", when, envVar)
+	}
+	return "is required" + when
+}
+
+// conditionHolds reports whether the condition path's resolved value matches equals,
+// after converting it to the type of equals. A nil equals matches any non-zero value.
+func conditionHolds(item configItem, equals any, tagName string, decodeHook mapstructure.DecodeHookFunc) bool {
+	if equals == nil {
+		return item.currentValue != nil && !reflect.ValueOf(item.currentValue).IsZero()
+	}
+	if item.currentValue == nil {
+		return false
+	}
+	value, err := decodeAs(item.currentValue, reflect.TypeOf(equals), tagName, decodeHook)
+	return err == nil && reflect.DeepEqual(value, equals)
+}
+
+// ValidateTypes checks that every current value converts to the type of its default,
+// using the same decode hooks as Scan and GetTyped. All failures are reported in one
+// error listing the offending paths. Paths with a nil default or value, and paths
+// registered from interface-typed fields, are skipped.
+func (c *Config) ValidateTypes() error {
+	c.mutex.RLock()
+	decodeHook := c.getDecodeHook()
+	tagName := c.tagName
+	type check struct {
+		path  string
+		value any
+		typ   reflect.Type
+	}
+	var checks []check
+	for path, item := range c.items {
+		if item.defaultValue == nil || item.currentValue == nil || item.untyped {
+			continue
+		}
+		checks = append(checks, check{path, item.currentValue, reflect.TypeOf(item.defaultValue)})
+	}
+	c.mutex.RUnlock()
+
+	sort.Slice(checks, func(i, j int) bool { return checks[i].path < checks[j].path })
+
+	var invalid []string
+	for _, ch := range checks {
+		if _, err := decodeAs(ch.value, ch.typ, tagName, decodeHook); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s (%v as %s: %v)", ch.path, ch.value, ch.typ, err))
+		}
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid configuration types: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// decodeAs decodes value into a new value of type t, as GetTyped does
+func decodeAs(value any, t reflect.Type, tagName string, decodeHook mapstructure.DecodeHookFunc) (any, error) {
+	targetType := reflect.StructOf([]reflect.StructField{{
+		Name: "Value",
+		Type: t,
+		Tag:  `mapstructure:"value"`,
+	}})
+	target := reflect.New(targetType).Interface()
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           target,
+		TagName:          tagName,
+		WeaklyTypedInput: true,
+		DecodeHook:       decodeHook,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(map[string]any{"value": value}); err != nil {
+		// Report the conversion failure without the wrapper field's name
+		var decodeErr *mapstructure.Error
+		if errors.As(err, &decodeErr) && len(decodeErr.Errors) > 0 {
+			return nil, errors.New(strings.ReplaceAll(strings.Join(decodeErr.Errors, "; "), "'Value' ", ""))
+		}
+		return nil, err
+	}
+	return reflect.ValueOf(target).Elem().Field(0).Interface(), nil
+}
+
+// textDefault formats a default the way it would be written in an env var or flag
+func textDefault(v any) string {
+	if v == nil {
+		return ""
+	}
+	if sf := newSliceFlag(v); sf != nil {
+		return sf.String()
+	}
+	if def, ok := flagDefault(v); ok {
+		return def
+	}
+	return fmt.Sprintf("%v", v)
+}
+
 // Validate checks that all required configuration values are set
 // A value is considered "set" if it differs from its default value
 func (c *Config) Validate(required ...string) error {
@@ -126,17 +739,10 @@ func (c *Config) Validate(required ...string) error {
 			continue
 		}
 
-		// Check if value equals default (indicating not set)
-		if reflect.DeepEqual(item.currentValue, item.defaultValue) {
-			// Check if any source provided a value
-			hasValue := false
-			for _, val := range item.values {
-				if val != nil {
-					hasValue = true
-					break
-				}
-			}
-			if !hasValue {
+		if !isSet(item) {
+			if envVar, recorded := c.envNames[path]; recorded {
+				missing = append(missing, fmt.Sprintf("%s (env %s)", path, envVar))
+			} else {
 				missing = append(missing, path)
 			}
 		}
@@ -151,6 +757,7 @@ func (c *Config) Validate(required ...string) error {
 
 // Debug returns a formatted string showing all configuration values and their sources
 func (c *Config) Debug() string {
+	c.resolveLazyDefaults()
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
@@ -172,6 +779,51 @@ func (c *Config) Debug() string {
 	return b.String()
 }
 
+// Explain returns a formatted trace of how the value for a single path was resolved,
+// listing each source in precedence order and marking the winning one
+func (c *Config) Explain(path string) string {
+	c.resolveLazyDefaults(path)
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	item, registered := c.items[path]
+	if !registered {
+		return fmt.Sprintf("Path %s is not registered\n", path)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Resolution for %s:\n", path))
+	b.WriteString(fmt.Sprintf("Precedence: %v\n", c.options.Sources))
+
+	// Mirror computeValue: first non-nil source value in precedence order wins
+	winner := SourceDefault
+	for _, source := range c.options.Sources {
+		if source == SourceDefault {
+			continue // Default is always the final fallback, reported below
+		}
+
+		val, exists := item.values[source]
+		switch {
+		case !exists || val == nil:
+			b.WriteString(fmt.Sprintf("  %s: (not set)\n", source))
+		case winner == SourceDefault:
+			winner = source
+			b.WriteString(fmt.Sprintf("  %s: %v (winner)\n", source, val))
+		default:
+			b.WriteString(fmt.Sprintf("  %s: %v (shadowed)\n", source, val))
+		}
+	}
+
+	if winner == SourceDefault {
+		b.WriteString(fmt.Sprintf("  %s: %v (winner)\n", SourceDefault, item.defaultValue))
+	} else {
+		b.WriteString(fmt.Sprintf("  %s: %v (shadowed)\n", SourceDefault, item.defaultValue))
+	}
+	b.WriteString(fmt.Sprintf("Resolved: %v (from %s)\n", item.currentValue, winner))
+
+	return b.String()
+}
+
 // Dump writes the current configuration to stdout in TOML format
 func (c *Config) Dump() error {
 	c.mutex.RLock()
@@ -192,11 +844,14 @@ func (c *Config) Clone() *Config {
 	defer c.mutex.RUnlock()
 
 	clone := &Config{
-		items:    make(map[string]configItem),
-		options:  c.options,
-		fileData: make(map[string]any),
-		envData:  make(map[string]any),
-		cliData:  make(map[string]any),
+		items:        make(map[string]configItem),
+		options:      c.options,
+		maxValueSize: c.maxValueSize,
+		fileData:     make(map[string]any),
+		envData:      make(map[string]any),
+		cliData:      make(map[string]any),
+		envNames:     make(map[string]string),
+		aliases:      make(map[string]string),
 	}
 
 	// Deep copy items
@@ -205,13 +860,20 @@ func (c *Config) Clone() *Config {
 			defaultValue: item.defaultValue,
 			currentValue: item.currentValue,
 			values:       make(map[Source]any),
+			usage:        item.usage,
+			required:     item.required,
+			requiredIf:   slices.Clone(item.requiredIf),
+			sensitive:    item.sensitive,
+			lazyDefault:  item.lazyDefault,
+			untyped:      item.untyped,
 		}
 
 		for source, value := range item.values {
 			newItem.values[source] = value
 		}
+		newItem.setTimes = maps.Clone(item.setTimes)
 
-		clone.items[path] = newItem
+		clone.storeItem(path, newItem)
 	}
 
 	// Copy cache data
@@ -224,10 +886,56 @@ func (c *Config) Clone() *Config {
 	for k, v := range c.cliData {
 		clone.cliData[k] = v
 	}
+	for source, data := range c.customData {
+		if clone.customData == nil {
+			clone.customData = make(map[Source]map[string]any)
+		}
+		clone.customData[source] = maps.Clone(data)
+	}
+	for k, v := range c.envNames {
+		clone.envNames[k] = v
+	}
+	for k, v := range c.aliases {
+		clone.aliases[k] = v
+	}
+	clone.preDecodeHooks = append(clone.preDecodeHooks, c.preDecodeHooks...)
+	clone.postDecodeHooks = append(clone.postDecodeHooks, c.postDecodeHooks...)
+	clone.log.Store(c.log.Load())
 
 	return clone
 }
 
+// Equal reports whether c and other have the same registered paths with the same
+// resolved values, regardless of which source provided each value. Numbers are
+// compared in canonical form, so int(8080) from a default equals int64(8080) from a file.
+func (c *Config) Equal(other *Config) bool {
+	if c == other {
+		return true
+	}
+	if other == nil {
+		return false
+	}
+
+	// Each config is read under its own lock, so comparing two configs cannot deadlock
+	c.resolveLazyDefaults()
+	other.resolveLazyDefaults()
+	values, otherValues := c.snapshot(), other.snapshot()
+	if len(values) != len(otherValues) {
+		return false
+	}
+	for path, value := range values {
+		otherValue, exists := otherValues[path]
+		if !exists {
+			return false
+		}
+		// Copies keep normalization from modifying the stored maps and slices
+		if !reflect.DeepEqual(normalizeNumber(copyValue(value)), normalizeNumber(copyValue(otherValue))) {
+			return false
+		}
+	}
+	return true
+}
+
 // QuickTyped creates a fully configured Config with a typed target
 func QuickTyped[T any](target *T, envPrefix, configFile string) (*Config, error) {
 	return NewBuilder().
@@ -245,7 +953,10 @@ func GetTyped[T any](c *Config, path string) (T, error) {
 
 	rawValue, exists := c.Get(path)
 	if !exists {
-		return zero, fmt.Errorf("path %q not found", path)
+		return zero, errNotRegistered(path)
+	}
+	if rawValue == nil && c.isUntyped(path) {
+		return zero, fmt.Errorf("path %q is an untyped field with no value to convert to %T", path, zero)
 	}
 
 	// Prepare the input map and target struct for the decoder.
@@ -255,11 +966,15 @@ func GetTyped[T any](c *Config, path string) (T, error) {
 	}
 
 	// Create a new decoder configured with the same hooks as the main config.
+	c.mutex.RLock()
+	decodeHook := c.getDecodeHook()
+	c.mutex.RUnlock()
+
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		Result:           &target,
 		TagName:          c.tagName,
 		WeaklyTypedInput: true,
-		DecodeHook:       c.getDecodeHook(),
+		DecodeHook:       decodeHook,
 		Metadata:         nil,
 	})
 	if err != nil {
@@ -274,6 +989,66 @@ func GetTyped[T any](c *Config, path string) (T, error) {
 	return target.Value, nil
 }
 
+// isUntyped reports whether path was registered from an interface-typed struct field
+func (c *Config) isUntyped(path string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.items[path].untyped
+}
+
+// GetCoerced returns the value at path converted to the type of its registered
+// default, using the same decode hooks as GetTyped, so a port reads as int64 whether
+// it came from an env string or a TOML number. Paths with a nil default, paths
+// registered from interface-typed fields, and element paths are returned as Get
+// returns them. The bool is false if the path is not
+// registered or the value cannot be converted.
+func (c *Config) GetCoerced(path string) (any, bool) {
+	c.resolveLazyDefaults(path)
+	c.mutex.RLock()
+	item, registered := c.items[path]
+	decodeHook := c.getDecodeHook()
+	c.mutex.RUnlock()
+
+	if !registered || item.defaultValue == nil || item.currentValue == nil || item.untyped {
+		return c.Get(path)
+	}
+
+	value, err := decodeAs(item.currentValue, reflect.TypeOf(item.defaultValue), c.tagName, decodeHook)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// MustGetTyped is like GetTyped but panics if the path is unregistered or conversion fails
+func MustGetTyped[T any](c *Config, path string) T {
+	value, err := GetTyped[T](c, path)
+	if err != nil {
+		panic(fmt.Sprintf("config value %s unavailable: %v", path, err))
+	}
+	return value
+}
+
+// MustString returns the value at path as a string, panicking on failure
+func (c *Config) MustString(path string) string {
+	return MustGetTyped[string](c, path)
+}
+
+// MustInt64 returns the value at path as an int64, panicking on failure
+func (c *Config) MustInt64(path string) int64 {
+	return MustGetTyped[int64](c, path)
+}
+
+// MustBool returns the value at path as a bool, panicking on failure
+func (c *Config) MustBool(path string) bool {
+	return MustGetTyped[bool](c, path)
+}
+
+// MustFloat64 returns the value at path as a float64, panicking on failure
+func (c *Config) MustFloat64(path string) float64 {
+	return MustGetTyped[float64](c, path)
+}
+
 // ScanTyped is a generic wrapper around Scan. It allocates a new instance of type T,
 // populates it with configuration data from the given base path, and returns a pointer to it.
 func ScanTyped[T any](c *Config, basePath ...string) (*T, error) {
@@ -2,7 +2,10 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"flag"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -155,6 +158,189 @@ func TestFlagGeneration(t *testing.T) {
 		assert.Equal(t, "true", debug)
 	})
 
+	t.Run("TypedFlags", func(t *testing.T) {
+		type FlagConfig struct {
+			Timeout time.Duration  `toml:"timeout" usage:"Request timeout"`
+			Tags    []string       `toml:"tags"`
+			Ports   []int          `toml:"ports"`
+			Extra   map[string]any `toml:"extra"`
+		}
+
+		cfg := New()
+		require.NoError(t, cfg.RegisterStruct("", FlagConfig{
+			Timeout: 5 * time.Second,
+			Tags:    []string{"a"},
+			Ports:   []int{80},
+		}))
+
+		fs := cfg.GenerateFlags()
+
+		timeoutFlag := fs.Lookup("timeout")
+		require.NotNil(t, timeoutFlag)
+		assert.Equal(t, "5s", timeoutFlag.DefValue)
+		assert.Equal(t, "Request timeout", timeoutFlag.Usage)
+
+		portsFlag := fs.Lookup("ports")
+		require.NotNil(t, portsFlag)
+		assert.Equal(t, "80", portsFlag.DefValue)
+		assert.Equal(t, "Config: ports", portsFlag.Usage)
+
+		// Unsupported complex types get no flag
+		assert.Nil(t, fs.Lookup("extra"))
+
+		err := fs.Parse([]string{"-timeout=1m30s", "-tags=x, y", "-ports=8080,9090"})
+		require.NoError(t, err)
+		require.NoError(t, cfg.BindFlags(fs))
+
+		var result FlagConfig
+		require.NoError(t, cfg.Scan(&result))
+		assert.Equal(t, 90*time.Second, result.Timeout)
+		assert.Equal(t, []string{"x", "y"}, result.Tags)
+		assert.Equal(t, []int{8080, 9090}, result.Ports)
+
+		// Invalid slice elements are rejected at parse time
+		fs = cfg.GenerateFlags()
+		assert.Error(t, fs.Parse([]string{"-ports=80,abc"}))
+	})
+
+	t.Run("BindToFlagSet", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		cfg.Register("debug", false)
+		cfg.Register("timeout", 5*time.Second)
+		cfg.Register("ports", []int{80})
+		cfg.Register("extra", map[string]any{"key": "value"})
+		require.NoError(t, cfg.RegisterAlias("p", "server.port"))
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		require.NoError(t, cfg.BindToFlagSet(fs))
+
+		hostFlag := fs.Lookup("server.host")
+		require.NotNil(t, hostFlag)
+		assert.Equal(t, "localhost", hostFlag.DefValue)
+		assert.Equal(t, "Config: server.host", hostFlag.Usage)
+		assert.Equal(t, "5s", fs.Lookup("timeout").DefValue)
+		assert.NotNil(t, fs.Lookup("p"))
+		assert.Nil(t, fs.Lookup("extra"))
+
+		// Parsing alone writes typed values at SourceCLI
+		require.NoError(t, fs.Parse([]string{"-server.host=flaghost", "-p", "9090", "-debug", "-timeout=1m", "-ports=8080,9090"}))
+
+		host, _ := cfg.GetSource("server.host", SourceCLI)
+		assert.Equal(t, "flaghost", host)
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(9090), port)
+		debug, _ := cfg.Get("debug")
+		assert.Equal(t, true, debug)
+		timeout, _ := cfg.Get("timeout")
+		assert.Equal(t, time.Minute, timeout)
+		ports, _ := cfg.Get("ports")
+		assert.Equal(t, []int{8080, 9090}, ports)
+
+		// Invalid values fail the parse and leave the config unchanged
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		require.NoError(t, cfg.BindToFlagSet(fs))
+		assert.Error(t, fs.Parse([]string{"-server.port=abc"}))
+		port, _ = cfg.Get("server.port")
+		assert.Equal(t, int64(9090), port)
+
+		// Names already in the flag set are rejected before any flag is defined
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Bool("debug", false, "")
+		assert.Error(t, cfg.BindToFlagSet(fs))
+		assert.Nil(t, fs.Lookup("server.host"))
+	})
+
+	t.Run("FlagCallbacks", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		cfg.Register("tags", []string{"a", "b"})
+		cfg.Register("extra", map[string]any{"key": "value"})
+		require.NoError(t, cfg.RegisterAlias("p", "server.port"))
+
+		// A fake flag library records definitions and returns parsed values
+		type fakeFlag struct{ value, usage string }
+		defined := make(map[string]fakeFlag)
+		var order []string
+		cfg.RegisterFlagsFunc(func(name, value, usage string) {
+			defined[name] = fakeFlag{value, usage}
+			order = append(order, name)
+		})
+
+		assert.Equal(t, []string{"p", "server.host", "server.port", "tags"}, order)
+		assert.Equal(t, fakeFlag{"8080", "Config: server.port"}, defined["p"])
+		assert.Equal(t, "a,b", defined["tags"].value)
+
+		parsed := map[string]string{"server.host": "flaghost", "p": "7070", "tags": "x,y"}
+		get := func(name string) (string, bool) {
+			value, ok := parsed[name]
+			return value, ok
+		}
+		require.NoError(t, cfg.ApplyFlagValues(get))
+
+		host, _ := cfg.GetSource("server.host", SourceCLI)
+		assert.Equal(t, "flaghost", host)
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(7070), port)
+		tags, _ := cfg.Get("tags")
+		assert.Equal(t, []string{"x", "y"}, tags)
+
+		// The canonical name wins over its alias
+		parsed["server.port"] = "9090"
+		require.NoError(t, cfg.ApplyFlagValues(get))
+		port, _ = cfg.Get("server.port")
+		assert.Equal(t, int64(9090), port)
+
+		// Unparsable values are reported without stopping other flags
+		parsed = map[string]string{"server.port": "abc", "p": "xyz", "server.host": "other"}
+		err := cfg.ApplyFlagValues(get)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "flag server.port")
+		assert.Contains(t, err.Error(), "flag p")
+		host, _ = cfg.Get("server.host")
+		assert.Equal(t, "other", host)
+	})
+
+	t.Run("Aliases", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.port", 8080)
+		require.NoError(t, cfg.RegisterAlias("p", "server.port"))
+
+		// Duplicate aliases and unknown targets are rejected
+		assert.Error(t, cfg.RegisterAlias("p", "server.port"))
+		assert.Error(t, cfg.RegisterAlias("server.port", "server.port"))
+		assert.Error(t, cfg.RegisterAlias("x", "missing.path"))
+
+		fs := cfg.GenerateFlags()
+		aliasFlag := fs.Lookup("p")
+		require.NotNil(t, aliasFlag)
+		assert.Equal(t, "8080", aliasFlag.DefValue)
+
+		require.NoError(t, fs.Parse([]string{"-p", "9090"}))
+		require.NoError(t, cfg.BindFlags(fs))
+		short, _ := cfg.Get("server.port")
+
+		cfg2 := New()
+		cfg2.Register("server.port", 8080)
+		require.NoError(t, cfg2.RegisterAlias("p", "server.port"))
+		fs = cfg2.GenerateFlags()
+		require.NoError(t, fs.Parse([]string{"--server.port=9090"}))
+		require.NoError(t, cfg2.BindFlags(fs))
+		long, _ := cfg2.Get("server.port")
+
+		assert.Equal(t, "9090", short)
+		assert.Equal(t, short, long)
+
+		// Command-line loading resolves aliases too
+		cfg3 := cfg.Clone()
+		require.NoError(t, cfg3.LoadCLI([]string{"--p", "7070"}))
+		port, _ := cfg3.Get("server.port")
+		assert.Equal(t, "7070", port)
+	})
+
 	t.Run("BindFlagsError", func(t *testing.T) {
 		fs := flag.NewFlagSet("test", flag.ContinueOnError)
 		fs.String("unregistered.path", "value", "")
@@ -166,6 +352,41 @@ func TestFlagGeneration(t *testing.T) {
 	})
 }
 
+// TestPrintUsage tests the --help-style usage listing
+func TestPrintUsage(t *testing.T) {
+	type AppConfig struct {
+		Host    string        `toml:"host" usage:"Listen address"`
+		Port    int64         `toml:"port"`
+		Timeout time.Duration `toml:"timeout"`
+		Tags    []string      `toml:"tags"`
+		Token   string        `toml:"token" sensitive:"true"`
+	}
+
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("", &AppConfig{
+		Host:    "localhost",
+		Port:    8080,
+		Timeout: 5 * time.Second,
+		Tags:    []string{"a", "b"},
+		Token:   "secret",
+	}))
+	require.NoError(t, cfg.Register("extra", nil))
+
+	var buf bytes.Buffer
+	cfg.PrintUsage(&buf)
+
+	expected := `--extra (any)
+--host (string, default: "localhost") Listen address
+--port (int64, default: 8080)
+--tags ([]string, default: a,b)
+--timeout (time.Duration, default: 5s)
+--token (string)
+`
+	assert.Equal(t, expected, buf.String())
+	assert.Equal(t, buf.String(), cfg.UsageString())
+	assert.NotContains(t, buf.String(), "secret")
+}
+
 // TestValidation tests configuration validation
 func TestValidation(t *testing.T) {
 	cfg := New()
@@ -207,6 +428,64 @@ func TestValidation(t *testing.T) {
 	})
 }
 
+// TestValidateTypes tests checking current values against their default types
+func TestValidateTypes(t *testing.T) {
+	t.Run("ValidConfigPasses", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.port", int64(8080))
+		cfg.Register("server.timeout", 5*time.Second)
+		cfg.Register("server.hosts", []string{"a"})
+		cfg.Register("debug", false)
+		cfg.Register("extra", nil)
+		require.NoError(t, cfg.SetSource(SourceEnv, "server.port", "9090"))
+		require.NoError(t, cfg.SetSource(SourceEnv, "server.timeout", "1m"))
+		require.NoError(t, cfg.SetSource(SourceEnv, "server.hosts", "b,c"))
+		require.NoError(t, cfg.SetSource(SourceEnv, "extra", "anything"))
+		assert.NoError(t, cfg.ValidateTypes())
+	})
+
+	t.Run("NonNumericIntFails", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.port", int64(8080))
+		cfg.Register("server.timeout", 5*time.Second)
+		cfg.Register("server.hosts", []string{"a"})
+		cfg.Register("debug", false)
+		cfg.Register("extra", nil)
+		require.NoError(t, cfg.SetSource(SourceEnv, "server.port", "eighty"))
+		require.NoError(t, cfg.SetSource(SourceEnv, "server.timeout", "soon"))
+
+		err := cfg.ValidateTypes()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "server.port (eighty as int64")
+		assert.Contains(t, err.Error(), "server.timeout (soon as time.Duration")
+		assert.NotContains(t, err.Error(), "debug")
+	})
+
+	t.Run("Builder", func(t *testing.T) {
+		t.Setenv("APP_PORT", "not-a-port")
+		type AppConfig struct {
+			Port int64 `toml:"port"`
+		}
+
+		_, err := NewBuilder().
+			WithDefaults(&AppConfig{Port: 8080}).
+			WithEnvPrefix("APP_").
+			WithArgs(nil).
+			WithTypeValidation().
+			Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "port (not-a-port as int64")
+
+		// Without the option the bad value is only found by Scan
+		_, err = NewBuilder().
+			WithDefaults(&AppConfig{Port: 8080}).
+			WithEnvPrefix("APP_").
+			WithArgs(nil).
+			Build()
+		assert.NoError(t, err)
+	})
+}
+
 // TestDebugAndDump tests debug output functions
 func TestDebugAndDump(t *testing.T) {
 	cfg := New()
@@ -229,6 +508,25 @@ func TestDebugAndDump(t *testing.T) {
 		assert.Contains(t, debug, "env: envhost")
 	})
 
+	t.Run("Explain", func(t *testing.T) {
+		explain := cfg.Explain("server.host")
+
+		assert.Contains(t, explain, "Resolution for server.host")
+		assert.Contains(t, explain, "cli: (not set)")
+		assert.Contains(t, explain, "env: envhost (winner)")
+		assert.Contains(t, explain, "file: filehost (shadowed)")
+		assert.Contains(t, explain, "default: localhost (shadowed)")
+		assert.Contains(t, explain, "Resolved: envhost (from env)")
+
+		// Default wins when no source provides a value
+		cfg.Register("server.name", "svc")
+		explain = cfg.Explain("server.name")
+		assert.Contains(t, explain, "default: svc (winner)")
+		assert.Contains(t, explain, "Resolved: svc (from default)")
+
+		assert.Contains(t, cfg.Explain("missing.path"), "not registered")
+	})
+
 	t.Run("Dump", func(t *testing.T) {
 		// Capture stdout
 		oldStdout := os.Stdout
@@ -312,6 +610,22 @@ func TestGenericHelpers(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("MustAccessors", func(t *testing.T) {
+		cfg.Register("limits.ratio", 0.75)
+
+		assert.Equal(t, "localhost", cfg.MustString("server.host"))
+		assert.Equal(t, int64(8080), cfg.MustInt64("server.port"))
+		assert.True(t, cfg.MustBool("features.dark_mode"))
+		assert.Equal(t, 0.75, cfg.MustFloat64("limits.ratio"))
+		assert.Equal(t, 5*time.Second, MustGetTyped[time.Duration](cfg, "timeouts.read"))
+
+		assert.PanicsWithValue(t,
+			`config value missing.path unavailable: path missing.path is not registered`,
+			func() { cfg.MustString("missing.path") })
+
+		assert.Panics(t, func() { cfg.MustInt64("server.host") }, "conversion failure should panic")
+	})
+
 	t.Run("ScanTyped", func(t *testing.T) {
 		type ServerConfig struct {
 			Host string `toml:"host"`
@@ -324,4 +638,343 @@ func TestGenericHelpers(t *testing.T) {
 		assert.Equal(t, "localhost", serverConf.Host)
 		assert.Equal(t, 8080, serverConf.Port)
 	})
+}
+
+// TestValidateRequired tests required and conditionally required paths
+func TestValidateRequired(t *testing.T) {
+	t.Run("ConditionsNotMet", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.RegisterRequired("api.key", ""))
+		cfg.Register("smtp.auth_user", "")
+		cfg.Register("smtp.auth_pass", "")
+		cfg.Register("tls.enabled", false)
+		cfg.Register("tls.cert", "")
+		require.NoError(t, cfg.RegisterRequiredIf("smtp.auth_pass", "smtp.auth_user", nil))
+		require.NoError(t, cfg.RegisterRequiredIf("tls.cert", "tls.enabled", true))
+		require.NoError(t, cfg.Set("api.key", "secret"))
+		assert.NoError(t, cfg.ValidateRequired())
+	})
+
+	t.Run("DependencyMakesRequired", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.RegisterRequired("api.key", ""))
+		cfg.Register("smtp.auth_user", "")
+		cfg.Register("smtp.auth_pass", "")
+		cfg.Register("tls.enabled", false)
+		cfg.Register("tls.cert", "")
+		require.NoError(t, cfg.RegisterRequiredIf("smtp.auth_pass", "smtp.auth_user", nil))
+		require.NoError(t, cfg.RegisterRequiredIf("tls.cert", "tls.enabled", true))
+		require.NoError(t, cfg.Set("api.key", "secret"))
+		require.NoError(t, cfg.Set("smtp.auth_user", "mailer"))
+		require.NoError(t, cfg.SetSource(SourceEnv, "tls.enabled", "true")) // Converted to bool
+
+		err := cfg.ValidateRequired()
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, []FieldError{
+			{Path: "smtp.auth_pass", Message: "is required when smtp.auth_user is set"},
+			{Path: "tls.cert", Message: "is required when tls.enabled is true"},
+		}, verr.Fields)
+
+		require.NoError(t, cfg.Set("smtp.auth_pass", "pw"))
+		require.NoError(t, cfg.Set("tls.cert", "/etc/cert.pem"))
+		assert.NoError(t, cfg.ValidateRequired())
+	})
+
+	t.Run("UnconditionallyRequired", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.RegisterRequired("api.key", ""))
+
+		err := cfg.ValidateRequired()
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, []FieldError{{Path: "api.key", Message: "is required"}}, verr.Fields)
+	})
+
+	t.Run("RequiredTag", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.RegisterStruct("", &struct {
+			URL string `toml:"url" required:"true"`
+		}{}))
+		assert.ErrorContains(t, cfg.ValidateRequired(), "url: is required")
+	})
+
+	t.Run("UnregisteredPaths", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("tls.enabled", false)
+		cfg.Register("tls.cert", "")
+		assert.ErrorIs(t, cfg.RegisterRequiredIf("missing", "tls.enabled", true), ErrPathNotRegistered)
+		assert.ErrorIs(t, cfg.RegisterRequiredIf("tls.cert", "missing", true), ErrPathNotRegistered)
+	})
+}
+
+// TestGetCoerced tests values resolve to the default's type regardless of source
+func TestGetCoerced(t *testing.T) {
+	t.Run("FromEnv", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.port", int64(8080)))
+		require.NoError(t, cfg.Register("server.timeout", 5*time.Second))
+		t.Setenv("APP_SERVER_PORT", "9090")
+		t.Setenv("APP_SERVER_TIMEOUT", "30s")
+		require.NoError(t, cfg.LoadEnv("APP_"))
+
+		raw, _ := cfg.Get("server.port")
+		assert.Equal(t, "9090", raw)
+
+		port, ok := cfg.GetCoerced("server.port")
+		require.True(t, ok)
+		assert.Equal(t, int64(9090), port)
+
+		timeout, ok := cfg.GetCoerced("server.timeout")
+		require.True(t, ok)
+		assert.Equal(t, 30*time.Second, timeout)
+	})
+
+	t.Run("FromFile", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.port", int64(8080)))
+		configFile := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(configFile, []byte("[server]\nport = 9090\n"), 0644))
+		require.NoError(t, cfg.LoadFile(configFile))
+
+		port, ok := cfg.GetCoerced("server.port")
+		require.True(t, ok)
+		assert.Equal(t, int64(9090), port)
+	})
+
+	t.Run("Unconvertible", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.port", int64(8080)))
+		require.NoError(t, cfg.Set("server.port", "not-a-port"))
+
+		_, ok := cfg.GetCoerced("server.port")
+		assert.False(t, ok)
+	})
+
+	t.Run("NilDefaultAndUnregistered", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("extra", nil))
+		require.NoError(t, cfg.Set("extra", "anything"))
+
+		value, ok := cfg.GetCoerced("extra")
+		require.True(t, ok)
+		assert.Equal(t, "anything", value)
+
+		_, ok = cfg.GetCoerced("missing")
+		assert.False(t, ok)
+	})
+}
+
+// TestEqual tests value-wise comparison of configs
+func TestEqual(t *testing.T) {
+	t.Run("DifferentSources", func(t *testing.T) {
+		a, b := New(), New()
+		for _, cfg := range []*Config{a, b} {
+			require.NoError(t, cfg.Register("server.host", "localhost"))
+			require.NoError(t, cfg.Register("server.port", 8080))
+		}
+		require.NoError(t, a.SetSource(SourceCLI, "server.port", int64(9090)))
+		require.NoError(t, b.SetSource(SourceFile, "server.port", 9090))
+		require.NoError(t, b.SetSource(SourceEnv, "server.host", "localhost"))
+
+		assert.True(t, a.Equal(b))
+		assert.True(t, b.Equal(a))
+		assert.True(t, a.Equal(a))
+		assert.True(t, a.Equal(a.Clone()))
+	})
+
+	t.Run("DifferentValues", func(t *testing.T) {
+		a, b := New(), New()
+		for _, cfg := range []*Config{a, b} {
+			require.NoError(t, cfg.Register("server.tags", []any{"a"}))
+		}
+		require.NoError(t, b.Set("server.tags", []any{"a", "b"}))
+		assert.False(t, a.Equal(b))
+		assert.False(t, a.Equal(nil))
+	})
+
+	t.Run("DifferentPaths", func(t *testing.T) {
+		a, b := New(), New()
+		for _, cfg := range []*Config{a, b} {
+			require.NoError(t, cfg.Register("server.host", "localhost"))
+		}
+		require.NoError(t, b.Register("server.debug", false))
+		assert.False(t, a.Equal(b))
+		assert.False(t, b.Equal(a))
+	})
+
+	t.Run("LeavesValuesUnchanged", func(t *testing.T) {
+		a, b := New(), New()
+		for _, cfg := range []*Config{a, b} {
+			require.NoError(t, cfg.Register("server.tags", []any{"a"}))
+		}
+		nested := []any{int32(1)}
+		require.NoError(t, a.Set("server.tags", nested))
+		require.NoError(t, b.Set("server.tags", []any{int64(1)}))
+
+		assert.True(t, a.Equal(b))
+		tags, _ := a.Get("server.tags")
+		assert.Equal(t, []any{int32(1)}, tags)
+	})
+}
+
+// TestModifiedPaths tests listing values that differ from their defaults
+func TestModifiedPaths(t *testing.T) {
+	cfg := New()
+	require.NoError(t, cfg.Register("server.host", "localhost"))
+	require.NoError(t, cfg.Register("server.port", 8080))
+	require.NoError(t, cfg.Register("server.tags", []string{"a"}))
+	require.NoError(t, cfg.Register("debug", false))
+	require.NoError(t, cfg.Register("extra", nil))
+
+	assert.Empty(t, cfg.ModifiedPaths())
+	assert.Empty(t, cfg.ModifiedValues())
+
+	// Same value as the default from another source, in another numeric type
+	require.NoError(t, cfg.SetSource(SourceFile, "server.port", int64(8080)))
+	require.NoError(t, cfg.SetSource(SourceEnv, "server.host", "localhost"))
+	assert.Empty(t, cfg.ModifiedPaths())
+
+	require.NoError(t, cfg.Set("server.port", int64(9090)))
+	require.NoError(t, cfg.Set("server.tags", []string{"a", "b"}))
+	require.NoError(t, cfg.Set("extra", "set"))
+
+	assert.Equal(t, []string{"extra", "server.port", "server.tags"}, cfg.ModifiedPaths())
+	assert.Equal(t, map[string]any{
+		"extra":       "set",
+		"server.port": int64(9090),
+		"server.tags": []string{"a", "b"},
+	}, cfg.ModifiedValues())
+
+	// Returned values are copies
+	values := cfg.ModifiedValues()
+	values["server.tags"].([]string)[0] = "changed"
+	tags, _ := cfg.Get("server.tags")
+	assert.Equal(t, []string{"a", "b"}, tags)
+
+	// ExportEnv uses the same comparison, including for slice values
+	assert.Equal(t, "9090", cfg.ExportEnv("APP_")["APP_SERVER_PORT"])
+	assert.NotContains(t, cfg.ExportEnv("APP_"), "APP_SERVER_HOST")
+}
+
+// TestRange tests visiting registered paths with their metadata
+func TestRange(t *testing.T) {
+	type RangeConfig struct {
+		Host     string `toml:"host" usage:"Listen address"`
+		Port     int    `toml:"port" required:"true"`
+		Password string `toml:"password" sensitive:"true"`
+	}
+
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("server.", RangeConfig{Host: "localhost", Port: 8080}))
+	cfg.Register("tags", []string{"a"})
+	require.NoError(t, cfg.SetSource(SourceFile, "server.port", int64(9090)))
+	require.NoError(t, cfg.SetSource(SourceEnv, "server.port", "9191"))
+
+	var visited []string
+	infos := make(map[string]ItemInfo)
+	cfg.Range(func(path string, info ItemInfo) bool {
+		visited = append(visited, path)
+		infos[path] = info
+		return true
+	})
+
+	assert.Equal(t, []string{"server.host", "server.password", "server.port", "tags"}, visited)
+
+	port := infos["server.port"]
+	assert.Equal(t, 8080, port.Default)
+	assert.Equal(t, "9191", port.Current)
+	assert.Equal(t, SourceEnv, port.Source)
+	assert.Equal(t, map[Source]any{SourceFile: int64(9090), SourceEnv: "9191"}, port.Sources)
+	assert.True(t, port.Required)
+
+	host := infos["server.host"]
+	assert.Equal(t, SourceDefault, host.Source)
+	assert.Empty(t, host.Sources)
+	assert.Equal(t, "Listen address", host.Usage)
+	assert.True(t, infos["server.password"].Sensitive)
+
+	// Visited values are copies
+	infos["tags"].Current.([]string)[0] = "mutated"
+	tags, _ := cfg.Get("tags")
+	assert.Equal(t, []string{"a"}, tags)
+
+	t.Run("EarlyStop", func(t *testing.T) {
+		count := 0
+		cfg.Range(func(path string, info ItemInfo) bool {
+			count++
+			return count < 2
+		})
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("CallbackMayWrite", func(t *testing.T) {
+		cfg.Range(func(path string, info ItemInfo) bool {
+			return assert.NoError(t, cfg.UnsetSource(SourceEnv, path))
+		})
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(9090), port)
+	})
+}
+
+// TestUntypedFields tests registration and access of interface-typed struct fields
+func TestUntypedFields(t *testing.T) {
+	type Plugin struct {
+		Name    string `toml:"name"`
+		Options any    `toml:"options"`
+		Limit   any    `toml:"limit"`
+	}
+
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("plugin.", Plugin{Name: "cache", Limit: 10}))
+
+	t.Run("NilDefault", func(t *testing.T) {
+		val, exists := cfg.Get("plugin.options")
+		assert.True(t, exists)
+		assert.Nil(t, val)
+
+		_, err := GetTyped[int64](cfg, "plugin.options")
+		assert.ErrorContains(t, err, "untyped field with no value")
+
+		// Typed paths with a value are unaffected
+		name, err := GetTyped[string](cfg, "plugin.name")
+		require.NoError(t, err)
+		assert.Equal(t, "cache", name)
+	})
+
+	t.Run("SetAnyType", func(t *testing.T) {
+		options := map[string]any{"ttl": "5m"}
+		require.NoError(t, cfg.Set("plugin.options", options))
+		val, _ := cfg.Get("plugin.options")
+		assert.Equal(t, options, val)
+
+		// A value of a different type than the default is accepted
+		require.NoError(t, cfg.Set("plugin.limit", "unlimited"))
+		assert.NoError(t, cfg.ValidateTypes())
+		coerced, ok := cfg.GetCoerced("plugin.limit")
+		require.True(t, ok)
+		assert.Equal(t, "unlimited", coerced)
+
+		// Clones keep paths untyped
+		clone := cfg.Clone()
+		assert.NoError(t, clone.ValidateTypes())
+		coerced, ok = clone.GetCoerced("plugin.limit")
+		require.True(t, ok)
+		assert.Equal(t, "unlimited", coerced)
+
+		var target Plugin
+		require.NoError(t, cfg.Scan(&target, "plugin"))
+		assert.Equal(t, options, target.Options)
+		assert.Equal(t, "unlimited", target.Limit)
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		data, err := cfg.ExportJSONSchema()
+		require.NoError(t, err)
+		var schema map[string]any
+		require.NoError(t, json.Unmarshal(data, &schema))
+		plugin := schema["properties"].(map[string]any)["plugin"].(map[string]any)
+		limit := plugin["properties"].(map[string]any)["limit"].(map[string]any)
+		assert.NotContains(t, limit, "type")
+	})
 }
\ No newline at end of file
@@ -4,18 +4,34 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net"
 	"net/url"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
 )
 
+// Metadata reports how configuration keys mapped onto a scanned struct.
+// Paths are dotted and include the scan base path.
+type Metadata struct {
+	Unused []string // Config keys with no corresponding struct field
+	Unset  []string // Struct fields with no corresponding config key
+}
+
 // unmarshal is the single authoritative function for decoding configuration
 // into target structures. All public decoding methods delegate to this.
 func (c *Config) unmarshal(source Source, target any, basePath ...string) error {
+	return c.unmarshalWithMetadata(source, target, nil, basePath...)
+}
+
+// unmarshalWithMetadata decodes like unmarshal and, when md is non-nil,
+// records mapstructure's key usage into it.
+func (c *Config) unmarshalWithMetadata(source Source, target any, md *Metadata, basePath ...string) error {
 	// Parse variadic basePath
 	path := ""
 	switch len(basePath) {
@@ -33,6 +49,7 @@ func (c *Config) unmarshal(source Source, target any, basePath ...string) error
 		return fmt.Errorf("unmarshal target must be non-nil pointer, got %T", target)
 	}
 
+	c.resolveLazyDefaults()
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
@@ -67,24 +84,206 @@ func (c *Config) unmarshal(source Source, target any, basePath ...string) error
 		}
 	}
 
-	// Create decoder with comprehensive hooks
+	var meta *mapstructure.Metadata
+	if md != nil {
+		meta = &mapstructure.Metadata{}
+	}
+
+	if err := c.decodeInto(target, sectionMap, meta); err != nil {
+		return fmt.Errorf("decode failed for path %q: %w", path, err)
+	}
+
+	if md != nil {
+		md.Unused = prefixPaths(path, meta.Unused)
+		md.Unset = prefixPaths(path, meta.Unset)
+	}
+
+	return nil
+}
+
+// decodeInto decodes data into target with the hooks and settings shared by all
+// Scan methods, recording key usage in meta when non-nil. Must be called with the lock held.
+func (c *Config) decodeInto(target any, data map[string]any, meta *mapstructure.Metadata) error {
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		Result:           target,
 		TagName:          c.tagName,
 		WeaklyTypedInput: true,
-		DecodeHook:       c.getDecodeHook(),
+		DecodeHook:       mapstructure.ComposeDecodeHookFunc(structLayoutHookFunc(c.tagName), c.getDecodeHook()),
 		ZeroFields:       true,
-		Metadata:         nil,
+		Squash:           true, // Embedded structs without a tag name are registered flat
+		Metadata:         meta,
 	})
 	if err != nil {
 		return fmt.Errorf("decoder creation failed: %w", err)
 	}
+	return decoder.Decode(data)
+}
 
-	if err := decoder.Decode(sectionMap); err != nil {
-		return fmt.Errorf("decode failed for path %q: %w", path, err)
+// structLayoutHookFunc reshapes a section decoded into a struct so Scan reads back the
+// layout that registration uses:
+//   - The decoder squashes every embedded struct, but registration keeps embedded structs
+//     with a tag name nested, so their section is lifted into the parent where the
+//     squashed fields are looked up. Keys already present in the parent are kept.
+//   - Nil embedded struct pointers are allocated when the section holds any of their
+//     keys, since the decoder only squashes non-nil pointers.
+//   - Inline struct fields are registered flat, so their keys are gathered into a
+//     section named after the field. Inline maps receive the keys no field claims.
+func structLayoutHookFunc(tagName string) mapstructure.DecodeHookFuncValue {
+	return func(from reflect.Value, to reflect.Value) (any, error) {
+		section, ok := from.Interface().(map[string]any)
+		if !ok || to.Kind() != reflect.Struct || isAtomicStructType(to.Type()) {
+			return from.Interface(), nil
+		}
+		reshaped := maps.Clone(section)
+		reshapeSection(reshaped, to, tagName, structKeys(to.Type(), tagName))
+		return reshaped, nil
 	}
+}
 
-	return nil
+// reshapeSection applies structLayoutHookFunc's rules to the fields of v, including
+// those of embedded structs that the decoder squashes into the same section.
+// claimed holds the section keys that belong to a field of the outermost struct.
+func reshapeSection(section map[string]any, v reflect.Value, tagName string, claimed map[string]bool) {
+	t := v.Type()
+	var inlineMaps []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(tagName)
+		if tag == "-" {
+			continue
+		}
+		tagKey, tagOpts := parseTag(tag)
+		squashed := tagKey == "" || tagOpts.has("squash")
+
+		if !field.Anonymous {
+			// The decoder looks up the field under its tag name or, failing that, its Go name
+			name := tagKey
+			if name == "" {
+				name = field.Name
+			}
+			switch {
+			case !field.IsExported() || !tagOpts.has("inline") || tagOpts.has("squash"):
+			case isNestedStructType(field.Type):
+				gatherKeys(section, name, structKeys(derefType(field.Type), tagName))
+				claimed[name] = true
+			case field.Type.Kind() == reflect.Map && field.Type.Key().Kind() == reflect.String:
+				inlineMaps = append(inlineMaps, name)
+			}
+			continue
+		}
+		if !isNestedStructType(field.Type) {
+			continue
+		}
+
+		embedded := v.Field(i)
+		if embedded.Kind() == reflect.Ptr {
+			if embedded.IsNil() {
+				if !squashed || !embedded.CanSet() || !hasAnyKey(section, structKeys(field.Type.Elem(), tagName)) {
+					continue // The decoder treats a nil pointer as an ordinary field
+				}
+				embedded.Set(reflect.New(field.Type.Elem()))
+			}
+			embedded = embedded.Elem()
+		}
+
+		if !squashed {
+			if nested, ok := section[tagKey].(map[string]any); ok {
+				delete(section, tagKey)
+				for key, value := range nested {
+					if _, exists := section[key]; !exists {
+						section[key] = value
+					}
+				}
+			}
+		}
+		reshapeSection(section, embedded, tagName, claimed)
+	}
+
+	for _, name := range inlineMaps {
+		unclaimed := make(map[string]bool)
+		for key := range section {
+			if !claimed[key] {
+				unclaimed[key] = true
+			}
+		}
+		gatherKeys(section, name, unclaimed)
+	}
+}
+
+// gatherKeys moves the section entries whose keys are in keys into a nested section
+// under name, creating it only if any key is present
+func gatherKeys(section map[string]any, name string, keys map[string]bool) {
+	nested := make(map[string]any)
+	for key := range keys {
+		if value, ok := section[key]; ok {
+			nested[key] = value
+			delete(section, key)
+		}
+	}
+	if len(nested) > 0 {
+		section[name] = nested
+	}
+}
+
+// structKeys returns the section keys that the fields of struct type t occupy,
+// counting the fields of embedded and inline structs as t's own
+func structKeys(t reflect.Type, tagName string) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(tagName)
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		tagKey, tagOpts := parseTag(tag)
+		nested := isNestedStructType(field.Type)
+
+		if nested && (field.Anonymous || tagOpts.has("inline") || tagOpts.has("squash")) {
+			maps.Copy(keys, structKeys(derefType(field.Type), tagName))
+			if tagKey == "" || tagOpts.has("squash") {
+				continue
+			}
+		}
+		if tagOpts.has("inline") && field.Type.Kind() == reflect.Map {
+			continue // Inline maps claim the keys left over
+		}
+		if tagKey == "" {
+			tagKey = field.Name
+		}
+		keys[tagKey] = true
+	}
+	return keys
+}
+
+// hasAnyKey reports whether section holds any of keys
+func hasAnyKey(section map[string]any, keys map[string]bool) bool {
+	for key := range keys {
+		if _, ok := section[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// derefType returns the element type of a pointer type, or t itself
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// prefixPaths qualifies decoder-relative keys with the scan base path, sorted
+func prefixPaths(base string, keys []string) []string {
+	paths := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if base != "" {
+			key = base + "." + key
+		}
+		paths = append(paths, key)
+	}
+	sort.Strings(paths)
+	return paths
 }
 
 // normalizeMap ensures that the input data is a map[string]any for the decoder.
@@ -118,8 +317,14 @@ func normalizeMap(data any) (map[string]any, error) {
 }
 
 // getDecodeHook returns the composite decode hook for all type conversions
+// and any hooks added with AddDecodeHook. Callers must hold c.mutex.
 func (c *Config) getDecodeHook() mapstructure.DecodeHookFunc {
-	return mapstructure.ComposeDecodeHookFunc(
+	hooks := make([]mapstructure.DecodeHookFunc, 0, len(c.preDecodeHooks)+len(c.postDecodeHooks)+12)
+
+	// Application hooks that take precedence over built-ins
+	hooks = append(hooks, c.preDecodeHooks...)
+
+	hooks = append(hooks,
 		// JSON Number handling
 		jsonNumberHookFunc(),
 
@@ -127,15 +332,26 @@ func (c *Config) getDecodeHook() mapstructure.DecodeHookFunc {
 		stringToNetIPHookFunc(),
 		stringToNetIPNetHookFunc(),
 		stringToURLHookFunc(),
+		stringToHardwareAddrHookFunc(),
+
+		// Time zones and patterns
+		stringToLocationHookFunc(),
+		stringToRegexpHookFunc(),
+
+		// Booleans such as "yes" and "off"
+		stringToBoolHookFunc(),
 
 		// Standard hooks
 		mapstructure.StringToTimeDurationHookFunc(),
-		mapstructure.StringToTimeHookFunc(time.RFC3339),
+		stringToTimeHookFunc(),
+		stringToScalarSliceHookFunc(),
 		mapstructure.StringToSliceHookFunc(","),
-
-		// Custom application hooks
-		c.customDecodeHook(),
 	)
+
+	// Application hooks that refine built-in results
+	hooks = append(hooks, c.postDecodeHooks...)
+
+	return mapstructure.ComposeDecodeHookFunc(hooks...)
 }
 
 // jsonNumberHookFunc handles json.Number conversion to appropriate numeric types
@@ -259,18 +475,224 @@ func stringToURLHookFunc() mapstructure.DecodeHookFunc {
 	}
 }
 
-// customDecodeHook allows for application-specific type conversions
-func (c *Config) customDecodeHook() mapstructure.DecodeHookFunc {
+// stringToHardwareAddrHookFunc handles net.HardwareAddr conversion
+func stringToHardwareAddrHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+
+		if t != reflect.TypeOf(net.HardwareAddr{}) {
+			return data, nil
+		}
+
+		str := data.(string)
+		if len(str) > 59 { // Max 20-octet IPoIB address length
+			return nil, fmt.Errorf("invalid MAC address length: %d", len(str))
+		}
+
+		mac, err := net.ParseMAC(str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAC address: %w", err)
+		}
+
+		return mac, nil
+	}
+}
+
+// stringToLocationHookFunc handles time.Location conversion from IANA names
+func stringToLocationHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+		isPtr := t.Kind() == reflect.Ptr
+		targetType := t
+		if isPtr {
+			targetType = t.Elem()
+		}
+		if targetType != reflect.TypeOf(time.Location{}) {
+			return data, nil
+		}
+
+		str := data.(string)
+		if len(str) > 128 {
+			return nil, fmt.Errorf("time zone name too long: %d bytes", len(str))
+		}
+		loc, err := time.LoadLocation(str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone: %w", err)
+		}
+		if isPtr {
+			return loc, nil
+		}
+		return *loc, nil
+	}
+}
+
+// stringToRegexpHookFunc handles regexp.Regexp conversion
+func stringToRegexpHookFunc() mapstructure.DecodeHookFunc {
 	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
-		// SECURITY: Add custom validation for application types here
-		// Example: Rate limit parsing, permission validation, etc.
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+		isPtr := t.Kind() == reflect.Ptr
+		targetType := t
+		if isPtr {
+			targetType = t.Elem()
+		}
+		if targetType != reflect.TypeOf(regexp.Regexp{}) {
+			return data, nil
+		}
+
+		// SECURITY: Bound pattern size; RE2 guarantees linear-time matching
+		str := data.(string)
+		if len(str) > 4096 {
+			return nil, fmt.Errorf("regexp too long: %d bytes", len(str))
+		}
+		re, err := regexp.Compile(str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp: %w", err)
+		}
+		if isPtr {
+			return re, nil
+		}
+		return *re, nil
+	}
+}
+
+// stringToBoolHookFunc handles bool conversion from the spellings accepted by parseBool.
+// Empty strings are left to mapstructure, which decodes them as false.
+func stringToBoolHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t.Kind() != reflect.Bool {
+			return data, nil
+		}
 
-		// Pass through by default
-		return data, nil
+		str := reflect.ValueOf(data).String()
+		if str == "" {
+			return data, nil
+		}
+		return parseBool(str)
 	}
 }
 
-// navigateToPath traverses nested map to reach the specified path
+// parseBool parses a boolean case-insensitively, accepting true/false, t/f, yes/no,
+// y/n, on/off, and 1/0 as used in shell environments
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "t", "yes", "y", "on", "1":
+		return true, nil
+	case "false", "f", "no", "n", "off", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// timeLayouts are the text forms accepted for time.Time values: RFC 3339 and the
+// TOML local date-time, local date, and local time forms, with 'T' or a space
+// between date and time
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999",
+}
+
+// parseTime parses s with the first matching layout in timeLayouts. Values without
+// an offset are in UTC.
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// stringToTimeHookFunc handles time.Time conversion from the forms in timeLayouts.
+// Values already decoded as time.Time, such as TOML datetimes, pass through unchanged.
+func stringToTimeHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeOf(time.Time{}) {
+			return data, nil
+		}
+		return parseTime(reflect.ValueOf(data).String())
+	}
+}
+
+// stringToScalarSliceHookFunc handles comma-separated strings into slices of numbers,
+// bools, or durations. Whitespace around elements and empty entries are ignored.
+func stringToScalarSliceHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t.Kind() != reflect.Slice {
+			return data, nil
+		}
+
+		switch t.Elem().Kind() {
+		case reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, // Uint8 excluded: []byte
+			reflect.Float32, reflect.Float64:
+		default:
+			return data, nil
+		}
+
+		var elems []string
+		for _, part := range strings.Split(data.(string), ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				elems = append(elems, part)
+			}
+		}
+
+		return convertElems(elems, t)
+	}
+}
+
+// HookPosition selects where a user decode hook runs relative to the built-in conversions
+type HookPosition int
+
+const (
+	// HookBeforeBuiltins runs the hook ahead of the built-in hooks, so it sees raw source values
+	HookBeforeBuiltins HookPosition = iota
+	// HookAfterBuiltins runs the hook after the built-in hooks have converted the value
+	HookAfterBuiltins
+)
+
+// AddDecodeHook registers an application decode hook used by Scan, AsStruct,
+// GetTyped, and ScanTyped. Hooks run ahead of the built-in conversions unless
+// HookAfterBuiltins is given; hooks at the same position run in the order added.
+// Hooks may run while the config's lock is held, so they must not call methods of
+// the Config, such as Get or Set; doing so can deadlock.
+func (c *Config) AddDecodeHook(hook mapstructure.DecodeHookFunc, position ...HookPosition) error {
+	if hook == nil {
+		return fmt.Errorf("decode hook cannot be nil")
+	}
+	pos := HookBeforeBuiltins
+	if len(position) > 0 {
+		pos = position[0]
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch pos {
+	case HookBeforeBuiltins:
+		c.preDecodeHooks = append(c.preDecodeHooks, hook)
+	case HookAfterBuiltins:
+		c.postDecodeHooks = append(c.postDecodeHooks, hook)
+	default:
+		return fmt.Errorf("invalid hook position: %d", pos)
+	}
+
+	c.invalidateCache()
+	return nil
+}
+
+// navigateToPath traverses nested map to reach the specified path.
+// Segments may carry [n] suffixes to index into slices (e.g., "servers[0]").
 func navigateToPath(nested map[string]any, path string) any {
 	if path == "" {
 		return nested
@@ -281,21 +703,14 @@ func navigateToPath(nested map[string]any, path string) any {
 		return nested
 	}
 
-	segments := strings.Split(path, ".")
-	current := any(nested)
-
-	for _, segment := range segments {
-		currentMap, ok := current.(map[string]any)
-		if !ok {
-			return nil
-		}
-
-		value, exists := currentMap[segment]
-		if !exists {
-			return nil
-		}
-		current = value
+	segments, err := parseIndexedPath(path)
+	if err != nil {
+		return nil
 	}
 
-	return current
+	value, found := getIndexedValue(nested, segments)
+	if !found {
+		return nil
+	}
+	return value
 }
\ No newline at end of file
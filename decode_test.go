@@ -2,11 +2,18 @@
 package config
 
 import (
+	"fmt"
 	"net"
 	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -325,4 +332,668 @@ func TestWeaklyTypedInput(t *testing.T) {
 	assert.Equal(t, true, result.BoolFromString)
 	assert.Equal(t, "12345", result.StringFromInt)
 	assert.Equal(t, "1", result.StringFromBool) // mapstructure converts bool(true) to "1" in weak conversion
+}
+
+// TestBoolSpellings tests shell-style boolean strings from env and other sources
+func TestBoolSpellings(t *testing.T) {
+	accepted := map[string]bool{
+		"true": true, "TRUE": true, "t": true, "yes": true, "Yes": true, "y": true, "on": true, "ON": true, "1": true,
+		"false": false, "F": false, "no": false, "N": false, "off": false, "Off": false, "0": false, "": false,
+	}
+	for value, want := range accepted {
+		t.Run("Accepts_"+value, func(t *testing.T) {
+			cfg := New()
+			require.NoError(t, cfg.Register("feature", !want))
+			t.Setenv("APP_FEATURE", value)
+			require.NoError(t, cfg.LoadEnv("APP_"))
+
+			got, err := GetTyped[bool](cfg, "feature")
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+			assert.Equal(t, want, cfg.MustBool("feature"))
+
+			if value != "" {
+				snapped, ok := cfg.Snapshot().Bool("feature")
+				assert.True(t, ok)
+				assert.Equal(t, want, snapped)
+			}
+		})
+	}
+
+	t.Run("Rejects", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("feature", false))
+		t.Setenv("APP_FEATURE", "maybe")
+		require.NoError(t, cfg.LoadEnv("APP_"))
+
+		_, err := GetTyped[bool](cfg, "feature")
+		assert.ErrorContains(t, err, `invalid boolean "maybe"`)
+		assert.Error(t, cfg.ValidateTypes())
+		_, ok := cfg.Snapshot().Bool("feature")
+		assert.False(t, ok)
+	})
+
+	t.Run("Slices", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("flags", []bool{}))
+		require.NoError(t, cfg.Set("flags", "yes, off, 1"))
+
+		flags, err := GetTyped[[]bool](cfg, "flags")
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false, true}, flags)
+	})
+}
+
+// TestPointerScalarFields tests optional pointer fields with nil-means-unset semantics
+func TestPointerScalarFields(t *testing.T) {
+	type OptionalConfig struct {
+		Port    *int    `toml:"port"`
+		Name    *string `toml:"name"`
+		Retries *int    `toml:"retries"`
+	}
+
+	three := 3
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("", OptionalConfig{Retries: &three}))
+
+	paths := cfg.GetRegisteredPaths("")
+	assert.True(t, paths["port"])
+	assert.True(t, paths["name"])
+
+	// Defaults are the dereferenced value, or nil when unset
+	val, _ := cfg.Get("retries")
+	assert.Equal(t, 3, val)
+	val, _ = cfg.Get("port")
+	assert.Nil(t, val)
+
+	var result OptionalConfig
+	require.NoError(t, cfg.Scan(&result))
+	assert.Nil(t, result.Port, "unset pointer should stay nil")
+	assert.Nil(t, result.Name)
+	require.NotNil(t, result.Retries)
+	assert.Equal(t, 3, *result.Retries)
+
+	// A value from the file populates the pointer
+	require.NoError(t, cfg.LoadReader(strings.NewReader("port = 8080"), "toml"))
+
+	result = OptionalConfig{}
+	require.NoError(t, cfg.Scan(&result))
+	require.NotNil(t, result.Port)
+	assert.Equal(t, 8080, *result.Port)
+	assert.Nil(t, result.Name, "absent value should stay nil")
+}
+
+// TestCSVSliceDecoding tests comma-separated strings decoding into typed slices
+func TestCSVSliceDecoding(t *testing.T) {
+	type SliceConfig struct {
+		Ints      []int           `toml:"ints"`
+		Int64s    []int64         `toml:"int64s"`
+		Floats    []float64       `toml:"floats"`
+		Bools     []bool          `toml:"bools"`
+		Durations []time.Duration `toml:"durations"`
+	}
+
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("", SliceConfig{}))
+
+	cfg.SetSource(SourceEnv, "ints", "7, 8 ,9")
+	cfg.SetSource(SourceEnv, "int64s", "1,,2,")
+	cfg.SetSource(SourceEnv, "floats", "0.5,1.25")
+	cfg.SetSource(SourceEnv, "bools", "true,false")
+	cfg.SetSource(SourceEnv, "durations", "1s,2m")
+
+	var result SliceConfig
+	require.NoError(t, cfg.Scan(&result))
+	assert.Equal(t, []int{7, 8, 9}, result.Ints)
+	assert.Equal(t, []int64{1, 2}, result.Int64s)
+	assert.Equal(t, []float64{0.5, 1.25}, result.Floats)
+	assert.Equal(t, []bool{true, false}, result.Bools)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, result.Durations)
+
+	ints, err := GetTyped[[]int](cfg, "ints")
+	require.NoError(t, err)
+	assert.Equal(t, []int{7, 8, 9}, ints)
+
+	cfg.SetSource(SourceEnv, "ints", "1,x")
+	err = cfg.Scan(&result)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid integer element "x"`)
+}
+
+// TestExtendedTypeConversion tests MAC address, time zone, and regexp decoding
+func TestExtendedTypeConversion(t *testing.T) {
+	type ExtendedConfig struct {
+		MAC     net.HardwareAddr `toml:"mac"`
+		Zone    *time.Location   `toml:"zone"`
+		Pattern *regexp.Regexp   `toml:"pattern"`
+	}
+
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("", ExtendedConfig{}))
+
+	paths := cfg.GetRegisteredPaths("")
+	assert.True(t, paths["zone"], "*time.Location registers as a single value")
+	assert.True(t, paths["pattern"], "*regexp.Regexp registers as a single value")
+
+	t.Run("ValidValues", func(t *testing.T) {
+		cfg.Set("mac", "00:1a:2b:3c:4d:5e")
+		cfg.Set("zone", "Europe/Berlin")
+		cfg.Set("pattern", `^v\d+$`)
+
+		var result ExtendedConfig
+		require.NoError(t, cfg.Scan(&result))
+		assert.Equal(t, "00:1a:2b:3c:4d:5e", result.MAC.String())
+		require.NotNil(t, result.Zone)
+		assert.Equal(t, "Europe/Berlin", result.Zone.String())
+		require.NotNil(t, result.Pattern)
+		assert.True(t, result.Pattern.MatchString("v12"))
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		value    string
+		expected string
+	}{
+		{"InvalidMAC", "mac", "00:1a:zz", "invalid MAC address"},
+		{"LongMAC", "mac", strings.Repeat("a", 60), "invalid MAC address length"},
+		{"InvalidZone", "zone", "Mars/Olympus_Mons", "invalid time zone"},
+		{"LongZone", "zone", strings.Repeat("a", 129), "time zone name too long"},
+		{"InvalidRegexp", "pattern", "([a-z", "invalid regexp"},
+		{"LongRegexp", "pattern", strings.Repeat("a", 4097), "regexp too long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cfg.Clone()
+			c.Set(tt.path, tt.value)
+
+			var result ExtendedConfig
+			err := c.Scan(&result)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+// TestTimeConversion tests TOML datetimes, local dates, and time strings decoding into time.Time
+func TestTimeConversion(t *testing.T) {
+	type TimeConfig struct {
+		Created  time.Time `toml:"created"`
+		Local    time.Time `toml:"local"`
+		Birthday time.Time `toml:"birthday"`
+		Alarm    time.Time `toml:"alarm"`
+	}
+
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("", TimeConfig{}))
+
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	content := `created = 2024-01-15T10:30:00+02:00
+local = 2024-01-15T10:30:00
+birthday = 2024-01-15
+alarm = 07:30:00
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	require.NoError(t, cfg.LoadFile(configPath))
+
+	t.Run("TOMLTypes", func(t *testing.T) {
+		// BurntSushi/toml decodes every datetime form to time.Time
+		value, _ := cfg.Get("birthday")
+		require.IsType(t, time.Time{}, value)
+
+		var result TimeConfig
+		require.NoError(t, cfg.Scan(&result))
+		assert.True(t, result.Created.Equal(time.Date(2024, 1, 15, 8, 30, 0, 0, time.UTC)))
+		assert.Equal(t, [3]int{10, 30, 0}, [3]int{result.Local.Hour(), result.Local.Minute(), result.Local.Second()})
+		year, month, day := result.Birthday.Date()
+		assert.Equal(t, []int{2024, 1, 15}, []int{year, int(month), day})
+		assert.Equal(t, 7, result.Alarm.Hour())
+		assert.Equal(t, 30, result.Alarm.Minute())
+
+		birthday, ok := cfg.Snapshot().Time("birthday")
+		assert.True(t, ok)
+		assert.Equal(t, result.Birthday, birthday)
+	})
+
+	t.Run("Strings", func(t *testing.T) {
+		c := cfg.Clone()
+		require.NoError(t, c.SetSource(SourceEnv, "created", "2024-01-15T10:30:00Z"))
+		require.NoError(t, c.SetSource(SourceEnv, "local", "2024-01-15 10:30:00"))
+		require.NoError(t, c.SetSource(SourceEnv, "birthday", "2024-01-15"))
+		require.NoError(t, c.SetSource(SourceEnv, "alarm", "07:30:00"))
+
+		var result TimeConfig
+		require.NoError(t, c.Scan(&result))
+		assert.Equal(t, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), result.Created)
+		assert.Equal(t, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), result.Local)
+		assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), result.Birthday)
+		assert.Equal(t, time.Date(0, 1, 1, 7, 30, 0, 0, time.UTC), result.Alarm)
+
+		birthday, ok := c.Snapshot().Time("birthday")
+		assert.True(t, ok)
+		assert.Equal(t, result.Birthday, birthday)
+
+		require.NoError(t, c.SetSource(SourceEnv, "birthday", "15/01/2024"))
+		err := c.Scan(&result)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid time "15/01/2024"`)
+		_, ok = c.Snapshot().Time("birthday")
+		assert.False(t, ok)
+	})
+}
+
+// TestScanMapped tests decoding scattered config paths into one flat struct
+func TestScanMapped(t *testing.T) {
+	type Connection struct {
+		Timeout time.Duration `toml:"timeout"`
+		Host    string        `toml:"host"`
+		Backup  string        `toml:"backup"`
+		Retry   struct {
+			Max int `toml:"max"`
+		} `toml:"retry"`
+		Limits struct {
+			Burst int `toml:"burst"`
+			Rate  int `toml:"rate"`
+		} `toml:"limits"`
+		Untouched string `toml:"untouched"`
+	}
+
+	cfg := New()
+	cfg.Register("server.read_timeout", 5*time.Second)
+	cfg.Register("db.host", "db.local")
+	cfg.Register("db.replicas", []any{map[string]any{"host": "replica-1"}})
+	cfg.Register("client.max_retries", 3)
+	cfg.Register("quota.burst", 10)
+	cfg.Register("quota.rate", 100)
+	require.NoError(t, cfg.SetSource(SourceEnv, "server.read_timeout", "30s"))
+
+	mapping := map[string]string{
+		"Timeout":   "server.read_timeout",
+		"Host":      "db.host",
+		"Backup":    "db.replicas[0].host",
+		"Retry.Max": "client.max_retries",
+		"limits":    "quota",
+	}
+
+	conn := Connection{Untouched: "kept"}
+	require.NoError(t, cfg.ScanMapped(&conn, mapping))
+	assert.Equal(t, 30*time.Second, conn.Timeout)
+	assert.Equal(t, "db.local", conn.Host)
+	assert.Equal(t, "replica-1", conn.Backup)
+	assert.Equal(t, 3, conn.Retry.Max)
+	assert.Equal(t, 10, conn.Limits.Burst)
+	assert.Equal(t, 100, conn.Limits.Rate)
+	assert.Equal(t, "kept", conn.Untouched)
+
+	t.Run("Errors", func(t *testing.T) {
+		err := cfg.ScanMapped(&conn, map[string]string{"Host": "db.missing"})
+		assert.ErrorIs(t, err, ErrPathNotRegistered)
+
+		assert.Error(t, cfg.ScanMapped(&conn, map[string]string{"Backup": "db.replicas[5].host"}))
+		assert.Error(t, cfg.ScanMapped(conn, mapping))
+
+		require.NoError(t, cfg.SetSource(SourceEnv, "server.read_timeout", "soon"))
+		assert.Error(t, cfg.ScanMapped(&conn, mapping))
+	})
+}
+
+// Size is a test enum decoded from "small|large" by a user hook
+type Size int
+
+const (
+	SizeSmall Size = iota + 1
+	SizeLarge
+)
+
+// TestAddDecodeHook tests application decode hooks across Scan, GetTyped, and ScanTyped
+func TestAddDecodeHook(t *testing.T) {
+	sizeHook := func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeOf(Size(0)) {
+			return data, nil
+		}
+		switch data.(string) {
+		case "small":
+			return SizeSmall, nil
+		case "large":
+			return SizeLarge, nil
+		default:
+			return nil, fmt.Errorf("invalid size %q", data)
+		}
+	}
+
+	type SizeConfig struct {
+		Size Size `toml:"size"`
+	}
+
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("", SizeConfig{Size: SizeSmall}))
+	require.NoError(t, cfg.AddDecodeHook(sizeHook))
+	require.NoError(t, cfg.Set("size", "large"))
+
+	t.Run("Scan", func(t *testing.T) {
+		var result SizeConfig
+		require.NoError(t, cfg.Scan(&result))
+		assert.Equal(t, SizeLarge, result.Size)
+	})
+
+	t.Run("GetTyped", func(t *testing.T) {
+		size, err := GetTyped[Size](cfg, "size")
+		require.NoError(t, err)
+		assert.Equal(t, SizeLarge, size)
+	})
+
+	t.Run("ScanTyped", func(t *testing.T) {
+		result, err := ScanTyped[SizeConfig](cfg)
+		require.NoError(t, err)
+		assert.Equal(t, SizeLarge, result.Size)
+	})
+
+	t.Run("HookError", func(t *testing.T) {
+		c := cfg.Clone()
+		require.NoError(t, c.Set("size", "huge"))
+		var result SizeConfig
+		err := c.Scan(&result)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid size "huge"`)
+	})
+
+	t.Run("WithoutHook", func(t *testing.T) {
+		c := New()
+		require.NoError(t, c.RegisterStruct("", SizeConfig{Size: SizeSmall}))
+		require.NoError(t, c.Set("size", "large"))
+		var result SizeConfig
+		assert.Error(t, c.Scan(&result))
+	})
+
+	t.Run("Positions", func(t *testing.T) {
+		var order []string
+		record := func(name string) mapstructure.DecodeHookFunc {
+			return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+				if t == reflect.TypeOf(time.Duration(0)) {
+					order = append(order, fmt.Sprintf("%s:%T", name, data))
+				}
+				return data, nil
+			}
+		}
+
+		c := New()
+		require.NoError(t, c.Register("timeout", time.Second))
+		require.NoError(t, c.Set("timeout", "5s"))
+		require.NoError(t, c.AddDecodeHook(record("after"), HookAfterBuiltins))
+		require.NoError(t, c.AddDecodeHook(record("before")))
+
+		var result struct {
+			Timeout time.Duration `toml:"timeout"`
+		}
+		require.NoError(t, c.Scan(&result))
+		assert.Equal(t, 5*time.Second, result.Timeout)
+		assert.Equal(t, []string{"before:string", "after:time.Duration"}, order)
+
+		assert.Error(t, c.AddDecodeHook(nil))
+		assert.Error(t, c.AddDecodeHook(record("bad"), HookPosition(9)))
+	})
+}
+
+// TestScanWithMetadata tests reporting of unused keys and unset fields
+func TestScanWithMetadata(t *testing.T) {
+	type ServerConfig struct {
+		Host    string `toml:"host"`
+		Port    int    `toml:"port"`
+		Timeout int    `toml:"timeout"`
+	}
+
+	cfg := New()
+	cfg.Register("app.server.host", "localhost")
+	cfg.Register("app.server.port", 8080)
+	cfg.Register("app.server.hots", "typo")
+	cfg.Register("app.server.tls.enabled", false)
+
+	t.Run("WithBasePath", func(t *testing.T) {
+		var server ServerConfig
+		md, err := cfg.ScanWithMetadata(&server, "app.server")
+		require.NoError(t, err)
+
+		assert.Equal(t, "localhost", server.Host)
+		assert.Equal(t, []string{"app.server.hots", "app.server.tls"}, md.Unused)
+		assert.Equal(t, []string{"app.server.timeout"}, md.Unset)
+	})
+
+	t.Run("FromRoot", func(t *testing.T) {
+		var root struct {
+			App struct {
+				Server ServerConfig `toml:"server"`
+			} `toml:"app"`
+		}
+		md, err := cfg.ScanWithMetadata(&root)
+		require.NoError(t, err)
+
+		assert.Equal(t, 8080, root.App.Server.Port)
+		assert.Equal(t, []string{"app.server.hots", "app.server.tls"}, md.Unused)
+		assert.Equal(t, []string{"app.server.timeout"}, md.Unset)
+	})
+
+	t.Run("DecodeError", func(t *testing.T) {
+		_, err := cfg.ScanWithMetadata(ServerConfig{})
+		assert.Error(t, err)
+	})
+}
+
+// TestScanArgumentForms tests the target-first, variadic basePath form of the scan methods
+func TestScanArgumentForms(t *testing.T) {
+	type ServerConfig struct {
+		Host string `toml:"host"`
+		Port int    `toml:"port"`
+	}
+	type RootConfig struct {
+		Server ServerConfig `toml:"server"`
+	}
+
+	cfg := New()
+	cfg.Register("server.host", "localhost")
+	cfg.Register("server.port", 8080)
+	cfg.SetSource(SourceEnv, "server.port", 9090)
+	cfg.SetSource(SourceFile, "server.host", "filehost")
+
+	t.Run("Scan", func(t *testing.T) {
+		var root RootConfig
+		require.NoError(t, cfg.Scan(&root))
+		assert.Equal(t, 9090, root.Server.Port)
+
+		var server ServerConfig
+		require.NoError(t, cfg.Scan(&server, "server"))
+		assert.Equal(t, "filehost", server.Host)
+		assert.Equal(t, 9090, server.Port)
+	})
+
+	t.Run("ScanSource", func(t *testing.T) {
+		var root RootConfig
+		require.NoError(t, cfg.ScanSource(SourceEnv, &root))
+		assert.Equal(t, 9090, root.Server.Port)
+		assert.Empty(t, root.Server.Host)
+
+		var server ServerConfig
+		require.NoError(t, cfg.ScanSource(SourceFile, &server, "server"))
+		assert.Equal(t, "filehost", server.Host)
+		assert.Zero(t, server.Port)
+	})
+
+	t.Run("ScanTyped", func(t *testing.T) {
+		root, err := ScanTyped[RootConfig](cfg)
+		require.NoError(t, err)
+		assert.Equal(t, 9090, root.Server.Port)
+
+		server, err := ScanTyped[ServerConfig](cfg, "server")
+		require.NoError(t, err)
+		assert.Equal(t, "filehost", server.Host)
+	})
+
+	t.Run("TooManyBasePaths", func(t *testing.T) {
+		var server ServerConfig
+		err := cfg.Scan(&server, "server", "extra")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "too many basePath arguments")
+
+		_, err = ScanTyped[ServerConfig](cfg, "server", "extra")
+		assert.Error(t, err)
+	})
+}
+
+// TestSquashedStructs tests that ",squash" structs are flattened on registration and Scan
+func TestSquashedStructs(t *testing.T) {
+	type Common struct {
+		LogLevel string `toml:"log_level"`
+	}
+	type Limits struct {
+		MaxConns int `toml:"max_conns"`
+	}
+	type Metrics struct {
+		Enabled bool `toml:"enabled"`
+	}
+	type AppConfig struct {
+		Common  `toml:",squash"`
+		Limits  Limits  `toml:",squash"`
+		Metrics Metrics `toml:"metrics"`
+		Name    string  `toml:"name"`
+	}
+
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("app.", AppConfig{
+		Common: Common{LogLevel: "info"},
+		Limits: Limits{MaxConns: 10},
+		Name:   "svc",
+	}))
+
+	paths := cfg.GetRegisteredPaths("app.")
+	assert.True(t, paths["app.log_level"])
+	assert.True(t, paths["app.max_conns"])
+	assert.True(t, paths["app.metrics.enabled"])
+	assert.False(t, paths["app.Limits.max_conns"])
+
+	// Defaults round-trip through Scan
+	var target AppConfig
+	require.NoError(t, cfg.Scan(&target, "app"))
+	assert.Equal(t, "info", target.LogLevel)
+	assert.Equal(t, 10, target.Limits.MaxConns)
+	assert.Equal(t, "svc", target.Name)
+
+	// Source values reach the squashed fields
+	require.NoError(t, cfg.Set("app.log_level", "debug"))
+	require.NoError(t, cfg.SetSource(SourceEnv, "app.max_conns", "25"))
+	require.NoError(t, cfg.Set("app.metrics.enabled", true))
+
+	target = AppConfig{}
+	require.NoError(t, cfg.Scan(&target, "app"))
+	assert.Equal(t, "debug", target.LogLevel)
+	assert.Equal(t, 25, target.Limits.MaxConns)
+	assert.True(t, target.Metrics.Enabled)
+
+	md, err := cfg.ScanWithMetadata(&target, "app")
+	require.NoError(t, err)
+	assert.Empty(t, md.Unused)
+	assert.Empty(t, md.Unset)
+}
+
+// TestEmbeddedStructScan tests that embedded structs round-trip from registration through Scan
+func TestEmbeddedStructScan(t *testing.T) {
+	type Base struct {
+		Host string `toml:"host"`
+	}
+	type Limits struct {
+		MaxConns int `toml:"max_conns"`
+	}
+	type Metrics struct {
+		Enabled bool `toml:"enabled"`
+	}
+	type App struct {
+		Base
+		*Limits
+		Metrics `toml:"metrics"` // Tagged embeds stay nested
+		Port    int              `toml:"port"`
+	}
+	defaults := App{Base: Base{Host: "h"}, Limits: &Limits{MaxConns: 10}, Port: 1}
+
+	t.Run("Scan", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.RegisterStruct("", defaults))
+
+		var app App
+		require.NoError(t, cfg.Scan(&app))
+		assert.Equal(t, "h", app.Host, "promoted defaults are decoded")
+		require.NotNil(t, app.Limits)
+		assert.Equal(t, 10, app.MaxConns)
+		assert.Equal(t, 1, app.Port)
+
+		require.NoError(t, cfg.Set("host", "example.com"))
+		require.NoError(t, cfg.Set("max_conns", 20))
+		require.NoError(t, cfg.Set("metrics.enabled", true))
+
+		app = App{}
+		require.NoError(t, cfg.Scan(&app))
+		assert.Equal(t, "example.com", app.Host)
+		assert.Equal(t, 20, app.MaxConns)
+		assert.True(t, app.Metrics.Enabled)
+
+		md, err := cfg.ScanWithMetadata(&app)
+		require.NoError(t, err)
+		assert.Empty(t, md.Unused)
+	})
+
+	t.Run("WithTarget", func(t *testing.T) {
+		target := defaults
+		cfg, err := NewBuilder().
+			WithTarget(&target).
+			Build()
+		require.NoError(t, err)
+		require.NoError(t, cfg.Set("host", "target.local"))
+
+		got, err := cfg.AsStruct()
+		require.NoError(t, err)
+		app := got.(*App)
+		assert.Equal(t, "target.local", app.Host)
+		assert.Equal(t, 1, app.Port)
+	})
+}
+
+// TestInlineStructScan tests that inline fields round-trip from registration through Scan
+func TestInlineStructScan(t *testing.T) {
+	type Limits struct {
+		MaxConns int `yaml:"max_conns"`
+	}
+	type AppConfig struct {
+		Name   string         `yaml:"name,omitempty"`
+		Limits Limits         `yaml:",inline"`
+		Extra  map[string]any `yaml:",inline"`
+		Labels map[string]any `yaml:"labels,omitempty"`
+	}
+
+	cfg, err := NewBuilder().
+		WithTagName("yaml").
+		WithDefaults(AppConfig{
+			Name:   "svc",
+			Limits: Limits{MaxConns: 10},
+			Extra:  map[string]any{"region": "eu"},
+			Labels: map[string]any{"team": "core"},
+		}).
+		Build()
+	require.NoError(t, err)
+
+	var app AppConfig
+	require.NoError(t, cfg.Scan(&app))
+	assert.Equal(t, "svc", app.Name)
+	assert.Equal(t, 10, app.Limits.MaxConns, "inline struct defaults are decoded")
+	assert.Equal(t, map[string]any{"region": "eu"}, app.Extra, "inline maps receive unclaimed keys")
+	assert.Equal(t, map[string]any{"team": "core"}, app.Labels)
+
+	require.NoError(t, cfg.Set("max_conns", "25"))
+	require.NoError(t, cfg.Set("region", "us"))
+
+	app = AppConfig{}
+	require.NoError(t, cfg.Scan(&app))
+	assert.Equal(t, 25, app.Limits.MaxConns)
+	assert.Equal(t, "us", app.Extra["region"])
+
+	md, err := cfg.ScanWithMetadata(&app)
+	require.NoError(t, err)
+	assert.Empty(t, md.Unused)
 }
\ No newline at end of file
@@ -45,16 +45,25 @@ func DefaultDiscoveryOptions(appName string) FileDiscoveryOptions {
 
 // WithFileDiscovery enables automatic config file discovery
 func (b *Builder) WithFileDiscovery(opts FileDiscoveryOptions) *Builder {
+	b.fileDiscovery = true
+
 	// Check CLI args first (highest priority)
 	if opts.CLIFlag != "" && len(b.args) > 0 {
+		// Accept the single-dash form of long flags (-config) as parseArgs does
+		flags := []string{opts.CLIFlag}
+		if strings.HasPrefix(opts.CLIFlag, "--") {
+			flags = append(flags, opts.CLIFlag[1:])
+		}
 		for i, arg := range b.args {
-			if arg == opts.CLIFlag && i+1 < len(b.args) {
-				b.file = b.args[i+1]
-				return b
-			}
-			if strings.HasPrefix(arg, opts.CLIFlag+"=") {
-				b.file = strings.TrimPrefix(arg, opts.CLIFlag+"=")
-				return b
+			for _, flag := range flags {
+				if arg == flag && i+1 < len(b.args) {
+					b.file = b.args[i+1]
+					return b
+				}
+				if strings.HasPrefix(arg, flag+"=") {
+					b.file = strings.TrimPrefix(arg, flag+"=")
+					return b
+				}
 			}
 		}
 	}
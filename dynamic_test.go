@@ -2,10 +2,10 @@
 package config
 
 import (
-	"encoding/json"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -71,20 +71,8 @@ database:
 		host, _ = cfg.Get("server.host")
 		assert.Equal(t, "json-host", host)
 		port, _ := cfg.Get("server.port")
-		// JSON number should be preserved as json.Number but convertible
-		switch v := port.(type) {
-		case json.Number:
-			// Expected for raw value
-			assert.Equal(t, json.Number("9090"), v)
-		case int64:
-			// Expected after decode hook conversion
-			assert.Equal(t, int64(9090), v)
-		case float64:
-			// Alternative conversion
-			assert.Equal(t, float64(9090), v)
-		default:
-			t.Errorf("Unexpected type for port: %T", port)
-		}
+		// JSON integers are normalized to int64 at load time, as with TOML
+		assert.Equal(t, int64(9090), port)
 
 		// Test YAML
 		require.NoError(t, cfg.LoadFile(yamlPath))
@@ -121,6 +109,38 @@ database:
 		host, _ := cfg.Get("server.host")
 		assert.Equal(t, "yaml-host", host)
 	})
+
+	t.Run("JSONNumbers", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("count", 0)
+		cfg.Register("ratio", 0.0)
+		cfg.Register("scaled", 0.0)
+		cfg.Register("huge", 0)
+		cfg.Register("list", []any{})
+
+		numbersPath := filepath.Join(tmpDir, "numbers.json")
+		require.NoError(t, os.WriteFile(numbersPath, []byte(`{
+			"count": 42,
+			"ratio": 0.5,
+			"scaled": 1e3,
+			"huge": 9223372036854775808,
+			"list": [1, 2.5]
+		}`), 0644))
+		require.NoError(t, cfg.LoadFile(numbersPath))
+
+		count, _ := cfg.Get("count")
+		assert.Equal(t, int64(42), count)
+		ratio, _ := cfg.Get("ratio")
+		assert.Equal(t, 0.5, ratio)
+		scaled, _ := cfg.Get("scaled")
+		assert.Equal(t, float64(1000), scaled)
+		list, _ := cfg.Get("list")
+		assert.Equal(t, []any{int64(1), 2.5}, list)
+
+		// Integers above the int64 range fall back to float64
+		huge, _ := cfg.Get("huge")
+		assert.Equal(t, float64(9223372036854775808), huge)
+	})
 }
 
 // TestDynamicFormatSwitching tests runtime format changes
@@ -298,6 +318,116 @@ func TestSecurityOptions(t *testing.T) {
 		err := cfg.LoadFile(ownedPath)
 		assert.NoError(t, err)
 	})
+	// Every file entry point shares one loadFile, so options apply to all formats and callers
+	t.Run("AllEntryPoints", func(t *testing.T) {
+		opts := SecurityOptions{MaxFileSize: 64}
+		large := `{"test": "` + strings.Repeat("x", 100) + `"}`
+
+		for _, name := range []string{"large.json", "large.yaml", "large.toml"} {
+			path := filepath.Join(tmpDir, name)
+			content := large
+			switch filepath.Ext(name) {
+			case ".yaml":
+				content = "test: " + strings.Repeat("x", 100) + "\n"
+			case ".toml":
+				content = `test = "` + strings.Repeat("x", 100) + `"`
+			}
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+			cfg := New()
+			cfg.Register("test", "")
+			cfg.SetSecurityOptions(opts)
+
+			assert.ErrorContains(t, cfg.LoadFile(path), "exceeds maximum size", name)
+			assert.ErrorContains(t, cfg.Load(path, nil), "exceeds maximum size", name)
+			assert.ErrorContains(t, cfg.LoadReader(strings.NewReader(content), "auto"), "exceeds maximum size", name)
+
+			_, err := NewBuilder().
+				WithDefaults(struct {
+					Test string `toml:"test"`
+				}{}).
+				WithSecurityOptions(opts).
+				WithFile(path).
+				WithArgs(nil).
+				Build()
+			assert.ErrorContains(t, err, "exceeds maximum size", name)
+		}
+	})
+
+	t.Run("AllowedDir", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Skipping symlink test on Windows")
+		}
+
+		jail := filepath.Join(tmpDir, "jail")
+		outside := filepath.Join(tmpDir, "outside")
+		require.NoError(t, os.MkdirAll(filepath.Join(jail, "conf.d"), 0755))
+		require.NoError(t, os.MkdirAll(outside, 0755))
+
+		inside := filepath.Join(jail, "conf.d", "app.toml")
+		secret := filepath.Join(outside, "secret.toml")
+		require.NoError(t, os.WriteFile(inside, []byte(`test = "inside"`), 0644))
+		require.NoError(t, os.WriteFile(secret, []byte(`test = "secret"`), 0644))
+
+		escaping := filepath.Join(jail, "escape.toml")
+		linkedInside := filepath.Join(jail, "app.toml")
+		require.NoError(t, os.Symlink(secret, escaping))
+		require.NoError(t, os.Symlink(inside, linkedInside))
+
+		cfg := New()
+		cfg.Register("test", "")
+		cfg.SetSecurityOptions(SecurityOptions{AllowedDir: jail})
+
+		err := cfg.LoadFile(escaping)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "outside allowed directory")
+		val, _ := cfg.Get("test")
+		assert.Equal(t, "", val)
+
+		assert.Error(t, cfg.LoadFile(secret), "direct path outside the jail")
+		assert.ErrorIs(t, cfg.LoadFile(filepath.Join(jail, "missing.toml")), ErrConfigNotFound)
+
+		require.NoError(t, cfg.LoadFile(inside))
+		require.NoError(t, cfg.LoadFile(linkedInside), "symlink within the jail")
+		assert.Equal(t, linkedInside, cfg.configFilePath, "the symlink, not its target, is watched")
+		val, _ = cfg.Get("test")
+		assert.Equal(t, "inside", val)
+
+		// Unset AllowedDir keeps following symlinks anywhere
+		open := New()
+		open.Register("test", "")
+		require.NoError(t, open.LoadFile(escaping))
+		val, _ = open.Get("test")
+		assert.Equal(t, "secret", val)
+	})
+
+	t.Run("WatcherReload", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "watched.toml")
+		require.NoError(t, os.WriteFile(path, []byte(`test = "small"`), 0644))
+
+		cfg := New()
+		cfg.Register("test", "")
+		cfg.SetSecurityOptions(SecurityOptions{MaxFileSize: 64})
+		require.NoError(t, cfg.LoadFile(path))
+
+		cfg.AutoUpdateWithOptions(WatchOptions{PollInterval: testPollInterval, Debounce: testDebounce})
+		defer cfg.StopAutoUpdate()
+		changes := cfg.Watch()
+		waitForWatchingState(t, cfg, true)
+
+		require.NoError(t, os.WriteFile(path, []byte(`test = "`+strings.Repeat("x", 100)+`"`), 0644))
+
+		select {
+		case event := <-changes:
+			assert.Contains(t, event, "reload_error")
+			assert.Contains(t, event, "exceeds maximum size")
+		case <-time.After(testEventuallyTimeout):
+			t.Fatal("timeout waiting for reload error")
+		}
+
+		val, _ := cfg.Get("test")
+		assert.Equal(t, "small", val)
+	})
 }
 
 // waitForWatchingState waits for watcher state, preventing race conditions of goroutine start and test check
@@ -352,18 +482,7 @@ func TestBuilderWithFormat(t *testing.T) {
 
 	port, exists := cfg.Get("server.port")
 	assert.True(t, exists, "server.port should exist")
-	// Handle json.Number or converted int
-	switch v := port.(type) {
-	case json.Number:
-		p, _ := v.Int64()
-		assert.Equal(t, int64(8080), p)
-	case int64:
-		assert.Equal(t, int64(8080), v)
-	case float64:
-		assert.Equal(t, float64(8080), v)
-	default:
-		t.Errorf("Unexpected type for port: %T", port)
-	}
+	assert.Equal(t, int64(8080), port)
 }
 
 // BenchmarkFormatParsing benchmarks different format parsing speeds
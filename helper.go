@@ -1,7 +1,24 @@
 // FILE: lixenwraith/config/helper.go
 package config
 
-import "strings"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxPathIndex bounds slice indices in paths to prevent unbounded slice growth
+const maxPathIndex = 1<<16 - 1
+
+// pathSegment is a single component of a path: either a map key or a slice index
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
 
 // flattenMap converts a nested map[string]any to a flat map[string]any with dot-notation paths.
 func flattenMap(nested map[string]any, prefix string) map[string]any {
@@ -30,6 +47,37 @@ func flattenMap(nested map[string]any, prefix string) map[string]any {
 	return flat
 }
 
+// exceedsDepth reports whether value nests maps or slices more than maxDepth levels deep,
+// counting value itself as level depth. A leaf at "a.b.c" sits in three levels of maps.
+// Recursion stops as soon as the limit is passed.
+func exceedsDepth(value any, depth, maxDepth int) bool {
+	var children []any
+	switch v := value.(type) {
+	case map[string]any:
+		for _, child := range v {
+			children = append(children, child)
+		}
+	case []any:
+		children = v
+	case []map[string]any:
+		for _, child := range v {
+			children = append(children, child)
+		}
+	default:
+		return false
+	}
+
+	if depth > maxDepth {
+		return true
+	}
+	for _, child := range children {
+		if exceedsDepth(child, depth+1, maxDepth) {
+			return true
+		}
+	}
+	return false
+}
+
 // setNestedValue sets a value in a nested map using a dot-notation path.
 // It creates intermediate maps if they don't exist.
 // If a segment exists but is not a map, it will be overwritten by a new map.
@@ -64,6 +112,20 @@ func setNestedValue(nested map[string]any, path string, value any) {
 	current[lastSegment] = value
 }
 
+// mergeNested merges src into dst recursively; tables present in both are merged
+// key by key, and any other value in src replaces the one in dst
+func mergeNested(dst, src map[string]any) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeNested(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
 // isValidKeySegment checks if a single path segment is a valid TOML key part.
 func isValidKeySegment(s string) bool {
 	if len(s) == 0 {
@@ -85,4 +147,260 @@ func isValidKeySegment(s string) bool {
 		}
 	}
 	return true
+}
+
+// isValidIndexedSegment checks if a path segment is a valid key optionally followed by [n] indices.
+func isValidIndexedSegment(s string) bool {
+	key, indices, hasIndex := strings.Cut(s, "[")
+	if !isValidKeySegment(key) {
+		return false
+	}
+	if !hasIndex {
+		return true
+	}
+	_, err := parseIndexedPath("[" + indices)
+	return err == nil
+}
+
+// splitIndexedPath splits a path like "server.hosts[1].name" into its base path
+// "server.hosts" and the indexed remainder "[1].name".
+func splitIndexedPath(path string) (base, rest string, ok bool) {
+	i := strings.IndexByte(path, '[')
+	if i <= 0 {
+		return "", "", false
+	}
+	return path[:i], path[i:], true
+}
+
+// parseIndexedPath parses a dot-notation path with optional [n] index suffixes into segments.
+// A leading index without a key (e.g., "[0].name") is allowed for path remainders.
+func parseIndexedPath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+
+	for i, part := range strings.Split(path, ".") {
+		key, indices := part, ""
+		if b := strings.IndexByte(part, '['); b >= 0 {
+			key, indices = part[:b], part[b:]
+		}
+
+		if key != "" {
+			if !isValidKeySegment(key) {
+				return nil, errInvalidSegment(path, part)
+			}
+			segments = append(segments, pathSegment{key: key})
+		} else if i > 0 || indices == "" {
+			return nil, errInvalidSegment(path, part)
+		}
+
+		for indices != "" {
+			end := strings.IndexByte(indices, ']')
+			if indices[0] != '[' || end < 2 {
+				return nil, fmt.Errorf("invalid index in path segment %q", part)
+			}
+			digits := indices[1:end]
+			for _, r := range digits {
+				if r < '0' || r > '9' {
+					return nil, fmt.Errorf("invalid index in path segment %q", part)
+				}
+			}
+			n, err := strconv.Atoi(digits)
+			if err != nil || n > maxPathIndex {
+				return nil, fmt.Errorf("index out of bounds in path segment %q", part)
+			}
+			segments = append(segments, pathSegment{index: n, isIndex: true})
+			indices = indices[end+1:]
+		}
+	}
+
+	return segments, nil
+}
+
+// getIndexedValue traverses a value along the given segments through slices and maps.
+// Returns false if any segment is missing or an index is out of range.
+func getIndexedValue(value any, segments []pathSegment) (any, bool) {
+	current := value
+	for _, seg := range segments {
+		if seg.isIndex {
+			rv := reflect.ValueOf(current)
+			if !rv.IsValid() || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+				return nil, false
+			}
+			if seg.index >= rv.Len() {
+				return nil, false
+			}
+			current = rv.Index(seg.index).Interface()
+			continue
+		}
+
+		m, err := normalizeMap(current)
+		if err != nil || current == nil {
+			return nil, false
+		}
+		next, exists := m[seg.key]
+		if !exists {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// setIndexedValue returns a copy of container with value placed at the given segments.
+// Slices along the path are converted to []any and grown as needed; maps are copied.
+func setIndexedValue(container any, segments []pathSegment, value any) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	seg := segments[0]
+	if seg.isIndex {
+		var elems []any
+		if container != nil {
+			rv := reflect.ValueOf(container)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				return nil, fmt.Errorf("cannot index into non-slice value of type %T", container)
+			}
+			elems = make([]any, rv.Len())
+			for i := range elems {
+				elems[i] = rv.Index(i).Interface()
+			}
+		}
+		for len(elems) <= seg.index {
+			elems = append(elems, nil)
+		}
+
+		child, err := setIndexedValue(elems[seg.index], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		elems[seg.index] = child
+		return elems, nil
+	}
+
+	src, err := normalizeMap(container)
+	if err != nil {
+		return nil, fmt.Errorf("cannot set key %q on non-map value of type %T", seg.key, container)
+	}
+	m := make(map[string]any, len(src)+1)
+	for k, v := range src {
+		m[k] = v
+	}
+
+	child, err := setIndexedValue(m[seg.key], segments[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	m[seg.key] = child
+	return m, nil
+}
+
+// copyValue returns a deep copy of maps and slices so callers cannot mutate internal state.
+// Other values are returned as-is.
+func copyValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for key, elem := range v {
+			copied[key] = copyValue(elem)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, elem := range v {
+			copied[i] = copyValue(elem)
+		}
+		return copied
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		reflect.Copy(copied, rv)
+		return copied.Interface()
+	case reflect.Map:
+		if rv.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return copied.Interface()
+	}
+	return value
+}
+
+// normalizeNumbers converts numeric values in parsed file data to canonical types in place:
+// integers become int64 and floats become float64, as TOML produces. json.Number values
+// become int64 when integral and float64 otherwise. Integers above the int64 range, from
+// JSON or unsigned YAML values, become float64 and may lose precision. A JSON number beyond
+// the float64 range is left as json.Number. TOML arrays of tables become []any of maps.
+func normalizeNumbers(data map[string]any) {
+	for key, value := range data {
+		data[key] = normalizeNumber(value)
+	}
+}
+
+// normalizeNumber returns the canonical form of a single parsed value, recursing into maps and slices
+func normalizeNumber(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		normalizeNumbers(v)
+		return v
+	case []any:
+		for i, elem := range v {
+			v[i] = normalizeNumber(elem)
+		}
+		return v
+	case []map[string]any:
+		// TOML arrays of tables become []any, as JSON and YAML lists do
+		list := make([]any, len(v))
+		for i, elem := range v {
+			normalizeNumbers(elem)
+			list[i] = elem
+		}
+		return list
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint:
+		return normalizeUint(uint64(v))
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		return normalizeUint(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
+
+// normalizeUint returns v as int64, or float64 if it exceeds the int64 range
+func normalizeUint(v uint64) any {
+	if v > math.MaxInt64 {
+		return float64(v)
+	}
+	return int64(v)
 }
\ No newline at end of file
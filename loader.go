@@ -7,11 +7,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
@@ -31,6 +37,15 @@ const (
 	SourceCLI Source = "cli"
 )
 
+// Valid reports whether s is one of the predefined sources
+func (s Source) Valid() bool {
+	switch s {
+	case SourceDefault, SourceFile, SourceEnv, SourceCLI:
+		return true
+	}
+	return false
+}
+
 // LoadMode defines how configuration sources are processed
 type LoadMode int
 
@@ -57,9 +72,15 @@ type LoadOptions struct {
 	EnvPrefix string
 
 	// EnvTransform customizes how paths map to environment variables
-	// If nil, uses default transformation (dots to underscores, uppercase)
+	// If nil, uses default transformation (dots to EnvSeparator, uppercase)
 	EnvTransform EnvTransformFunc
 
+	// EnvSeparator replaces the dots between path segments in default env var names.
+	// Use "__" when segments contain underscores, so "server.max_conns" becomes
+	// SERVER__MAX_CONNS and cannot collide with "server_max.conns".
+	// Default: "_"
+	EnvSeparator string
+
 	// LoadMode determines how values are merged
 	LoadMode LoadMode
 
@@ -68,6 +89,46 @@ type LoadOptions struct {
 
 	// SkipValidation skips path validation during load
 	SkipValidation bool
+
+	// Strict rejects config files containing keys that match no registered path
+	// Default: false (unknown keys are ignored)
+	Strict bool
+
+	// AutoRegisterUnknown registers unknown leaf keys from config files,
+	// using the file value as the default. Checked before Strict.
+	// Default: false (unknown keys are ignored)
+	AutoRegisterUnknown bool
+
+	// ReloadEnv makes SetLoadOptions re-read environment variables when EnvPrefix or
+	// EnvWhitelist changed, or EnvTransform is set. The env source is replaced, so
+	// values no longer matched are removed.
+	// Default: false (env values are kept until the next load)
+	ReloadEnv bool
+
+	// CoerceToDefaultType converts env and CLI strings to the type of the registered
+	// default when they are stored, so every source holds the same type for a path.
+	// Values that fail to convert are stored as strings.
+	// Default: false (strings are stored and converted when read)
+	CoerceToDefaultType bool
+
+	// Hooks are called around each source while loading, for logging and metrics
+	Hooks LoadHooks
+
+	// TrimEnvWhitespace removes whitespace around env values before quotes are stripped
+	// Default: false (whitespace outside quotes is kept)
+	TrimEnvWhitespace bool
+}
+
+// LoadHooks observes the phases of a load. Each hook is optional. Load calls them
+// for the file, env, and CLI sources in load order, lowest precedence first, even
+// when no file or arguments are given; defaults are already in place and are skipped.
+type LoadHooks struct {
+	// BeforeSource is called before a source is loaded
+	BeforeSource func(source Source)
+
+	// AfterSource is called after a source is loaded with the number of registered
+	// paths it set and the error it returned, if any
+	AfterSource func(source Source, count int, err error)
 }
 
 // DefaultLoadOptions returns the standard load options
@@ -78,51 +139,131 @@ func DefaultLoadOptions() LoadOptions {
 	}
 }
 
+// ReloadOptions controls which source values survive Reload
+type ReloadOptions struct {
+	// PreserveSources keep their current values and are not re-read. Other built-in
+	// sources are cleared; the file and env sources are then read again.
+	PreserveSources []Source
+
+	// ClearCustomSources also clears sources added with RegisterSource that are not
+	// in PreserveSources. Loads never fill them, so by default they are kept.
+	ClearCustomSources bool
+}
+
+// DefaultReloadOptions returns reload options that preserve command-line values,
+// which cannot be re-read
+func DefaultReloadOptions() ReloadOptions {
+	return ReloadOptions{PreserveSources: []Source{SourceCLI}}
+}
+
 // Load reads configuration from a TOML file and merges overrides from command-line arguments.
 // This is a convenience method that maintains backward compatibility.
 func (c *Config) Load(filePath string, args []string) error {
 	return c.LoadWithOptions(filePath, args, c.options)
 }
 
+// LoadReport describes what each source contributed to a load
+type LoadReport struct {
+	Counts    map[Source]int // Registered paths set by each source during the load
+	FilePath  string         // Absolute path of the config file, even if missing; empty if none was given
+	FileFound bool           // Whether the config file existed and was loaded
+	EnvVars   []string       // Environment variables that matched registered paths, sorted
+}
+
 // LoadWithOptions loads configuration from multiple sources with custom options
 func (c *Config) LoadWithOptions(filePath string, args []string, opts LoadOptions) error {
+	return c.load(filePath, args, opts, nil)
+}
+
+// LoadWithReport loads like LoadWithOptions and reports which sources contributed.
+// The report is filled in as far as loading got, even when an error is returned.
+func (c *Config) LoadWithReport(filePath string, args []string, opts LoadOptions) (LoadReport, error) {
+	report := LoadReport{Counts: make(map[Source]int)}
+	err := c.load(filePath, args, opts, &report)
+	return report, err
+}
+
+// LoadOptional loads like LoadWithOptions but treats a missing config file as
+// non-fatal: found reports whether the file was loaded, and ErrConfigNotFound is
+// dropped from the returned error. Any other error is still returned.
+func (c *Config) LoadOptional(filePath string, args []string, opts LoadOptions) (found bool, err error) {
+	report, err := c.LoadWithReport(filePath, args, opts)
+	return report.FileFound, withoutNotFound(err)
+}
+
+// withoutNotFound removes ErrConfigNotFound from err, including from joined errors
+func withoutNotFound(err error) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var rest []error
+		for _, e := range joined.Unwrap() {
+			if !errors.Is(e, ErrConfigNotFound) {
+				rest = append(rest, e)
+			}
+		}
+		return errors.Join(rest...)
+	}
+	if errors.Is(err, ErrConfigNotFound) {
+		return nil
+	}
+	return err
+}
+
+// load implements LoadWithOptions, recording source contributions in report when non-nil
+func (c *Config) load(filePath string, args []string, opts LoadOptions, report *LoadReport) error {
+	if err := c.checkLoadable(); err != nil {
+		return err
+	}
+
 	c.mutex.Lock()
 	c.options = opts
 	c.mutex.Unlock()
 
+	// Hooks need per-source counts even when the caller asked for no report
+	hooks := opts.Hooks
+	if report == nil && hooks.AfterSource != nil {
+		report = &LoadReport{Counts: make(map[Source]int)}
+	}
+
 	var loadErrors []error
 
 	// Process each source according to precedence (in reverse order for proper layering)
 	for i := len(opts.Sources) - 1; i >= 0; i-- {
 		source := opts.Sources[i]
-
-		switch source {
-		case SourceDefault:
-			// Defaults are already in place from Register calls
+		if !source.Valid() || source == SourceDefault {
+			// Defaults are already in place from Register calls, and custom sources are set directly
 			continue
+		}
+
+		if hooks.BeforeSource != nil {
+			hooks.BeforeSource(source)
+		}
+		var before int
+		if report != nil {
+			before = report.Counts[source]
+		}
 
+		var err error
+		switch source {
 		case SourceFile:
 			if filePath != "" {
-				if err := c.loadFile(filePath); err != nil {
-					if errors.Is(err, ErrConfigNotFound) {
-						loadErrors = append(loadErrors, err)
-					} else {
-						return err // Fatal error
-					}
-				}
+				err = c.loadFile(filePath, report)
 			}
-
 		case SourceEnv:
-			if err := c.loadEnv(opts); err != nil {
-				loadErrors = append(loadErrors, err)
-			}
-
+			err = c.loadEnv(opts, report)
 		case SourceCLI:
 			if len(args) > 0 {
-				if err := c.loadCLI(args); err != nil {
-					loadErrors = append(loadErrors, err)
-				}
+				err = c.loadCLI(args, report)
+			}
+		}
+
+		if hooks.AfterSource != nil {
+			hooks.AfterSource(source, report.Counts[source]-before, err)
+		}
+		if err != nil {
+			if source == SourceFile && !errors.Is(err, ErrConfigNotFound) {
+				return err // Fatal error
 			}
+			loadErrors = append(loadErrors, err)
 		}
 	}
 
@@ -133,21 +274,140 @@ func (c *Config) LoadWithOptions(filePath string, args []string, opts LoadOption
 func (c *Config) LoadEnv(prefix string) error {
 	opts := c.options
 	opts.EnvPrefix = prefix
-	return c.loadEnv(opts)
+	return c.loadEnv(opts, nil)
 }
 
 // LoadCLI loads configuration values from command-line arguments
 func (c *Config) LoadCLI(args []string) error {
-	return c.loadCLI(args)
+	return c.loadCLI(args, nil)
 }
 
-// LoadFile loads configuration values from a TOML file
+// LoadFile loads configuration values from a TOML, JSON, or YAML file, applying the security options
 func (c *Config) LoadFile(filePath string) error {
-	return c.loadFile(filePath)
+	return c.loadFile(filePath, nil)
+}
+
+// Reload re-reads the loaded config file and environment variables with
+// DefaultReloadOptions, keeping values set at SourceCLI
+func (c *Config) Reload() error {
+	return c.ReloadWithOptions(DefaultReloadOptions())
+}
+
+// ReloadWithOptions re-reads the config file and environment variables, keeping the
+// values of opts.PreserveSources and, unless opts.ClearCustomSources is set, of custom
+// sources. Other sources, including SourceCLI unless preserved, are cleared. The file source is replaced by the last loaded file, if any; the env
+// source is re-read with the current LoadOptions. Paths whose value changed are sent
+// to watch subscribers with ChangeCauseReload. A read error leaves values unchanged.
+func (c *Config) ReloadWithOptions(opts ReloadOptions) error {
+	if err := c.checkLoadable(); err != nil {
+		return err
+	}
+
+	c.mutex.RLock()
+	for _, source := range opts.PreserveSources {
+		if err := c.validateSource(source); err != nil {
+			c.mutex.RUnlock()
+			return err
+		}
+	}
+	filePath := c.configFilePath
+	loadOpts := c.options
+	c.mutex.RUnlock()
+
+	preserved := func(source Source) bool { return slices.Contains(opts.PreserveSources, source) }
+
+	// Read env before changing anything, so a failed read leaves the config untouched
+	var env map[string]any
+	if !preserved(SourceEnv) {
+		found, _, err := c.readEnv(loadOpts)
+		if err != nil {
+			return err
+		}
+		env = c.envStoredValues(found, loadOpts)
+	}
+
+	oldValues := c.snapshot()
+	if filePath != "" && !preserved(SourceFile) {
+		if err := c.loadFile(filePath, nil); err != nil {
+			return err
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// The file source was replaced above; clear the others that are not preserved
+	cleared := []Source{SourceEnv, SourceCLI}
+	if opts.ClearCustomSources {
+		cleared = append(cleared, slices.Collect(maps.Keys(c.customData))...)
+	}
+	cleared = slices.DeleteFunc(cleared, preserved)
+	for _, source := range cleared {
+		c.clearSourceData(source)
+		c.forgetRemotePaths(source)
+	}
+	if filePath != "" && !preserved(SourceFile) {
+		c.forgetRemotePaths(SourceFile)
+	}
+
+	for path, item := range c.items {
+		for _, source := range cleared {
+			item.unsetSource(source)
+		}
+		item.currentValue = c.computeValue(item)
+		c.storeItem(path, item)
+	}
+	if !preserved(SourceEnv) {
+		c.applyEnvValues(env, time.Now())
+	}
+	c.notifyChanges(c.changesSince(oldValues, ChangeCauseReload))
+	c.invalidateCache()
+	return nil
 }
 
-// loadFile reads and parses a TOML configuration file
-func (c *Config) loadFile(path string) error {
+// loadFile reads, checks, and applies a config file. When a profile is set, the
+// profile overlay next to the file is merged over it. When report is non-nil, the
+// file's resolved path, existence, and contributed path count are recorded in it.
+func (c *Config) loadFile(path string, report *LoadReport) error {
+	if err := c.checkLoadable(); err != nil {
+		return err
+	}
+
+	if report != nil {
+		report.FilePath, _ = filepath.Abs(path)
+	}
+
+	fileConfig, readPath, err := c.readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.RLock()
+	profile := c.profile
+	c.mutex.RUnlock()
+	if profile != "" {
+		overlay, _, err := c.readConfigFile(profilePath(path, profile))
+		switch {
+		case err == nil:
+			mergeNested(fileConfig, overlay)
+		case !errors.Is(err, ErrConfigNotFound):
+			return err
+		}
+		// A missing overlay is skipped; the base file applies alone
+	}
+
+	if report != nil {
+		report.FilePath, _ = filepath.Abs(readPath)
+		report.FileFound = true
+	}
+
+	return c.applyFileConfig(fileConfig, path, report)
+}
+
+// readConfigFile applies the security checks to a config file, then reads and parses it.
+// It returns the parsed data and the path actually read, which differs from path when
+// AllowedDir resolves a symlink. A missing file returns ErrConfigNotFound.
+func (c *Config) readConfigFile(path string) (map[string]any, string, error) {
 	// Security: Path traversal check
 	if c.securityOpts != nil && c.securityOpts.PreventPathTraversal {
 		// Clean the path and check for traversal attempts
@@ -155,7 +415,7 @@ func (c *Config) loadFile(path string) error {
 
 		// Check if cleaned path tries to go outside current directory
 		if strings.HasPrefix(cleanPath, ".."+string(filepath.Separator)) || cleanPath == ".." {
-			return fmt.Errorf("potential path traversal detected in config path: %s", path)
+			return nil, "", fmt.Errorf("potential path traversal detected in config path: %s", path)
 		}
 
 		// Also check for absolute paths that might escape jail
@@ -163,23 +423,34 @@ func (c *Config) loadFile(path string) error {
 			// Absolute paths are OK if that's what was provided
 		} else if filepath.IsAbs(cleanPath) && !filepath.IsAbs(path) {
 			// Relative path became absolute after cleaning - suspicious
-			return fmt.Errorf("potential path traversal detected in config path: %s", path)
+			return nil, "", fmt.Errorf("potential path traversal detected in config path: %s", path)
+		}
+	}
+
+	// Security: Symlink-resolved containment check. The resolved file is read, while
+	// the given path is still what gets watched, so symlink swaps are noticed.
+	readPath := path
+	if c.securityOpts != nil && c.securityOpts.AllowedDir != "" {
+		realPath, err := resolveWithin(path, c.securityOpts.AllowedDir)
+		if err != nil {
+			return nil, "", err
 		}
+		readPath = realPath
 	}
 
 	// Read file with size limit
-	fileInfo, err := os.Stat(path)
+	fileInfo, err := os.Stat(readPath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return ErrConfigNotFound
+			return nil, "", ErrConfigNotFound
 		}
-		return fmt.Errorf("failed to stat config file '%s': %w", path, err)
+		return nil, "", fmt.Errorf("failed to stat config file '%s': %w", path, err)
 	}
 
 	// Security: File size check
 	if c.securityOpts != nil && c.securityOpts.MaxFileSize > 0 {
 		if fileInfo.Size() > c.securityOpts.MaxFileSize {
-			return fmt.Errorf("config file '%s' exceeds maximum size %d bytes", path, c.securityOpts.MaxFileSize)
+			return nil, "", fmt.Errorf("config file '%s' exceeds maximum size %d bytes", path, c.securityOpts.MaxFileSize)
 		}
 	}
 
@@ -187,16 +458,16 @@ func (c *Config) loadFile(path string) error {
 	if c.securityOpts != nil && c.securityOpts.EnforceFileOwnership && runtime.GOOS != "windows" {
 		if stat, ok := fileInfo.Sys().(*syscall.Stat_t); ok {
 			if stat.Uid != uint32(os.Geteuid()) {
-				return fmt.Errorf("config file '%s' is not owned by current user (file UID: %d, process UID: %d)",
+				return nil, "", fmt.Errorf("config file '%s' is not owned by current user (file UID: %d, process UID: %d)",
 					path, stat.Uid, os.Geteuid())
 			}
 		}
 	}
 
 	// 1. Read and parse file data
-	file, err := os.Open(path)
+	file, err := os.Open(readPath)
 	if err != nil {
-		return fmt.Errorf("failed to open config file '%s': %w", path, err)
+		return nil, "", fmt.Errorf("failed to open config file '%s': %w", path, err)
 	}
 	defer file.Close()
 
@@ -208,7 +479,7 @@ func (c *Config) loadFile(path string) error {
 
 	fileData, err := io.ReadAll(reader)
 	if err != nil {
-		return fmt.Errorf("failed to read config file '%s': %w", path, err)
+		return nil, "", fmt.Errorf("failed to read config file '%s': %w", path, err)
 	}
 
 	// Determine format
@@ -227,38 +498,235 @@ func (c *Config) loadFile(path string) error {
 	}
 
 	// Parse based on detected/specified format
+	fileConfig, err := c.parseFileData(fileData, format, fmt.Sprintf("file '%s'", path))
+	if err != nil {
+		return nil, "", err
+	}
+	return fileConfig, readPath, nil
+}
+
+// LoadReader loads configuration values from a reader into the File source.
+// Format must be "toml", "json", "yaml", or "auto" to detect from content.
+// Unlike LoadFile, the reader is not tracked for watching.
+func (c *Config) LoadReader(r io.Reader, format string) error {
+	if err := c.checkLoadable(); err != nil {
+		return err
+	}
+
+	data, err := c.readLimited(r)
+	if err != nil {
+		return fmt.Errorf("failed to read config data: %w", err)
+	}
+
+	fileConfig, err := c.parseReaderData(data, format)
+	if err != nil {
+		return err
+	}
+
+	return c.applyFileConfig(fileConfig, "", nil)
+}
+
+// loadFileBase parses data and installs it as the base layer of the File source.
+// Files loaded afterwards (including watcher reloads) overlay this base.
+func (c *Config) loadFileBase(data []byte, format string) error {
+	fileConfig, err := c.parseReaderData(data, format)
+	if err != nil {
+		return err
+	}
+
+	base, unknown := c.collectRegistered(fileConfig)
+	c.registerUnknown(unknown, base)
+	if err := c.checkStrict(unknown, ""); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	c.fileBase = base
+	c.mutex.Unlock()
+
+	return c.applyFileConfig(make(map[string]any), "", nil)
+}
+
+// resolveWithin resolves symlinks in path and dir and returns the real path of the
+// file, failing if it lies outside dir
+func resolveWithin(path, dir string) (string, error) {
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ErrConfigNotFound
+		}
+		return "", fmt.Errorf("failed to resolve config path '%s': %w", path, err)
+	}
+	realPath, err = filepath.Abs(realPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve config path '%s': %w", path, err)
+	}
+
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve allowed directory '%s': %w", dir, err)
+	}
+	realDir, err = filepath.Abs(realDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve allowed directory '%s': %w", dir, err)
+	}
+
+	rel, err := filepath.Rel(realDir, realPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("config path '%s' resolves outside allowed directory '%s'", path, dir)
+	}
+	return realPath, nil
+}
+
+// readLimited reads all data from r, honoring the MaxFileSize security option
+func (c *Config) readLimited(r io.Reader) ([]byte, error) {
+	c.mutex.RLock()
+	maxSize := int64(0)
+	if c.securityOpts != nil {
+		maxSize = c.securityOpts.MaxFileSize
+	}
+	c.mutex.RUnlock()
+
+	if maxSize <= 0 {
+		return io.ReadAll(r)
+	}
+
+	// Read one extra byte to detect oversized input
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("config data exceeds maximum size %d bytes", maxSize)
+	}
+	return data, nil
+}
+
+// parseReaderData resolves the format for in-memory data and parses it
+func (c *Config) parseReaderData(data []byte, format string) (map[string]any, error) {
+	switch format {
+	case "toml", "json", "yaml":
+		// Explicit format
+	case "", "auto":
+		format = detectFormatFromContent(data)
+		if format == "" {
+			format = c.tagName
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file format %q, must be one of: toml, json, yaml, auto", format)
+	}
+
+	return c.parseFileData(data, format, "data")
+}
+
+// parseFileData parses raw configuration data in the given format into a nested map,
+// rejecting data nested deeper than the MaxDepth security option.
+// The label describes the origin of the data for error messages.
+func (c *Config) parseFileData(data []byte, format, label string) (map[string]any, error) {
 	fileConfig := make(map[string]any)
 	switch format {
 	case "toml":
-		if err := toml.Unmarshal(fileData, &fileConfig); err != nil {
-			return fmt.Errorf("failed to parse TOML config file '%s': %w", path, err)
+		if err := toml.Unmarshal(data, &fileConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML config %s: %w", label, err)
 		}
 	case "json":
-		decoder := json.NewDecoder(bytes.NewReader(fileData))
+		decoder := json.NewDecoder(bytes.NewReader(data))
 		decoder.UseNumber() // Preserve number precision
 		if err := decoder.Decode(&fileConfig); err != nil {
-			return fmt.Errorf("failed to parse JSON config file '%s': %w", path, err)
+			return nil, fmt.Errorf("failed to parse JSON config %s: %w", label, err)
 		}
 	case "yaml":
-		if err := yaml.Unmarshal(fileData, &fileConfig); err != nil {
-			return fmt.Errorf("failed to parse YAML config file '%s': %w", path, err)
+		// Parse to a node tree first so alias expansion is bounded before any map is built
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config %s: %w", label, err)
+		}
+		maxNodes := int64(DefaultMaxYAMLNodes)
+		if c.securityOpts != nil && c.securityOpts.MaxYAMLNodes != 0 {
+			maxNodes = c.securityOpts.MaxYAMLNodes
+		}
+		if maxNodes > 0 && exceedsYAMLNodes(&doc, maxNodes) {
+			return nil, fmt.Errorf("config %s exceeds maximum of %d expanded YAML nodes", label, maxNodes)
+		}
+		if doc.Kind != 0 {
+			if err := doc.Decode(&fileConfig); err != nil {
+				return nil, fmt.Errorf("failed to parse YAML config %s: %w", label, err)
+			}
 		}
 	default:
-		return fmt.Errorf("unable to determine config format for file '%s'", path)
+		return nil, fmt.Errorf("unable to determine config format for %s", label)
+	}
+
+	// Bound nesting before any recursive processing of the data
+	maxDepth := DefaultMaxDepth
+	if c.securityOpts != nil && c.securityOpts.MaxDepth != 0 {
+		maxDepth = c.securityOpts.MaxDepth
+	}
+	if maxDepth > 0 && exceedsDepth(fileConfig, 1, maxDepth) {
+		return nil, fmt.Errorf("config %s exceeds maximum nesting depth %d", label, maxDepth)
+	}
+
+	// Same logical number yields the same type regardless of format
+	normalizeNumbers(fileConfig)
+	return fileConfig, nil
+}
+
+// exceedsYAMLNodes reports whether the node tree holds more than maxNodes nodes once
+// every alias is replaced by the node it refers to. Sizes of shared nodes are memoized,
+// so the check runs in time proportional to the unexpanded document.
+func exceedsYAMLNodes(root *yaml.Node, maxNodes int64) bool {
+	sizes := make(map[*yaml.Node]int64)
+
+	var size func(n *yaml.Node) int64
+	size = func(n *yaml.Node) int64 {
+		if n.Kind == yaml.AliasNode {
+			if n.Alias == nil {
+				return 1
+			}
+			n = n.Alias
+		}
+		if s, seen := sizes[n]; seen {
+			return s
+		}
+		sizes[n] = 1 // Self-referencing anchors are rejected by the decoder
+
+		total := int64(1)
+		for _, child := range n.Content {
+			total += size(child)
+			if total > maxNodes {
+				total = maxNodes + 1
+				break
+			}
+		}
+		sizes[n] = total
+		return total
 	}
 
-	// 2. Prepare New State (Read-Lock Only)
-	newFileData := make(map[string]any)
+	return size(root) > maxNodes
+}
+
+// collectRegistered flattens parsed file data, keeping only registered paths.
+// Keys that match no registered path, including whole sections without any
+// registered leaves, are returned as unknown with their values.
+func (c *Config) collectRegistered(fileConfig map[string]any) (collected, unknown map[string]any) {
+	collected = make(map[string]any)
+	unknown = make(map[string]any)
 
-	// Briefly acquire a read-lock to safely get the list of registered paths.
+	// Briefly acquire a read-lock to safely get the list of registered paths and their parent sections.
 	c.mutex.RLock()
 	registeredPaths := make(map[string]bool, len(c.items))
+	sections := make(map[string]bool)
 	for p := range c.items {
 		registeredPaths[p] = true
+		for i := 0; i < len(p); i++ {
+			if p[i] == '.' {
+				sections[p[:i]] = true
+			}
+		}
 	}
 	c.mutex.RUnlock()
 
-	// Define a recursive function to populate newFileData. This runs without any lock.
+	// Define a recursive function to populate collected. This runs without any lock.
 	var apply func(prefix string, data map[string]any)
 	apply = func(prefix string, data map[string]any) {
 		for key, value := range data {
@@ -267,70 +735,166 @@ func (c *Config) loadFile(path string) error {
 				fullPath = prefix + "." + key
 			}
 			if registeredPaths[fullPath] {
-				newFileData[fullPath] = value
-			} else if subMap, isMap := value.(map[string]any); isMap {
+				collected[fullPath] = value
+			} else if subMap, isMap := value.(map[string]any); isMap && sections[fullPath] {
 				apply(fullPath, subMap)
+			} else {
+				unknown[fullPath] = value
 			}
 		}
 	}
 	apply("", fileConfig)
 
-	// 3. Atomically Update Config (Write-Lock)
+	return collected, unknown
+}
+
+// registerUnknown registers unknown file keys as new paths when AutoRegisterUnknown is set.
+// Each leaf is registered with its file value as default and added to collected.
+// Leaves with invalid segments or colliding with registered paths stay in unknown.
+// Nothing is registered on a frozen config, or in strict mode when a key stays
+// unknown, since checkStrict then rejects the data.
+func (c *Config) registerUnknown(unknown, collected map[string]any) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.options.AutoRegisterUnknown || c.checkWritable() != nil {
+		return
+	}
+
+	pending := make(map[string]any)
+	for path, value := range unknown {
+		leaves := map[string]any{path: value}
+		if section, isMap := value.(map[string]any); isMap {
+			leaves = flattenMap(section, path)
+		}
+
+		registeredAll := true
+		for leaf, leafValue := range leaves {
+			if !c.canAutoRegister(leaf) {
+				registeredAll = false
+				continue
+			}
+			pending[leaf] = leafValue
+		}
+		if registeredAll {
+			delete(unknown, path)
+		}
+	}
+	if len(pending) == 0 || (c.options.Strict && len(unknown) > 0) {
+		return
+	}
+
+	for leaf, value := range pending {
+		c.storeItem(leaf, configItem{
+			defaultValue: value,
+			currentValue: value,
+			values:       make(map[Source]any),
+		})
+		collected[leaf] = value
+	}
+
+	c.invalidateCache()
+}
+
+// canAutoRegister reports whether path is a valid new leaf that does not collide
+// with a registered path, as a parent section or a child of a registered value.
+// Must be called with the lock held.
+func (c *Config) canAutoRegister(path string) bool {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		if !isValidKeySegment(segment) {
+			return false
+		}
+		if _, registered := c.items[strings.Join(segments[:i+1], ".")]; registered {
+			return false
+		}
+	}
+	return !c.hasChildren(path)
+}
+
+// applyFileConfig replaces the File source with the parsed data overlaid on the file base.
+// If filePath is non-empty, it is recorded as the tracked config file.
+// In strict mode, unknown keys reject the data and leave the File source untouched.
+func (c *Config) applyFileConfig(fileConfig map[string]any, filePath string, report *LoadReport) error {
+	// 1. Prepare New State (Read-Lock Only)
+	loaded, unknown := c.collectRegistered(fileConfig)
+	c.registerUnknown(unknown, loaded)
+	if err := c.checkStrict(unknown, filePath); err != nil {
+		return err
+	}
+
+	// 2. Atomically Update Config (Write-Lock)
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.configFilePath = path
+	newFileData := make(map[string]any, len(c.fileBase)+len(loaded))
+	for path, value := range c.fileBase {
+		newFileData[path] = value
+	}
+	for path, value := range loaded {
+		newFileData[path] = value
+	}
+
+	if filePath != "" {
+		c.configFilePath = filePath
+	}
 	c.fileData = newFileData
+	if report != nil {
+		report.Counts[SourceFile] = len(loaded)
+	}
 
 	// Apply the new state to the main config items.
+	now := time.Now()
 	for path, item := range c.items {
 		if value, exists := newFileData[path]; exists {
-			if item.values == nil {
-				item.values = make(map[Source]any)
-			}
-			item.values[SourceFile] = value
+			item.setSource(SourceFile, value, now)
 		} else {
 			// Key was not in the new file, so remove its old file-sourced value.
-			delete(item.values, SourceFile)
+			item.unsetSource(SourceFile)
 		}
 		// Recompute the current value based on new source precedence.
 		item.currentValue = c.computeValue(item)
-		c.items[path] = item
+		c.storeItem(path, item)
 	}
 
 	c.invalidateCache()
 	return nil
 }
 
-// loadEnv loads configuration from environment variables
-func (c *Config) loadEnv(opts LoadOptions) error {
-	transform := opts.EnvTransform
-	if transform == nil {
-		transform = defaultEnvTransform(opts.EnvPrefix)
+// checkStrict returns a StrictError if strict mode is enabled and unknown keys were found
+func (c *Config) checkStrict(unknown map[string]any, origin string) error {
+	c.mutex.RLock()
+	strict := c.options.Strict
+	c.mutex.RUnlock()
+
+	if len(unknown) == 0 {
+		return nil
+	}
+	if origin == "" {
+		origin = "config data"
 	}
 
-	// -- 1. Prepare data (Read-Lock to get paths)
-	c.mutex.RLock()
-	paths := make([]string, 0, len(c.items))
-	for p := range c.items {
-		paths = append(paths, p)
+	keys := make([]string, 0, len(unknown))
+	for key := range unknown {
+		keys = append(keys, key)
 	}
-	c.mutex.RUnlock()
+	sort.Strings(keys)
+	if !strict {
+		c.logger().Warnf("config: ignoring unknown keys in %s: %s", origin, strings.Join(keys, ", "))
+		return nil
+	}
+	return &StrictError{Origin: origin, Keys: keys}
+}
 
-	// -- 2. Process env vars (No Lock)
-	foundEnvVars := make(map[string]string)
-	for _, path := range paths {
-		if opts.EnvWhitelist != nil && !opts.EnvWhitelist[path] {
-			continue
-		}
+// loadEnv loads configuration from environment variables
+func (c *Config) loadEnv(opts LoadOptions, report *LoadReport) error {
+	if err := c.checkLoadable(); err != nil {
+		return err
+	}
 
-		envVar := transform(path)
-		if value, exists := os.LookupEnv(envVar); exists {
-			if len(value) > MaxValueSize {
-				return ErrValueSize
-			}
-			foundEnvVars[path] = value
-		}
+	foundEnvVars, envVars, err := c.readEnv(opts)
+	if err != nil {
+		return err
 	}
 
 	// If no relevant env vars were found, we are done.
@@ -338,33 +902,153 @@ func (c *Config) loadEnv(opts LoadOptions) error {
 		return nil
 	}
 
+	// Strings are stored as-is and converted when read, unless coercion is enabled
+	values := c.envStoredValues(foundEnvVars, opts)
+
 	// -- 3. Atomically update config (Write-Lock)
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.envData = make(map[string]any, len(foundEnvVars))
+	applied := c.applyEnvValues(values, time.Now())
+	if report != nil {
+		report.Counts[SourceEnv] += len(applied)
+		for _, path := range applied {
+			report.EnvVars = append(report.EnvVars, envVars[path])
+		}
+		sort.Strings(report.EnvVars)
+	}
 
-	for path, value := range foundEnvVars {
-		// Store raw string value - mapstructure will handle conversion later.
+	c.invalidateCache()
+	return nil
+}
+
+// applyEnvValues stores values as the env source of their registered paths, replacing
+// the env source cache, and returns the paths stored. Must be called with the write lock held.
+func (c *Config) applyEnvValues(values map[string]any, now time.Time) []string {
+	c.envData = make(map[string]any, len(values))
+	var applied []string
+	for path, stored := range values {
+		item, exists := c.items[path]
+		if !exists {
+			continue
+		}
+		item.setSource(SourceEnv, stored, now)
+		item.currentValue = c.computeValue(item)
+		c.storeItem(path, item)
+		c.envData[path] = stored
+		applied = append(applied, path)
+	}
+	return applied
+}
+
+// envStoredValues returns env values in the form the env source stores them: strings,
+// or the defaults' types when opts.CoerceToDefaultType is set
+func (c *Config) envStoredValues(found map[string]string, opts LoadOptions) map[string]any {
+	values := make(map[string]any, len(found))
+	for path, value := range found {
+		values[path] = value
+	}
+	if opts.CoerceToDefaultType {
+		values = c.coerceToDefaults(values)
+	}
+	return values
+}
+
+// coerceToDefaults returns values with each string converted to the type of its path's
+// default, in the canonical stored form, keeping strings whose path is unregistered, whose
+// default is nil, or whose conversion fails. Conversions run without the lock held, so
+// decode hooks may read the config.
+func (c *Config) coerceToDefaults(values map[string]any) map[string]any {
+	c.mutex.RLock()
+	defaults := make(map[string]any, len(values))
+	for path := range values {
 		if item, exists := c.items[path]; exists {
-			if item.values == nil {
-				item.values = make(map[Source]any)
+			defaults[path] = item.defaultValue
+		}
+	}
+	decodeHook := c.getDecodeHook()
+	tagName := c.tagName
+	c.mutex.RUnlock()
+
+	coerced := make(map[string]any, len(values))
+	for path, value := range values {
+		coerced[path] = value
+		def := defaults[path]
+		if _, isString := value.(string); !isString || def == nil {
+			continue
+		}
+		if converted, err := decodeAs(value, reflect.TypeOf(def), tagName, decodeHook); err == nil {
+			coerced[path] = normalizeNumber(converted)
+		}
+	}
+	return coerced
+}
+
+// readEnv looks up the env vars for all registered paths under opts. It returns the
+// values found and the variable each was read from, both keyed by path.
+func (c *Config) readEnv(opts LoadOptions) (map[string]string, map[string]string, error) {
+	transform := opts.EnvTransform
+	if transform == nil {
+		transform = defaultEnvTransform(opts.EnvPrefix, opts.EnvSeparator)
+	}
+
+	// -- 1. Prepare data (Read-Lock to get paths and their env var names)
+	c.mutex.RLock()
+	candidates := make(map[string][]string, len(c.items))
+	for p := range c.items {
+		candidates[p] = c.envVarCandidates(p, transform)
+	}
+	maxValueSize := c.maxValueSize
+	c.mutex.RUnlock()
+
+	// -- 2. Process env vars (No Lock); the first candidate that is set wins
+	foundEnvVars := make(map[string]string)
+	envVars := make(map[string]string)
+	for path, names := range candidates {
+		if opts.EnvWhitelist != nil && !opts.EnvWhitelist[path] {
+			continue
+		}
+
+		for _, envVar := range names {
+			value, exists := os.LookupEnv(envVar)
+			if !exists {
+				continue
 			}
-			item.values[SourceEnv] = value // Store as string
-			item.currentValue = c.computeValue(item)
-			c.items[path] = item
-			c.envData[path] = value
+			if maxValueSize > 0 && int64(len(value)) > maxValueSize {
+				return nil, nil, ErrValueSize
+			}
+			foundEnvVars[path] = normalizeEnvValue(value, opts.TrimEnvWhitespace)
+			envVars[path] = envVar
+			break
 		}
 	}
 
-	c.invalidateCache()
-	return nil
+	return foundEnvVars, envVars, nil
 }
 
 // loadCLI loads configuration from command-line arguments
-func (c *Config) loadCLI(args []string) error {
-	// -- 1. Prepare data (No Lock)
-	parsedCLI, err := parseArgs(args)
+func (c *Config) loadCLI(args []string, report *LoadReport) error {
+	if err := c.checkLoadable(); err != nil {
+		return err
+	}
+
+	// -- 1. Prepare data
+	// Known names drive short clusters (-vq) and negation (--no-debug)
+	c.mutex.RLock()
+	names := make(map[string]bool, len(c.items)+len(c.aliases))
+	for path, item := range c.items {
+		_, isBool := item.defaultValue.(bool)
+		names[path] = isBool
+	}
+	for alias, path := range c.aliases {
+		_, isBool := c.items[path].defaultValue.(bool)
+		names[alias] = isBool
+	}
+	aliases := maps.Clone(c.aliases)
+	coerce := c.options.CoerceToDefaultType
+	c.mutex.RUnlock()
+
+	parsedCLI, err := parseArgs(args, names)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrCLIParse, err)
 	}
@@ -374,20 +1058,45 @@ func (c *Config) loadCLI(args []string) error {
 		return nil // No CLI args to process.
 	}
 
+	// Resolve aliases such as --p to their target paths
+	for alias, path := range aliases {
+		if value, exists := flattenedCLI[alias]; exists {
+			delete(flattenedCLI, alias)
+			if _, set := flattenedCLI[path]; !set {
+				flattenedCLI[path] = value // Canonical name wins when both are given
+			}
+		}
+	}
+	if coerce {
+		flattenedCLI = c.coerceToDefaults(flattenedCLI)
+	}
+
 	// 2. Atomically update config (Write-Lock)
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.cliData = flattenedCLI
+	// Filled separately: element overrides write their base path into cliData while looping
+	c.cliData = make(map[string]any, len(flattenedCLI))
+	now := time.Now()
 
 	for path, value := range flattenedCLI {
 		if item, exists := c.items[path]; exists {
-			if item.values == nil {
-				item.values = make(map[Source]any)
-			}
-			item.values[SourceCLI] = value
+			item.setSource(SourceCLI, value, now)
 			item.currentValue = c.computeValue(item)
-			c.items[path] = item
+			c.storeItem(path, item)
+			c.cliData[path] = value
+		} else if base, rest, ok := c.resolveIndexedPath(path); ok {
+			// Element override such as --server.hosts[1]=x
+			if err := c.setIndexedSource(SourceCLI, base, rest, value); err != nil {
+				return fmt.Errorf("%w: %w", ErrCLIParse, err)
+			}
+		} else {
+			// Unregistered flags are kept in the CLI cache
+			c.cliData[path] = value
+			continue
+		}
+		if report != nil {
+			report.Counts[SourceCLI]++
 		}
 	}
 
@@ -400,7 +1109,7 @@ func (c *Config) loadCLI(args []string) error {
 func (c *Config) DiscoverEnv(prefix string) map[string]string {
 	transform := c.options.EnvTransform
 	if transform == nil {
-		transform = defaultEnvTransform(prefix)
+		transform = defaultEnvTransform(prefix, c.options.EnvSeparator)
 	}
 
 	c.mutex.RLock()
@@ -409,7 +1118,7 @@ func (c *Config) DiscoverEnv(prefix string) map[string]string {
 	discovered := make(map[string]string)
 
 	for path := range c.items {
-		envVar := transform(path)
+		envVar := c.envVarName(path, transform)
 		if _, exists := os.LookupEnv(envVar); exists {
 			discovered[path] = envVar
 		}
@@ -418,12 +1127,37 @@ func (c *Config) DiscoverEnv(prefix string) map[string]string {
 	return discovered
 }
 
+// EnvVarPath maps an environment variable name back to the registered path it sets
+// under the current load options, the reverse of the env transform. It reports false
+// if no path uses the name or if several do, as "server.max_conns" and
+// "server_max.conns" both do with the default "_" separator; set EnvSeparator to "__"
+// to keep such names distinct.
+func (c *Config) EnvVarPath(envVar string) (string, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	transform := c.options.EnvTransform
+	if transform == nil {
+		transform = defaultEnvTransform(c.options.EnvPrefix, c.options.EnvSeparator)
+	}
+
+	var found string
+	matches := 0
+	for path := range c.items {
+		if slices.Contains(c.envVarCandidates(path, transform), envVar) {
+			found = path
+			matches++
+		}
+	}
+	return found, matches == 1
+}
+
 // ExportEnv exports the current configuration as environment variables
 // Only exports paths that have non-default values
 func (c *Config) ExportEnv(prefix string) map[string]string {
 	transform := c.options.EnvTransform
 	if transform == nil {
-		transform = defaultEnvTransform(prefix)
+		transform = defaultEnvTransform(prefix, c.options.EnvSeparator)
 	}
 
 	c.mutex.RLock()
@@ -433,7 +1167,7 @@ func (c *Config) ExportEnv(prefix string) map[string]string {
 
 	for path, item := range c.items {
 		// Only export if value differs from default
-		if item.currentValue != item.defaultValue {
+		if isModified(item) {
 			envVar := transform(path)
 			exports[envVar] = fmt.Sprintf("%v", item.currentValue)
 		}
@@ -442,10 +1176,106 @@ func (c *Config) ExportEnv(prefix string) map[string]string {
 	return exports
 }
 
-// defaultEnvTransform creates the default environment variable transformer
-func defaultEnvTransform(prefix string) EnvTransformFunc {
+// EnvDoc describes one environment variable read by the config
+type EnvDoc struct {
+	EnvVar    string // Variable name, from the env tag or the transform
+	Path      string // Registered config path
+	Type      string // Go type of the default, or "any" for a nil default
+	Default   string // Default in env form; empty for sensitive paths
+	Usage     string // Description from the usage struct tag
+	Required  bool   // Registered with RegisterRequired or the required tag
+	Sensitive bool   // Marked with the sensitive struct tag
+}
+
+// DocumentEnv returns a description of every environment variable the config
+// reads, sorted by path. Names follow the same rules as loading: recorded names
+// (env tags, RegisterWithEnv, auto env) first, then the transformed prefix name.
+func (c *Config) DocumentEnv(prefix string) []EnvDoc {
+	transform := c.options.EnvTransform
+	if transform == nil {
+		transform = defaultEnvTransform(prefix, c.options.EnvSeparator)
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	docs := make([]EnvDoc, 0, len(c.items))
+	for path, item := range c.items {
+		doc := EnvDoc{
+			EnvVar:    c.envVarCandidates(path, transform)[0],
+			Path:      path,
+			Type:      "any",
+			Usage:     item.usage,
+			Required:  item.required,
+			Sensitive: item.sensitive,
+		}
+		if item.defaultValue != nil {
+			doc.Type = fmt.Sprintf("%T", item.defaultValue)
+		}
+		if !item.sensitive {
+			doc.Default = textDefault(item.defaultValue)
+		}
+		docs = append(docs, doc)
+	}
+
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Path < docs[j].Path })
+	return docs
+}
+
+// EnvMapping returns the recorded path to environment variable names,
+// from explicit env tags, RegisterWithEnv, and Builder.WithAutoEnv
+func (c *Config) EnvMapping() map[string]string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	mapping := make(map[string]string, len(c.envNames))
+	for path, envVar := range c.envNames {
+		mapping[path] = envVar
+	}
+	return mapping
+}
+
+// recordEnvNames records the env var name for every registered path that has no recorded name yet
+func (c *Config) recordEnvNames(transform EnvTransformFunc) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for path := range c.items {
+		if _, recorded := c.envNames[path]; !recorded {
+			c.envNames[path] = transform(path)
+		}
+	}
+}
+
+// envVarName returns the recorded env var name for a path, falling back to the transform.
+// Must be called with the lock held.
+func (c *Config) envVarName(path string, transform EnvTransformFunc) string {
+	if envVar, recorded := c.envNames[path]; recorded {
+		return envVar
+	}
+	return transform(path)
+}
+
+// envVarCandidates returns the env var names consulted for a path, in priority order:
+// the recorded name (explicit env tag, RegisterWithEnv, or auto env) first, then the
+// transformed name if it differs. An explicit name therefore wins when both are set.
+// Must be called with the lock held.
+func (c *Config) envVarCandidates(path string, transform EnvTransformFunc) []string {
+	transformed := transform(path)
+	if envVar, recorded := c.envNames[path]; recorded && envVar != transformed {
+		return []string{envVar, transformed}
+	}
+	return []string{transformed}
+}
+
+// defaultEnvTransform creates the default environment variable transformer,
+// joining segments with separator ("_" if empty)
+func defaultEnvTransform(prefix, separator string) EnvTransformFunc {
+	if separator == "" {
+		separator = "_"
+	}
 	return func(path string) string {
-		env := strings.ReplaceAll(path, ".", "_")
+		env := strings.ReplaceAll(path, ".", separator)
 		env = strings.ToUpper(env)
 		if prefix != "" {
 			env = prefix + env
@@ -454,7 +1284,33 @@ func defaultEnvTransform(prefix string) EnvTransformFunc {
 	}
 }
 
-// parseValue attempts to parse a string into appropriate types
+// normalizeEnvValue is the normalization applied to every env value read: surrounding
+// whitespace is removed if trimSpace is set, then one pair of matching double or single
+// quotes around the value is stripped. Text inside the quotes is kept as is, so quotes
+// preserve intended leading or trailing spaces.
+func normalizeEnvValue(s string, trimSpace bool) string {
+	if trimSpace {
+		s = strings.TrimSpace(s)
+	}
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// lookupEnv reads an env var, normalized with the current load options
+func (c *Config) lookupEnv(name string) (string, bool) {
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		return "", false
+	}
+	c.mutex.RLock()
+	trimSpace := c.options.TrimEnvWhitespace
+	c.mutex.RUnlock()
+	return normalizeEnvValue(value, trimSpace), true
+}
+
+// parseValue attempts to parse a normalized env value into appropriate types
 // Only basic parse, complex parsing is deferred to mapstructure's decode hooks
 func parseValue(s string) any {
 	if s == "true" {
@@ -464,18 +1320,54 @@ func parseValue(s string) any {
 		return false
 	}
 
-	// Remove quotes if present
-	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
-		return s[1 : len(s)-1]
-	}
-
 	// Return as string - mapstructure will convert as needed
 	return s
 }
 
+// SaveOptions controls how Save writes configuration files
+type SaveOptions struct {
+	FileMode os.FileMode // Permissions of the saved file (0 = 0644)
+	DirMode  os.FileMode // Permissions of parent directories created by the save (0 = 0755)
+
+	// Backup keeps the file being replaced. Numbered backups rotate as path.bak,
+	// path.bak.1, ...; timestamped backups are named path.<timestamp>.bak.
+	Backup          bool
+	BackupSuffix    string // Backup name suffix (default ".bak")
+	BackupTimestamp bool   // Name backups by time instead of rotating numbered copies
+	BackupKeep      int    // Number of backups to keep (0 = 1)
+}
+
+// backupTimeFormat is fixed-width so timestamped backup names sort chronologically
+const backupTimeFormat = "20060102T150405.000000000"
+
+// DefaultSaveOptions returns the options used by Save and SaveSource
+func DefaultSaveOptions() SaveOptions {
+	return SaveOptions{
+		FileMode: 0644,
+		DirMode:  0755,
+	}
+}
+
 // Save writes the current configuration to a TOML file atomically.
 // Only registered paths are saved.
 func (c *Config) Save(path string) error {
+	return c.SaveWithOptions(path, DefaultSaveOptions())
+}
+
+// SaveWithOptions writes the current configuration to a TOML file atomically,
+// applying the file and directory permissions from opts. Use FileMode 0600 for
+// files that hold secrets.
+func (c *Config) SaveWithOptions(path string, opts SaveOptions) error {
+	data, err := c.SavePreview()
+	if err != nil {
+		return err
+	}
+	return atomicWriteFile(path, data, opts)
+}
+
+// SavePreview returns the TOML that Save would write, without touching the filesystem
+func (c *Config) SavePreview() ([]byte, error) {
+	c.resolveLazyDefaults()
 	c.mutex.RLock()
 
 	nestedData := make(map[string]any)
@@ -489,65 +1381,28 @@ func (c *Config) Save(path string) error {
 	var buf bytes.Buffer
 	encoder := toml.NewEncoder(&buf)
 	if err := encoder.Encode(nestedData); err != nil {
-		return fmt.Errorf("failed to marshal config data to TOML: %w", err)
-	}
-	tomlData := buf.Bytes()
-
-	// Atomic write logic
-	dir := filepath.Dir(path)
-	// Ensure the directory exists
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory '%s': %w", dir, err)
+		return nil, fmt.Errorf("failed to marshal config data to TOML: %w", err)
 	}
+	return buf.Bytes(), nil
+}
 
-	// Create a temporary file in the same directory
-	tempFile, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+// SaveSource writes values from a specific source to a TOML file
+func (c *Config) SaveSource(path string, source Source) error {
+	data, err := c.SaveSourcePreview(source)
 	if err != nil {
-		return fmt.Errorf("failed to create temporary config file in '%s': %w", dir, err)
+		return err
 	}
-
-	tempFilePath := tempFile.Name()
-	removed := false
-	defer func() {
-		if !removed {
-			os.Remove(tempFilePath)
-		}
-	}()
-
-	// Write data to the temporary file
-	if _, err := tempFile.Write(tomlData); err != nil {
-		tempFile.Close()
-		return fmt.Errorf("failed to write temp config file '%s': %w", tempFilePath, err)
-	}
-
-	// Sync data to disk
-	if err := tempFile.Sync(); err != nil {
-		tempFile.Close()
-		return fmt.Errorf("failed to sync temp config file '%s': %w", tempFilePath, err)
-	}
-
-	// Close the temporary file
-	if err := tempFile.Close(); err != nil {
-		return fmt.Errorf("failed to close temp config file '%s': %w", tempFilePath, err)
-	}
-
-	// Set permissions on the temporary file
-	if err := os.Chmod(tempFilePath, 0644); err != nil {
-		return fmt.Errorf("failed to set permissions on temporary config file '%s': %w", tempFilePath, err)
-	}
-
-	// Atomically replace the original file
-	if err := os.Rename(tempFilePath, path); err != nil {
-		return fmt.Errorf("failed to rename temp file '%s' to '%s': %w", tempFilePath, path, err)
-	}
-	removed = true
-
-	return nil
+	return atomicWriteFile(path, data, DefaultSaveOptions())
 }
 
-// SaveSource writes values from a specific source to a TOML file
-func (c *Config) SaveSource(path string, source Source) error {
+// SaveSourcePreview returns the TOML that SaveSource would write for source,
+// without touching the filesystem
+func (c *Config) SaveSourcePreview(source Source) ([]byte, error) {
 	c.mutex.RLock()
+	if err := c.validateSource(source); err != nil {
+		c.mutex.RUnlock()
+		return nil, err
+	}
 
 	nestedData := make(map[string]any)
 	for itemPath, item := range c.items {
@@ -562,16 +1417,24 @@ func (c *Config) SaveSource(path string, source Source) error {
 	var buf bytes.Buffer
 	encoder := toml.NewEncoder(&buf)
 	if err := encoder.Encode(nestedData); err != nil {
-		return fmt.Errorf("failed to marshal %s source data to TOML: %w", source, err)
+		return nil, fmt.Errorf("failed to marshal %s source data to TOML: %w", source, err)
 	}
-
-	return atomicWriteFile(path, buf.Bytes())
+	return buf.Bytes(), nil
 }
 
-// atomicWriteFile performs atomic file write
-func atomicWriteFile(path string, data []byte) error {
+// atomicWriteFile writes data to a temporary file in the target directory, applies
+// the requested permissions, and renames it over path
+func atomicWriteFile(path string, data []byte, opts SaveOptions) error {
+	fileMode, dirMode := opts.FileMode, opts.DirMode
+	if fileMode == 0 {
+		fileMode = 0644
+	}
+	if dirMode == 0 {
+		dirMode = 0755
+	}
+
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := os.MkdirAll(dir, dirMode); err != nil {
 		return fmt.Errorf("failed to create directory '%s': %w", dir, err)
 	}
 
@@ -597,36 +1460,192 @@ func atomicWriteFile(path string, data []byte) error {
 		return fmt.Errorf("failed to close temporary file: %w", err)
 	}
 
-	if err := os.Chmod(tempPath, 0644); err != nil {
+	if err := os.Chmod(tempPath, fileMode); err != nil {
 		return fmt.Errorf("failed to set permissions: %w", err)
 	}
 
+	// The target stays in place until the rename, so a crash never leaves it missing
+	if opts.Backup {
+		if err := backupFile(path, opts); err != nil {
+			return fmt.Errorf("failed to back up '%s': %w", path, err)
+		}
+	}
+
 	if err := os.Rename(tempPath, path); err != nil {
 		return fmt.Errorf("failed to rename temporary file: %w", err)
 	}
 
+	// Make the rename itself durable
+	if err := syncDir(dir); err != nil {
+		return fmt.Errorf("failed to sync directory '%s': %w", dir, err)
+	}
+
+	return nil
+}
+
+// backupFile preserves the current contents of path under its backup name,
+// rotating or pruning older backups to honor BackupKeep. No-op if path does not exist.
+func backupFile(path string, opts SaveOptions) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	suffix := opts.BackupSuffix
+	if suffix == "" {
+		suffix = ".bak"
+	}
+	keep := max(opts.BackupKeep, 1)
+
+	if opts.BackupTimestamp {
+		backup := path + "." + time.Now().Format(backupTimeFormat) + suffix
+		if err := linkOrCopy(path, backup); err != nil {
+			return err
+		}
+		return pruneTimestampedBackups(path, suffix, keep)
+	}
+
+	// Shift path.bak.N-1 -> path.bak.N, ..., path.bak -> path.bak.1
+	base := path + suffix
+	numbered := func(i int) string {
+		if i == 0 {
+			return base
+		}
+		return fmt.Sprintf("%s.%d", base, i)
+	}
+	if err := os.Remove(numbered(keep - 1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := keep - 2; i >= 0; i-- {
+		if err := os.Rename(numbered(i), numbered(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return linkOrCopy(path, base)
+}
+
+// pruneTimestampedBackups removes all but the newest keep timestamped backups of path
+func pruneTimestampedBackups(path, suffix string, keep int) error {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	prefix := name + "."
+	var backups []string
+	for _, entry := range entries {
+		n := entry.Name()
+		if strings.HasPrefix(n, prefix) && strings.HasSuffix(n, suffix) &&
+			len(n) == len(prefix)+len(backupTimeFormat)+len(suffix) {
+			backups = append(backups, n)
+		}
+	}
+	sort.Strings(backups)
+
+	for len(backups) > keep {
+		if err := os.Remove(filepath.Join(dir, backups[0])); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// linkOrCopy makes dst a hard link to src, copying the contents and mode if
+// the filesystem does not support links
+func linkOrCopy(src, dst string) error {
+	if err := os.Remove(dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chmod(dst, info.Mode().Perm())
+}
+
+// syncDir fsyncs a directory so a rename within it survives a crash.
+// Skipped on Windows and on filesystems that do not support directory sync.
+// A variable so tests can observe calls.
+var syncDir = func(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTSUP) {
+		return err
+	}
 	return nil
 }
 
 // parseArgs processes command-line arguments into a nested map structure.
-func parseArgs(args []string) (map[string]any, error) {
+// Both --key and -key forms are accepted; later occurrences override earlier ones.
+// names maps known paths and aliases to whether they are boolean. A single-dash
+// cluster such as -vq expands to booleans when every letter is known, and
+// --no-<name> sets a known boolean to false.
+func parseArgs(args []string, names map[string]bool) (map[string]any, error) {
 	result := make(map[string]any)
 	i := 0
 	for i < len(args) {
 		arg := args[i]
-		if !strings.HasPrefix(arg, "--") {
+		if !isFlagArg(arg) {
 			// Skip non-flag arguments
 			i++
 			continue
 		}
 
-		argContent := strings.TrimPrefix(arg, "--")
+		singleDash := !strings.HasPrefix(arg, "--")
+		argContent := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
 		if argContent == "" {
 			// Skip "--" argument if used as a separator
 			i++
 			continue
 		}
 
+		// Short boolean cluster such as -vq, when every letter is a known name
+		if _, known := names[argContent]; singleDash && len(argContent) > 1 && !known && isShortCluster(argContent, names) {
+			for _, r := range argContent {
+				if !names[string(r)] {
+					return nil, fmt.Errorf("non-boolean flag %q in short flag cluster %q", string(r), arg)
+				}
+			}
+			for _, r := range argContent {
+				result[string(r)] = "true"
+			}
+			i++
+			continue
+		}
+
+		// Negated boolean such as --no-debug
+		if target, ok := negatedName(argContent, names); ok {
+			if !names[target] {
+				return nil, fmt.Errorf("cannot negate non-boolean path %q", target)
+			}
+			setNestedValue(result, target, "false")
+			i++
+			continue
+		}
+
 		var keyPath string
 		var valueStr string
 
@@ -640,7 +1659,7 @@ func parseArgs(args []string) (map[string]any, error) {
 			// Handle "--key value" or "--booleanflag"
 			keyPath = argContent
 			// Check if it's a boolean flag (next arg is another flag or end of args)
-			if i+1 >= len(args) || strings.HasPrefix(args[i+1], "--") {
+			if i+1 >= len(args) || isFlagArg(args[i+1]) {
 				valueStr = "true"
 				i++ // Consume only the flag argument
 			} else {
@@ -656,9 +1675,10 @@ func parseArgs(args []string) (map[string]any, error) {
 		}
 
 		// Validate keyPath segments
+		// Segments may carry [n] suffixes to address slice elements
 		segments := strings.Split(keyPath, ".")
 		for _, segment := range segments {
-			if !isValidKeySegment(segment) {
+			if !isValidIndexedSegment(segment) {
 				return nil, fmt.Errorf("invalid command-line key segment %q in path %q", segment, keyPath)
 			}
 		}
@@ -670,6 +1690,44 @@ func parseArgs(args []string) (map[string]any, error) {
 	return result, nil
 }
 
+// isFlagArg reports whether arg is a flag rather than a value.
+// Negative numbers and a lone "-" are treated as values.
+func isFlagArg(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	if _, err := strconv.ParseFloat(arg, 64); err == nil {
+		return false
+	}
+	return true
+}
+
+// isShortCluster reports whether every character of s is a known single-letter name
+func isShortCluster(s string, names map[string]bool) bool {
+	for _, r := range s {
+		if _, known := names[string(r)]; !known {
+			return false
+		}
+	}
+	return true
+}
+
+// negatedName returns the target of a --no-<name> flag when name is known
+// and the full flag is not itself a known name
+func negatedName(flag string, names map[string]bool) (string, bool) {
+	target, found := strings.CutPrefix(flag, "no-")
+	if !found || strings.Contains(flag, "=") {
+		return "", false
+	}
+	if _, known := names[flag]; known {
+		return "", false
+	}
+	if _, known := names[target]; !known {
+		return "", false
+	}
+	return target, true
+}
+
 // detectFileFormat determines format from file extension
 func detectFileFormat(path string) string {
 	ext := strings.ToLower(filepath.Ext(path))
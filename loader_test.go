@@ -2,9 +2,14 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -103,6 +108,159 @@ unregistered = "ignored"
 	})
 }
 
+// TestStrictLoading tests rejection of unknown keys in strict mode
+func TestStrictLoading(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "typo.toml")
+	os.WriteFile(configFile, []byte(`
+[server]
+host = "example.com"
+prot = 9090
+
+[plugins.cache]
+size = 10
+`), 0644)
+
+	type AppConfig struct {
+		Server struct {
+			Host string `toml:"host"`
+			Port int64  `toml:"port"`
+		} `toml:"server"`
+	}
+
+	t.Run("Lenient", func(t *testing.T) {
+		cfg, err := NewBuilder().
+			WithDefaults(&AppConfig{}).
+			WithFile(configFile).
+			WithArgs([]string{}).
+			Build()
+		require.NoError(t, err)
+
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "example.com", host)
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		_, err := NewBuilder().
+			WithDefaults(&AppConfig{}).
+			WithFile(configFile).
+			WithArgs([]string{}).
+			WithStrict().
+			Build()
+		require.Error(t, err)
+
+		var strictErr *StrictError
+		require.ErrorAs(t, err, &strictErr)
+		assert.Equal(t, []string{"plugins", "server.prot"}, strictErr.Keys)
+		assert.Contains(t, err.Error(), "server.prot")
+	})
+
+	t.Run("StrictValidFile", func(t *testing.T) {
+		validFile := filepath.Join(tmpDir, "valid.toml")
+		os.WriteFile(validFile, []byte("[server]\nport = 9090\n"), 0644)
+
+		cfg, err := NewBuilder().
+			WithDefaults(&AppConfig{}).
+			WithFile(validFile).
+			WithArgs([]string{}).
+			WithStrict().
+			Build()
+		require.NoError(t, err)
+
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(9090), port)
+	})
+}
+
+// TestAutoRegisterUnknown tests registration of unknown file keys
+func TestAutoRegisterUnknown(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "plugins.toml")
+	os.WriteFile(configFile, []byte(`
+[server]
+port = 9090
+host = "filehost"
+
+[plugins.foo]
+enabled = true
+rate = 5
+
+[plugins.bar]
+name = "bar"
+`), 0644)
+
+	type AppConfig struct {
+		Server struct {
+			Port int `toml:"port"`
+			Host struct {
+				Name string `toml:"name"`
+			} `toml:"host"`
+		} `toml:"server"`
+	}
+
+	cfg, err := NewBuilder().
+		WithDefaults(&AppConfig{}).
+		WithFile(configFile).
+		WithArgs([]string{}).
+		WithAutoRegister().
+		Build()
+	require.NoError(t, err)
+
+	paths := cfg.GetRegisteredPaths("plugins.")
+	assert.True(t, paths["plugins.foo.enabled"])
+	assert.True(t, paths["plugins.foo.rate"])
+	assert.True(t, paths["plugins.bar.name"])
+
+	enabled, _ := cfg.Get("plugins.foo.enabled")
+	assert.Equal(t, true, enabled)
+	rate, _ := cfg.GetSource("plugins.foo.rate", SourceFile)
+	assert.Equal(t, int64(5), rate)
+
+	var plugin struct {
+		Enabled bool `toml:"enabled"`
+		Rate    int  `toml:"rate"`
+	}
+	require.NoError(t, cfg.Scan(&plugin, "plugins.foo"))
+	assert.True(t, plugin.Enabled)
+	assert.Equal(t, 5, plugin.Rate)
+
+	// A leaf colliding with a registered section is not registered
+	_, exists := cfg.Get("server.host")
+	assert.False(t, exists)
+	name, _ := cfg.Get("server.host.name")
+	assert.Equal(t, "", name)
+
+	// Registered keys are no longer unknown in strict mode
+	cfg2 := New()
+	cfg2.Register("server.port", 0)
+	cfg2.Register("server.host.name", "")
+	cfg2.SetLoadOptions(LoadOptions{
+		Sources:             []Source{SourceFile, SourceDefault},
+		Strict:              true,
+		AutoRegisterUnknown: true,
+	})
+	err = cfg2.LoadFile(configFile)
+	var strictErr *StrictError
+	require.ErrorAs(t, err, &strictErr)
+	assert.Equal(t, []string{"server.host"}, strictErr.Keys)
+
+	// A strict failure registers none of the other unknown keys
+	assert.Empty(t, cfg2.GetRegisteredPaths("plugins."))
+
+	// A frozen config does not grow new paths on reload
+	cfg3 := New()
+	cfg3.Register("server.port", 0)
+	cfg3.SetLoadOptions(LoadOptions{
+		Sources:             []Source{SourceFile, SourceDefault},
+		AutoRegisterUnknown: true,
+	})
+	cfg3.FreezeWithOptions(FreezeOptions{AllowReload: true})
+	require.NoError(t, cfg3.LoadFile(configFile))
+	assert.Empty(t, cfg3.GetRegisteredPaths("plugins."))
+	port, _ := cfg3.Get("server.port")
+	assert.Equal(t, int64(9090), port)
+}
+
 // TestEnvironmentLoading tests environment variable loading
 func TestEnvironmentLoading(t *testing.T) {
 	// Save and restore environment
@@ -163,6 +321,44 @@ func TestEnvironmentLoading(t *testing.T) {
 		assert.Equal(t, "customhost", host)
 	})
 
+	t.Run("ExplicitTagWinsOverPrefix", func(t *testing.T) {
+		type Settings struct {
+			APIKey  string `toml:"api_key" env:"CUSTOM_API_KEY"`
+			Region  string `toml:"region" env:"CUSTOM_REGION"`
+			Timeout string `toml:"timeout"`
+		}
+
+		cfg := New()
+		require.NoError(t, cfg.RegisterStruct("", Settings{}))
+
+		os.Setenv("CUSTOM_API_KEY", "explicit-key")
+		os.Setenv("APP_API_KEY", "prefixed-key")
+		os.Setenv("APP_REGION", "prefixed-region") // CUSTOM_REGION unset: fall back to the prefix
+		os.Setenv("APP_TIMEOUT", "5s")
+		defer func() {
+			for _, name := range []string{"CUSTOM_API_KEY", "APP_API_KEY", "APP_REGION", "APP_TIMEOUT"} {
+				os.Unsetenv(name)
+			}
+		}()
+
+		// Result must not depend on map iteration order across repeated loads
+		for i := 0; i < 10; i++ {
+			report, err := cfg.LoadWithReport("", nil, LoadOptions{
+				Sources:   []Source{SourceEnv, SourceDefault},
+				EnvPrefix: "APP_",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"APP_REGION", "APP_TIMEOUT", "CUSTOM_API_KEY"}, report.EnvVars)
+
+			key, _ := cfg.Get("api_key")
+			assert.Equal(t, "explicit-key", key)
+			region, _ := cfg.Get("region")
+			assert.Equal(t, "prefixed-region", region)
+			timeout, _ := cfg.Get("timeout")
+			assert.Equal(t, "5s", timeout)
+		}
+	})
+
 	t.Run("EnvWhitelist", func(t *testing.T) {
 		cfg := New()
 		cfg.Register("allowed.path", "default1")
@@ -285,8 +481,89 @@ func TestCLIParsing(t *testing.T) {
 		})
 	}
 
+	t.Run("IndexedElementOverride", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.hosts", []string{"a", "b", "c"})
+
+		err := cfg.LoadCLI([]string{"--server.hosts[1]=x"})
+		require.NoError(t, err)
+
+		val, _ := cfg.Get("server.hosts")
+		assert.Equal(t, []any{"a", "x", "c"}, val)
+
+		var result struct {
+			Server struct {
+				Hosts []string `toml:"hosts"`
+			} `toml:"server"`
+		}
+		require.NoError(t, cfg.Scan(&result))
+		assert.Equal(t, []string{"a", "x", "c"}, result.Server.Hosts)
+	})
+
+	t.Run("SingleDashFlags", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.port", 0)
+		cfg.Register("server.host", "")
+		cfg.Register("debug", false)
+		cfg.Register("offset", 0)
+		cfg.Register("v", false)
+		cfg.Register("q", false)
+
+		err := cfg.LoadCLI([]string{"-server.port", "8080", "-debug", "--server.host=h", "-offset", "-5", "-vq"})
+		require.NoError(t, err)
+
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, "8080", port)
+		debug, _ := cfg.Get("debug")
+		assert.Equal(t, "true", debug)
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "h", host)
+		offset, _ := cfg.Get("offset")
+		assert.Equal(t, "-5", offset, "negative numbers are values, not flags")
+		v, _ := cfg.Get("v")
+		assert.Equal(t, "true", v)
+		q, _ := cfg.Get("q")
+		assert.Equal(t, "true", q)
+
+		// Later occurrences win regardless of dash style
+		require.NoError(t, cfg.LoadCLI([]string{"--server.port=1", "-server.port=2"}))
+		port, _ = cfg.Get("server.port")
+		assert.Equal(t, "2", port)
+
+		// Clusters may only combine boolean flags
+		require.NoError(t, cfg.RegisterAlias("p", "server.port"))
+		err = cfg.LoadCLI([]string{"-vp"})
+		assert.ErrorIs(t, err, ErrCLIParse)
+		assert.ErrorContains(t, err, `non-boolean flag "p"`)
+		port, _ = cfg.Get("server.port")
+		assert.Equal(t, "2", port)
+	})
+
+	t.Run("NegatedBooleanFlags", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("features.caching", true)
+		cfg.Register("server.port", 8080)
+		require.NoError(t, cfg.RegisterAlias("caching", "features.caching"))
+
+		require.NoError(t, cfg.LoadCLI([]string{"--no-features.caching"}))
+		val, _ := cfg.Get("features.caching")
+		assert.Equal(t, "false", val)
+
+		require.NoError(t, cfg.LoadCLI([]string{"--features.caching"}))
+		val, _ = cfg.Get("features.caching")
+		assert.Equal(t, "true", val)
+
+		require.NoError(t, cfg.LoadCLI([]string{"--no-caching"}))
+		val, _ = cfg.Get("features.caching")
+		assert.Equal(t, "false", val)
+
+		err := cfg.LoadCLI([]string{"--no-server.port"})
+		assert.ErrorIs(t, err, ErrCLIParse)
+		assert.Contains(t, err.Error(), "cannot negate non-boolean path")
+	})
+
 	t.Run("InvalidKeySegment", func(t *testing.T) {
-		result, err := parseArgs([]string{"--invalid!key=value"})
+		result, err := parseArgs([]string{"--invalid!key=value"}, nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid command-line key segment")
 		assert.Nil(t, result)
@@ -339,6 +616,120 @@ port = 8080
 	assert.Equal(t, int64(8080), sources[SourceFile])
 }
 
+// TestLoadWithReport tests per-source load diagnostics
+func TestLoadWithReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+unknown = "ignored"
+[server]
+host = "filehost"
+port = 8080
+`), 0644))
+
+	t.Setenv("REPORT_SERVER_HOST", "envhost")
+	t.Setenv("REPORT_DEBUG", "true")
+
+	opts := LoadOptions{
+		Sources:   []Source{SourceCLI, SourceEnv, SourceFile, SourceDefault},
+		EnvPrefix: "REPORT_",
+	}
+	t.Run("AllSources", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "defaulthost")
+		cfg.Register("server.port", 3000)
+		cfg.Register("server.tags", []string{"a", "b"})
+		cfg.Register("debug", false)
+		args := []string{"--server.port=7070", "--server.tags[1]=z", "--unregistered=1"}
+
+		report, err := cfg.LoadWithReport(configFile, args, opts)
+		require.NoError(t, err)
+
+		assert.True(t, report.FileFound)
+		assert.Equal(t, configFile, report.FilePath)
+		assert.Equal(t, 2, report.Counts[SourceFile])
+		assert.Equal(t, 2, report.Counts[SourceEnv])
+		assert.Equal(t, 2, report.Counts[SourceCLI], "registered and element paths count, unknown flags do not")
+		assert.Equal(t, []string{"REPORT_DEBUG", "REPORT_SERVER_HOST"}, report.EnvVars)
+
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, "7070", port)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "defaulthost")
+		cfg.Register("server.port", 3000)
+		cfg.Register("server.tags", []string{"a", "b"})
+		cfg.Register("debug", false)
+
+		missing := filepath.Join(tmpDir, "missing.toml")
+		report, err := cfg.LoadWithReport(missing, nil, opts)
+		assert.ErrorIs(t, err, ErrConfigNotFound)
+
+		assert.False(t, report.FileFound)
+		assert.Equal(t, missing, report.FilePath, "the resolved path is reported even when missing")
+		assert.Zero(t, report.Counts[SourceFile])
+		assert.Equal(t, 2, report.Counts[SourceEnv])
+		assert.Zero(t, report.Counts[SourceCLI])
+
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "envhost", host)
+	})
+}
+
+// TestLoadOptional tests loading with an optional config file
+func TestLoadOptional(t *testing.T) {
+	tmpDir := t.TempDir()
+	opts := DefaultLoadOptions()
+
+	t.Run("MissingFile", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.port", 3000)
+		found, err := cfg.LoadOptional(filepath.Join(tmpDir, "missing.toml"), []string{"--server.port=7070"}, opts)
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, "7070", port, "other sources still load")
+	})
+
+	t.Run("PresentFile", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "present.toml")
+		require.NoError(t, os.WriteFile(path, []byte("[server]\nport = 8080\n"), 0644))
+
+		cfg := New()
+		cfg.Register("server.port", 3000)
+		found, err := cfg.LoadOptional(path, nil, opts)
+		assert.NoError(t, err)
+		assert.True(t, found)
+
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(8080), port)
+	})
+
+	t.Run("ParseError", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "broken.toml")
+		require.NoError(t, os.WriteFile(path, []byte("[server\nport = "), 0644))
+
+		cfg := New()
+		cfg.Register("server.port", 3000)
+		found, err := cfg.LoadOptional(path, nil, opts)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse TOML")
+		assert.False(t, found)
+	})
+
+	t.Run("OtherErrorsKept", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.port", 3000)
+		found, err := cfg.LoadOptional(filepath.Join(tmpDir, "missing.toml"), []string{"--no-server.port"}, opts)
+		assert.False(t, found)
+		assert.ErrorIs(t, err, ErrCLIParse)
+		assert.NotErrorIs(t, err, ErrConfigNotFound)
+	})
+}
+
 // TestAtomicSave tests atomic file saving
 func TestAtomicSave(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -390,6 +781,28 @@ func TestAtomicSave(t *testing.T) {
 		assert.NotContains(t, string(content), "6666")
 	})
 
+	t.Run("Preview", func(t *testing.T) {
+		savePath := filepath.Join(tmpDir, "preview.toml")
+		preview, err := cfg.SavePreview()
+		require.NoError(t, err)
+		_, err = os.Stat(savePath)
+		assert.True(t, os.IsNotExist(err), "preview must not write")
+
+		require.NoError(t, cfg.Save(savePath))
+		content, err := os.ReadFile(savePath)
+		require.NoError(t, err)
+		assert.Equal(t, content, preview)
+
+		sourcePath := filepath.Join(tmpDir, "preview-env.toml")
+		sourcePreview, err := cfg.SaveSourcePreview(SourceEnv)
+		require.NoError(t, err)
+		require.NoError(t, cfg.SaveSource(sourcePath, SourceEnv))
+		content, err = os.ReadFile(sourcePath)
+		require.NoError(t, err)
+		assert.Equal(t, content, sourcePreview)
+		assert.NotEqual(t, preview, sourcePreview)
+	})
+
 	t.Run("SaveToNonExistentDirectory", func(t *testing.T) {
 		savePath := filepath.Join(tmpDir, "new", "dir", "config.toml")
 		err := cfg.Save(savePath)
@@ -399,6 +812,188 @@ func TestAtomicSave(t *testing.T) {
 		_, err = os.Stat(savePath)
 		assert.NoError(t, err)
 	})
+	t.Run("FileModes", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Skipping permission test on Windows")
+		}
+
+		defaultPath := filepath.Join(tmpDir, "default-mode.toml")
+		require.NoError(t, cfg.Save(defaultPath))
+		info, err := os.Stat(defaultPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+		secretDir := filepath.Join(tmpDir, "secrets")
+		secretPath := filepath.Join(secretDir, "secret.toml")
+		require.NoError(t, cfg.SaveWithOptions(secretPath, SaveOptions{FileMode: 0600, DirMode: 0700}))
+
+		info, err = os.Stat(secretPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		dirInfo, err := os.Stat(secretDir)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0700), dirInfo.Mode().Perm())
+
+		// Overwriting an existing file applies the requested mode too
+		require.NoError(t, cfg.SaveWithOptions(defaultPath, SaveOptions{FileMode: 0600}))
+		info, err = os.Stat(defaultPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		content, err := os.ReadFile(secretPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "savehost")
+	})
+	t.Run("DirectorySync", func(t *testing.T) {
+		var synced []string
+		original := syncDir
+		syncDir = func(dir string) error {
+			synced = append(synced, dir)
+			return original(dir)
+		}
+		defer func() { syncDir = original }()
+
+		savePath := filepath.Join(tmpDir, "synced", "config.toml")
+		require.NoError(t, cfg.Save(savePath))
+		require.NoError(t, cfg.SaveSource(savePath, SourceEnv))
+		assert.Equal(t, []string{filepath.Dir(savePath), filepath.Dir(savePath)}, synced)
+
+		content, err := os.ReadFile(savePath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "envhost")
+	})
+
+	t.Run("DirectorySyncFailure", func(t *testing.T) {
+		original := syncDir
+		syncDir = func(string) error { return os.ErrPermission }
+		defer func() { syncDir = original }()
+
+		err := cfg.Save(filepath.Join(tmpDir, "sync-fail.toml"))
+		assert.ErrorIs(t, err, os.ErrPermission)
+		assert.Contains(t, err.Error(), "failed to sync directory")
+	})
+	t.Run("Backup", func(t *testing.T) {
+		c := New()
+		c.Register("version", 0)
+		dir := filepath.Join(tmpDir, "backup")
+		savePath := filepath.Join(dir, "config.toml")
+		opts := SaveOptions{Backup: true, BackupKeep: 2}
+
+		// First save has nothing to back up
+		c.Set("version", 1)
+		require.NoError(t, c.SaveWithOptions(savePath, opts))
+		_, err := os.Stat(savePath + ".bak")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+
+		for v := 2; v <= 4; v++ {
+			c.Set("version", v)
+			require.NoError(t, c.SaveWithOptions(savePath, opts))
+		}
+
+		read := func(path string) string {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			return strings.TrimSpace(string(data))
+		}
+		assert.Equal(t, "version = 4", read(savePath))
+		assert.Equal(t, "version = 3", read(savePath+".bak"))
+		assert.Equal(t, "version = 2", read(savePath+".bak.1"))
+		_, err = os.Stat(savePath + ".bak.2")
+		assert.ErrorIs(t, err, os.ErrNotExist, "only BackupKeep backups are kept")
+	})
+
+	t.Run("TimestampedBackup", func(t *testing.T) {
+		c := New()
+		c.Register("version", 0)
+		dir := filepath.Join(tmpDir, "timestamped")
+		savePath := filepath.Join(dir, "config.toml")
+		opts := SaveOptions{Backup: true, BackupTimestamp: true, BackupSuffix: ".old", BackupKeep: 2}
+
+		for v := 1; v <= 4; v++ {
+			c.Set("version", v)
+			require.NoError(t, c.SaveWithOptions(savePath, opts))
+		}
+
+		backups, err := filepath.Glob(filepath.Join(dir, "config.toml.*.old"))
+		require.NoError(t, err)
+		require.Len(t, backups, 2)
+
+		oldest, err := os.ReadFile(backups[0])
+		require.NoError(t, err)
+		newest, err := os.ReadFile(backups[1])
+		require.NoError(t, err)
+		assert.Contains(t, string(oldest), "version = 2")
+		assert.Contains(t, string(newest), "version = 3")
+	})
+}
+
+// TestSaveArraysOfTables tests that [[table]] arrays survive load, save, and reload
+func TestSaveArraysOfTables(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "source.toml")
+	require.NoError(t, os.WriteFile(sourcePath, []byte(`
+[[servers]]
+name = "web1"
+port = 80
+
+[[servers]]
+name = "web2"
+port = 81
+[servers.tls]
+enabled = true
+
+[[servers]]
+name = "web3"
+port = 82
+[[servers.routes]]
+path = "/api"
+`), 0644))
+
+	cfg := New()
+	cfg.Register("servers", []any{})
+	cfg.Register("backends", []any{})
+	require.NoError(t, cfg.LoadFile(sourcePath))
+	// Arrays of tables load as []any of maps, like JSON and YAML lists
+	servers, _ := cfg.Get("servers")
+	require.IsType(t, []any{}, servers)
+	// Element writes and slices of maps built in code save as arrays of tables too
+	require.NoError(t, cfg.Set("servers[0].port", int64(8080)))
+	require.NoError(t, cfg.Set("backends", []any{
+		map[string]any{"host": "db1"},
+		map[string]any{"host": "db2"},
+	}))
+
+	savedPath := filepath.Join(tmpDir, "saved.toml")
+	require.NoError(t, cfg.Save(savedPath))
+	content, err := os.ReadFile(savedPath)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(content), "[[servers]]"))
+	assert.Equal(t, 2, strings.Count(string(content), "[[backends]]"))
+	assert.Contains(t, string(content), "[[servers.routes]]")
+
+	reloaded := New()
+	reloaded.Register("servers", []any{})
+	reloaded.Register("backends", []any{})
+	require.NoError(t, reloaded.LoadFile(savedPath))
+	for _, path := range []string{"servers", "backends"} {
+		original, _ := cfg.Get(path)
+		roundTripped, _ := reloaded.Get(path)
+		assert.Equal(t, original, roundTripped, path)
+	}
+	port, _ := reloaded.Get("servers[0].port")
+	assert.Equal(t, int64(8080), port)
+	enabled, _ := reloaded.Get("servers[1].tls.enabled")
+	assert.Equal(t, true, enabled)
+	route, _ := reloaded.Get("servers[2].routes[0].path")
+	assert.Equal(t, "/api", route)
+
+	// A second save produces the same file
+	resavedPath := filepath.Join(tmpDir, "resaved.toml")
+	require.NoError(t, reloaded.Save(resavedPath))
+	resaved, err := os.ReadFile(resavedPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(content), string(resaved))
 }
 
 // TestExportEnv tests environment variable export
@@ -420,6 +1015,66 @@ func TestExportEnv(t *testing.T) {
 	assert.NotContains(t, exports, "APP_SERVER_PORT") // Still default
 }
 
+// TestDocumentEnv tests generating env var documentation for registered paths
+func TestDocumentEnv(t *testing.T) {
+	type DBConfig struct {
+		URL      string `toml:"url" env:"DATABASE_URL" required:"true"`
+		Password string `toml:"password" sensitive:"true"`
+	}
+	type AppConfig struct {
+		Port    int64         `toml:"port" usage:"Listen port"`
+		Timeout time.Duration `toml:"timeout"`
+		Tags    []string      `toml:"tags"`
+		DB      DBConfig      `toml:"db"`
+	}
+
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("", &AppConfig{
+		Port:    8080,
+		Timeout: 5 * time.Second,
+		Tags:    []string{"a", "b"},
+		DB:      DBConfig{URL: "postgres://localhost", Password: "hunter2"},
+	}))
+	require.NoError(t, cfg.Register("extra", nil))
+
+	docs := cfg.DocumentEnv("APP_")
+	require.Len(t, docs, len(cfg.GetRegisteredPaths()))
+
+	byPath := make(map[string]EnvDoc, len(docs))
+	for i, doc := range docs {
+		if i > 0 {
+			assert.Less(t, docs[i-1].Path, doc.Path, "docs are sorted by path")
+		}
+		byPath[doc.Path] = doc
+	}
+
+	assert.Equal(t, EnvDoc{EnvVar: "APP_PORT", Path: "port", Type: "int64", Default: "8080", Usage: "Listen port"}, byPath["port"])
+	assert.Equal(t, "5s", byPath["timeout"].Default)
+	assert.Equal(t, "time.Duration", byPath["timeout"].Type)
+	assert.Equal(t, "a,b", byPath["tags"].Default)
+	assert.Equal(t, "any", byPath["extra"].Type)
+
+	t.Run("ExplicitEnvTag", func(t *testing.T) {
+		url := byPath["db.url"]
+		assert.Equal(t, "DATABASE_URL", url.EnvVar)
+		assert.True(t, url.Required)
+	})
+
+	t.Run("SensitiveDefaultHidden", func(t *testing.T) {
+		password := byPath["db.password"]
+		assert.Equal(t, "APP_DB_PASSWORD", password.EnvVar)
+		assert.True(t, password.Sensitive)
+		assert.Empty(t, password.Default)
+	})
+
+	t.Run("MatchesLoading", func(t *testing.T) {
+		t.Setenv("APP_PORT", "9090")
+		require.NoError(t, cfg.LoadEnv("APP_"))
+		port, _ := cfg.GetSource("port", SourceEnv)
+		assert.Equal(t, "9090", port)
+	})
+}
+
 // splitEnvVar splits environment variable into key and value
 func splitEnvVar(env string) []string {
 	parts := make([]string, 2)
@@ -431,4 +1086,555 @@ func splitEnvVar(env string) []string {
 		}
 	}
 	return []string{env}
+}
+
+// TestNumericNormalization tests that numbers load as the same types across formats
+func TestNumericNormalization(t *testing.T) {
+	contents := map[string]string{
+		"toml": "port = 9090\nratio = 1.5\nports = [80, 443]\n[limits]\nmax = 10\n",
+		"json": `{"port": 9090, "ratio": 1.5, "ports": [80, 443], "limits": {"max": 10}}`,
+		"yaml": "port: 9090\nratio: 1.5\nports: [80, 443]\nlimits:\n  max: 10\n",
+	}
+
+	results := make(map[string]map[string]any)
+	for format, content := range contents {
+		cfg := New()
+		cfg.Register("port", int64(0))
+		cfg.Register("ratio", 0.0)
+		cfg.Register("ports", []int64{})
+		cfg.Register("limits.max", int64(0))
+
+		require.NoError(t, cfg.LoadReader(strings.NewReader(content), format), format)
+		require.NoError(t, cfg.Validate("port", "ratio", "limits.max"), format)
+
+		values := make(map[string]any)
+		for _, path := range []string{"port", "ratio", "ports", "limits.max"} {
+			values[path], _ = cfg.Get(path)
+		}
+		results[format] = values
+	}
+
+	assert.Equal(t, int64(9090), results["toml"]["port"])
+	assert.Equal(t, 1.5, results["toml"]["ratio"])
+	assert.Equal(t, []any{int64(80), int64(443)}, results["toml"]["ports"])
+	assert.Equal(t, results["toml"], results["json"])
+	assert.Equal(t, results["toml"], results["yaml"])
+}
+
+// TestMaxDepth tests the nesting depth limit on parsed file data
+func TestMaxDepth(t *testing.T) {
+	// nestedJSON builds {"l1":{"l2":...{"lN":1}}}, whose leaf sits in depth levels of maps
+	nestedJSON := func(depth int) string {
+		var sb strings.Builder
+		for i := 1; i <= depth; i++ {
+			fmt.Fprintf(&sb, `{"l%d":`, i)
+		}
+		sb.WriteString("1")
+		sb.WriteString(strings.Repeat("}", depth))
+		return sb.String()
+	}
+
+	t.Run("DefaultLimit", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.LoadReader(strings.NewReader(nestedJSON(DefaultMaxDepth)), "json"))
+
+		err := cfg.LoadReader(strings.NewReader(nestedJSON(DefaultMaxDepth+1)), "json")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds maximum nesting depth 32")
+	})
+
+	t.Run("ArraysCount", func(t *testing.T) {
+		cfg := New()
+		cfg.SetSecurityOptions(SecurityOptions{MaxDepth: 3})
+
+		require.NoError(t, cfg.LoadReader(strings.NewReader(`{"a": [[1, 2]]}`), "json"))
+		assert.Error(t, cfg.LoadReader(strings.NewReader(`{"a": [[[1]]]}`), "json"))
+	})
+
+	t.Run("CustomLimitYAMLFile", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("a.b.c", 0)
+		cfg.SetSecurityOptions(SecurityOptions{MaxDepth: 3})
+
+		dir := t.TempDir()
+		under := filepath.Join(dir, "under.yaml")
+		over := filepath.Join(dir, "over.yaml")
+		require.NoError(t, os.WriteFile(under, []byte("a:\n  b:\n    c: 5\n"), 0644))
+		require.NoError(t, os.WriteFile(over, []byte("a:\n  b:\n    c:\n      d: 5\n"), 0644))
+
+		require.NoError(t, cfg.LoadFile(under))
+		val, _ := cfg.Get("a.b.c")
+		assert.Equal(t, int64(5), val)
+
+		err := cfg.LoadFile(over)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds maximum nesting depth 3")
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		cfg := New()
+		cfg.SetSecurityOptions(SecurityOptions{MaxDepth: -1})
+		assert.NoError(t, cfg.LoadReader(strings.NewReader(nestedJSON(100)), "json"))
+	})
+}
+
+// TestYAMLAliasLimit tests rejection of alias expansion bombs
+func TestYAMLAliasLimit(t *testing.T) {
+	// Each level references the previous one nine times: 9^9 leaves once expanded
+	bomb := `a: &a ["x","x","x","x","x","x","x","x","x"]
+b: &b [*a,*a,*a,*a,*a,*a,*a,*a,*a]
+c: &c [*b,*b,*b,*b,*b,*b,*b,*b,*b]
+d: &d [*c,*c,*c,*c,*c,*c,*c,*c,*c]
+e: &e [*d,*d,*d,*d,*d,*d,*d,*d,*d]
+f: &f [*e,*e,*e,*e,*e,*e,*e,*e,*e]
+g: &g [*f,*f,*f,*f,*f,*f,*f,*f,*f]
+h: &h [*g,*g,*g,*g,*g,*g,*g,*g,*g]
+i: &i [*h,*h,*h,*h,*h,*h,*h,*h,*h]
+`
+
+	t.Run("BombRejected", func(t *testing.T) {
+		cfg := New()
+		err := cfg.LoadReader(strings.NewReader(bomb), "yaml")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expanded YAML nodes")
+	})
+
+	t.Run("AnchoredDocument", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("defaults.timeout", 0)
+		cfg.Register("primary.timeout", 0)
+		cfg.Register("primary.host", "")
+		cfg.Register("replica.timeout", 0)
+
+		doc := `defaults: &defaults
+  timeout: 30
+primary:
+  <<: *defaults
+  host: db1
+replica: *defaults
+`
+		require.NoError(t, cfg.LoadReader(strings.NewReader(doc), "yaml"))
+		val, _ := cfg.Get("primary.timeout")
+		assert.Equal(t, int64(30), val)
+		val, _ = cfg.Get("primary.host")
+		assert.Equal(t, "db1", val)
+		val, _ = cfg.Get("replica.timeout")
+		assert.Equal(t, int64(30), val)
+	})
+
+	t.Run("CustomLimit", func(t *testing.T) {
+		cfg := New()
+		cfg.SetSecurityOptions(SecurityOptions{MaxYAMLNodes: 10})
+
+		assert.NoError(t, cfg.LoadReader(strings.NewReader("a: &a [1, 2]\nb: *a\n"), "yaml"))
+		err := cfg.LoadReader(strings.NewReader("a: &a [1, 2, 3]\nb: *a\nc: *a\n"), "yaml")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds maximum of 10 expanded YAML nodes")
+	})
+
+	t.Run("EmptyDocument", func(t *testing.T) {
+		cfg := New()
+		assert.NoError(t, cfg.LoadReader(strings.NewReader(""), "yaml"))
+		assert.NoError(t, cfg.LoadReader(strings.NewReader("# comment only\n"), "yaml"))
+	})
+}
+
+// TestCoerceToDefaultType tests env and CLI values stored as the default's type
+func TestCoerceToDefaultType(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte("[server]\nport = 7070\n"), 0644))
+
+	t.Run("EnvMatchesFileType", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.port", 8080))
+		require.NoError(t, cfg.Register("server.timeout", 5*time.Second))
+		require.NoError(t, cfg.Register("server.tags", []string{"a"}))
+		require.NoError(t, cfg.Register("extra", nil))
+		t.Setenv("APP_SERVER_PORT", "9090")
+		t.Setenv("APP_SERVER_TIMEOUT", "30s")
+		t.Setenv("APP_SERVER_TAGS", "x,y")
+		t.Setenv("APP_EXTRA", "raw")
+
+		opts := DefaultLoadOptions()
+		opts.EnvPrefix = "APP_"
+		opts.CoerceToDefaultType = true
+		require.NoError(t, cfg.LoadWithOptions(configFile, nil, opts))
+
+		sources := cfg.GetSources("server.port")
+		assert.Equal(t, int64(7070), sources[SourceFile])
+		assert.Equal(t, int64(9090), sources[SourceEnv])
+		assert.IsType(t, sources[SourceFile], sources[SourceEnv])
+
+		timeout, _ := cfg.Get("server.timeout")
+		assert.Equal(t, 30*time.Second, timeout)
+		tags, _ := cfg.Get("server.tags")
+		assert.Equal(t, []string{"x", "y"}, tags)
+		extra, _ := cfg.Get("extra")
+		assert.Equal(t, "raw", extra, "nil defaults keep the string")
+	})
+
+	t.Run("CLI", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.port", 8080))
+		opts := DefaultLoadOptions()
+		opts.CoerceToDefaultType = true
+		require.NoError(t, cfg.LoadWithOptions("", []string{"--server.port=9191"}, opts))
+
+		port, _ := cfg.GetSource("server.port", SourceCLI)
+		assert.Equal(t, int64(9191), port)
+	})
+
+	t.Run("UnconvertibleKeptAsString", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.port", 8080))
+		t.Setenv("APP_SERVER_PORT", "not-a-port")
+
+		opts := DefaultLoadOptions()
+		opts.EnvPrefix = "APP_"
+		opts.CoerceToDefaultType = true
+		require.NoError(t, cfg.LoadWithOptions("", nil, opts))
+
+		port, _ := cfg.GetSource("server.port", SourceEnv)
+		assert.Equal(t, "not-a-port", port)
+		assert.Error(t, cfg.ValidateTypes())
+	})
+
+	t.Run("HookMayReadConfig", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.port", 8080))
+		require.NoError(t, cfg.Register("server.timeout", 5*time.Second))
+		require.NoError(t, cfg.AddDecodeHook(func(f, to reflect.Type, data any) (any, error) {
+			cfg.Get("server.timeout")
+			return data, nil
+		}))
+		t.Setenv("APP_SERVER_PORT", "9090")
+
+		opts := DefaultLoadOptions()
+		opts.EnvPrefix = "APP_"
+		opts.CoerceToDefaultType = true
+		done := make(chan error, 1)
+		go func() { done <- cfg.LoadWithOptions("", []string{"--server.timeout=1m"}, opts) }()
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("load deadlocked in a decode hook")
+		}
+
+		timeout, _ := cfg.Get("server.timeout")
+		assert.Equal(t, time.Minute, timeout)
+	})
+
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.port", 8080))
+		t.Setenv("APP_SERVER_PORT", "9090")
+		require.NoError(t, cfg.LoadEnv("APP_"))
+
+		port, _ := cfg.GetSource("server.port", SourceEnv)
+		assert.Equal(t, "9090", port)
+	})
+}
+
+// TestLoadHooks tests hooks called around each source during a load
+func TestLoadHooks(t *testing.T) {
+	type event struct {
+		phase  string
+		source Source
+		count  int
+		err    error
+	}
+	recordHooks := func(events *[]event) LoadHooks {
+		return LoadHooks{
+			BeforeSource: func(source Source) {
+				*events = append(*events, event{phase: "before", source: source})
+			},
+			AfterSource: func(source Source, count int, err error) {
+				*events = append(*events, event{"after", source, count, err})
+			},
+		}
+	}
+
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte("[server]\nhost = \"file\"\nport = 7070\n"), 0644))
+
+	t.Run("LoadOrderAndCounts", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.host", "localhost"))
+		require.NoError(t, cfg.Register("server.port", 8080))
+		require.NoError(t, cfg.Register("debug", false))
+		t.Setenv("APP_SERVER_PORT", "9090")
+
+		var events []event
+		opts := DefaultLoadOptions()
+		opts.EnvPrefix = "APP_"
+		opts.Hooks = recordHooks(&events)
+		require.NoError(t, cfg.LoadWithOptions(configFile, []string{"--debug", "--server.host=cli"}, opts))
+
+		assert.Equal(t, []event{
+			{phase: "before", source: SourceFile},
+			{"after", SourceFile, 2, nil},
+			{phase: "before", source: SourceEnv},
+			{"after", SourceEnv, 1, nil},
+			{phase: "before", source: SourceCLI},
+			{"after", SourceCLI, 2, nil},
+		}, events)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cfg := New()
+		var events []event
+		opts := DefaultLoadOptions()
+		opts.Hooks = recordHooks(&events)
+		report, err := cfg.LoadWithReport(filepath.Join(t.TempDir(), "missing.toml"), nil, opts)
+		require.ErrorIs(t, err, ErrConfigNotFound)
+
+		require.Len(t, events, 6)
+		assert.Equal(t, SourceFile, events[1].source)
+		assert.ErrorIs(t, events[1].err, ErrConfigNotFound)
+		assert.Equal(t, 0, events[5].count, "no arguments given")
+		assert.False(t, report.FileFound)
+	})
+
+	t.Run("PartialHooks", func(t *testing.T) {
+		cfg := New()
+		var sources []Source
+		opts := DefaultLoadOptions()
+		opts.Hooks.BeforeSource = func(source Source) { sources = append(sources, source) }
+		require.NoError(t, cfg.LoadWithOptions(configFile, nil, opts))
+		assert.Equal(t, []Source{SourceFile, SourceEnv, SourceCLI}, sources)
+	})
+}
+
+// TestEnvValueNormalization tests quote stripping and optional whitespace trimming of env values
+func TestEnvValueNormalization(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		trimmed string // With TrimEnvWhitespace
+		kept    string // Without TrimEnvWhitespace
+	}{
+		{"Plain", "example.com", "example.com", "example.com"},
+		{"DoubleQuoted", `"example.com"`, "example.com", "example.com"},
+		{"SingleQuoted", `'example.com'`, "example.com", "example.com"},
+		{"SpacePadded", "  example.com \t", "example.com", "  example.com \t"},
+		{"QuotedSpaces", `" example.com "`, " example.com ", " example.com "},
+		{"SpaceAndQuotes", `  "example.com"  `, "example.com", `  "example.com"  `},
+		{"MismatchedQuotes", `"example.com'`, `"example.com'`, `"example.com'`},
+		{"LoneQuote", `"`, `"`, `"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_SERVER_HOST", tt.value)
+
+			for _, trim := range []bool{true, false} {
+				cfg := New()
+				require.NoError(t, cfg.Register("server.host", "localhost"))
+
+				opts := DefaultLoadOptions()
+				opts.EnvPrefix = "APP_"
+				opts.TrimEnvWhitespace = trim
+				require.NoError(t, cfg.LoadWithOptions("", nil, opts))
+
+				expected := tt.kept
+				if trim {
+					expected = tt.trimmed
+				}
+				host, _ := cfg.Get("server.host")
+				assert.Equal(t, expected, host, "TrimEnvWhitespace=%v", trim)
+			}
+		})
+	}
+
+	t.Run("ExplicitEnvNames", func(t *testing.T) {
+		t.Setenv("HOST_NAME", `  'example.com'  `)
+
+		cfg := NewWithOptions(LoadOptions{
+			Sources:           []Source{SourceCLI, SourceEnv, SourceFile, SourceDefault},
+			TrimEnvWhitespace: true,
+		})
+		require.NoError(t, cfg.RegisterWithEnv("server.host", "localhost", "HOST_NAME"))
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "example.com", host)
+	})
+}
+
+// TestEnvSeparator tests env var names with a custom segment separator
+func TestEnvSeparator(t *testing.T) {
+	type defaults struct {
+		Server struct {
+			MaxConns int64 `toml:"max_conns"`
+		} `toml:"server"`
+		ServerMax struct {
+			Conns int64 `toml:"conns"`
+		} `toml:"server_max"`
+	}
+
+	t.Run("DoubleUnderscore", func(t *testing.T) {
+		t.Setenv("APP_SERVER__MAX_CONNS", "10")
+		t.Setenv("APP_SERVER_MAX__CONNS", "20")
+		cfg, err := NewBuilder().
+			WithDefaults(&defaults{}).
+			WithEnvPrefix("APP_").
+			WithEnvSeparator("__").
+			Build()
+		require.NoError(t, err)
+
+		maxConns, _ := cfg.Get("server.max_conns")
+		assert.Equal(t, "10", maxConns)
+		conns, _ := cfg.Get("server_max.conns")
+		assert.Equal(t, "20", conns)
+
+		path, ok := cfg.EnvVarPath("APP_SERVER__MAX_CONNS")
+		require.True(t, ok)
+		assert.Equal(t, "server.max_conns", path)
+		path, ok = cfg.EnvVarPath("APP_SERVER_MAX__CONNS")
+		require.True(t, ok)
+		assert.Equal(t, "server_max.conns", path)
+
+		assert.Equal(t, map[string]string{
+			"server.max_conns": "APP_SERVER__MAX_CONNS",
+			"server_max.conns": "APP_SERVER_MAX__CONNS",
+		}, cfg.DiscoverEnv("APP_"))
+	})
+
+	t.Run("DefaultSeparatorCollides", func(t *testing.T) {
+		t.Setenv("APP_SERVER_MAX_CONNS", "30")
+		cfg, err := NewBuilder().
+			WithDefaults(&defaults{}).
+			WithEnvPrefix("APP_").
+			WithEnvSeparator("").
+			Build()
+		require.NoError(t, err)
+
+		maxConns, _ := cfg.Get("server.max_conns")
+		conns, _ := cfg.Get("server_max.conns")
+		assert.Equal(t, "30", maxConns)
+		assert.Equal(t, "30", conns)
+
+		_, ok := cfg.EnvVarPath("APP_SERVER_MAX_CONNS")
+		assert.False(t, ok, "ambiguous name")
+		_, ok = cfg.EnvVarPath("APP_UNKNOWN")
+		assert.False(t, ok)
+	})
+}
+
+// TestReload tests which sources survive Reload and ReloadWithOptions
+func TestReload(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	writeConfig := func(t *testing.T, port int, host string) {
+		content := fmt.Sprintf("[server]\nport = %d\nhost = %q\n", port, host)
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	opts := DefaultLoadOptions()
+	opts.EnvPrefix = "RELOAD_"
+	args := []string{"--server.host=cli-host"}
+
+	t.Run("PreservesCLI", func(t *testing.T) {
+		t.Setenv("RELOAD_LOG_LEVEL", "debug")
+		writeConfig(t, 8080, "file-host")
+		cfg := New()
+		cfg.Register("server.port", 0)
+		cfg.Register("server.host", "localhost")
+		cfg.Register("log.level", "info")
+		require.NoError(t, cfg.LoadWithOptions(configPath, args, opts))
+
+		require.NoError(t, cfg.Set("server.port", int64(1234))) // Set writes to SourceCLI
+
+		writeConfig(t, 9090, "new-file-host")
+		t.Setenv("RELOAD_LOG_LEVEL", "warn")
+		require.NoError(t, cfg.Reload())
+
+		// CLI overrides survive, file and env values update beneath them
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(1234), port)
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "cli-host", host)
+		filePort, _ := cfg.GetSource("server.port", SourceFile)
+		assert.Equal(t, int64(9090), filePort)
+		level, _ := cfg.Get("log.level")
+		assert.Equal(t, "warn", level)
+	})
+
+	t.Run("ClearsUnpreserved", func(t *testing.T) {
+		t.Setenv("RELOAD_LOG_LEVEL", "debug")
+		writeConfig(t, 8080, "file-host")
+		cfg := New()
+		cfg.Register("server.port", 0)
+		cfg.Register("server.host", "localhost")
+		cfg.Register("log.level", "info")
+		require.NoError(t, cfg.LoadWithOptions(configPath, args, opts))
+
+		writeConfig(t, 9090, "new-file-host")
+		require.NoError(t, os.Unsetenv("RELOAD_LOG_LEVEL"))
+		require.NoError(t, cfg.ReloadWithOptions(ReloadOptions{}))
+
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "new-file-host", host)
+		level, _ := cfg.Get("log.level")
+		assert.Equal(t, "info", level, "removed env var no longer applies")
+		assert.Empty(t, cfg.GetSources("log.level"))
+	})
+
+	t.Run("PreservesEnv", func(t *testing.T) {
+		t.Setenv("RELOAD_LOG_LEVEL", "debug")
+		writeConfig(t, 8080, "file-host")
+		cfg := New()
+		cfg.Register("server.port", 0)
+		cfg.Register("server.host", "localhost")
+		cfg.Register("log.level", "info")
+		require.NoError(t, cfg.LoadWithOptions(configPath, args, opts))
+
+		t.Setenv("RELOAD_LOG_LEVEL", "warn")
+		require.NoError(t, cfg.ReloadWithOptions(ReloadOptions{PreserveSources: []Source{SourceEnv, SourceCLI}}))
+
+		level, _ := cfg.Get("log.level")
+		assert.Equal(t, "debug", level, "preserved env is not re-read")
+	})
+
+	t.Run("CustomSources", func(t *testing.T) {
+		const sourceVault Source = "vault"
+		t.Setenv("RELOAD_LOG_LEVEL", "debug")
+		writeConfig(t, 8080, "file-host")
+		cfg := New()
+		cfg.Register("server.port", 0)
+		cfg.Register("server.host", "localhost")
+		cfg.Register("log.level", "info")
+		require.NoError(t, cfg.LoadWithOptions(configPath, args, opts))
+		require.NoError(t, cfg.RegisterSource(sourceVault))
+		require.NoError(t, cfg.SetPrecedence(SourceCLI, sourceVault, SourceEnv, SourceFile, SourceDefault))
+		require.NoError(t, cfg.SetSource(sourceVault, "server.port", int64(6000)))
+		require.NoError(t, cfg.AddRemoteSource("kv", &fakeRemote{data: map[string]any{"log.level": "trace"}}, SourceEnv))
+
+		require.NoError(t, cfg.Reload())
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(6000), port, "custom sources cannot be re-read, so they are kept")
+		level, _ := cfg.Get("log.level")
+		assert.Equal(t, "debug", level, "the env reload replaces the remote's values")
+		assert.Empty(t, cfg.remotes["kv"].paths)
+
+		require.NoError(t, cfg.ReloadWithOptions(ReloadOptions{ClearCustomSources: true}))
+		port, _ = cfg.Get("server.port")
+		assert.Equal(t, int64(8080), port)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Setenv("RELOAD_LOG_LEVEL", "debug")
+		writeConfig(t, 8080, "file-host")
+		cfg := New()
+		cfg.Register("server.port", 0)
+		cfg.Register("server.host", "localhost")
+		cfg.Register("log.level", "info")
+		require.NoError(t, cfg.LoadWithOptions(configPath, args, opts))
+
+		assert.ErrorIs(t, cfg.ReloadWithOptions(ReloadOptions{PreserveSources: []Source{"cl"}}), ErrInvalidSource)
+
+		// A failed file read leaves values unchanged
+		require.NoError(t, os.WriteFile(configPath, []byte("invalid toml ["), 0644))
+		assert.Error(t, cfg.Reload())
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(8080), port)
+	})
 }
\ No newline at end of file
@@ -0,0 +1,36 @@
+// FILE: lixenwraith/config/logger.go
+package config
+
+// Logger receives diagnostics for conditions that do not fail an operation, such as
+// dropped watch notifications, failed watcher reloads, and ignored file keys.
+// Implementations must be safe for concurrent use.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Warnf(format string, args ...any)
+	Errorf(format string, args ...any)
+}
+
+// nopLogger discards all diagnostics
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...any) {}
+func (nopLogger) Warnf(string, ...any)  {}
+func (nopLogger) Errorf(string, ...any) {}
+
+// SetLogger sets the logger for internal diagnostics. A nil logger discards them,
+// which is the default. It takes effect immediately, including for a running watcher.
+func (c *Config) SetLogger(logger Logger) {
+	if logger == nil {
+		c.log.Store(nil)
+		return
+	}
+	c.log.Store(&logger)
+}
+
+// logger returns the configured logger, or a no-op logger if none is set
+func (c *Config) logger() Logger {
+	if logger := c.log.Load(); logger != nil {
+		return *logger
+	}
+	return nopLogger{}
+}
\ No newline at end of file
@@ -0,0 +1,169 @@
+// FILE: lixenwraith/config/persist.go
+package config
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// exportFormatVersion is the version of the Export format written by this package.
+// Import rejects data written with a newer version.
+const exportFormatVersion = 1
+
+// exportData is the gob-encoded form of a config written by Export
+type exportData struct {
+	Version    int
+	Precedence []Source
+	Items      map[string]exportItem
+}
+
+// exportItem is the exported state of a single registered path
+type exportItem struct {
+	Default  any
+	Values   map[Source]any
+	SetTimes map[Source]time.Time
+}
+
+var registerGobTypesOnce sync.Once
+
+// registerGobTypes registers the value types produced by the loaders that gob
+// does not know, so they can travel inside interface values
+func registerGobTypes() {
+	registerGobTypesOnce.Do(func() {
+		gob.Register(map[string]any{})
+		gob.Register([]any{})
+		gob.Register([]map[string]any{})
+		gob.Register(time.Time{})
+		gob.Register(time.Duration(0))
+	})
+}
+
+// Export writes every registered path with its default, per-source values, and
+// source timestamps, plus the source precedence, in a compact versioned binary
+// form for Import. Values must be gob-encodable: scalars, strings, time.Time,
+// time.Duration, and slices and maps of them are; types such as *regexp.Regexp
+// are not and fail the export.
+func (c *Config) Export(w io.Writer) error {
+	registerGobTypes()
+	c.resolveLazyDefaults()
+
+	c.mutex.RLock()
+	data := exportData{
+		Version:    exportFormatVersion,
+		Precedence: append([]Source(nil), c.options.Sources...),
+		Items:      make(map[string]exportItem, len(c.items)),
+	}
+	for path, item := range c.items {
+		exported := exportItem{
+			Default:  item.defaultValue,
+			Values:   make(map[Source]any, len(item.values)),
+			SetTimes: make(map[Source]time.Time, len(item.setTimes)),
+		}
+		for source, value := range item.values {
+			exported.Values[source] = value
+		}
+		for source, at := range item.setTimes {
+			exported.SetTimes[source] = at
+		}
+		data.Items[path] = exported
+	}
+	c.mutex.RUnlock()
+
+	if err := gob.NewEncoder(w).Encode(data); err != nil {
+		return fmt.Errorf("failed to export config: %w", err)
+	}
+	return nil
+}
+
+// Import restores state written by Export. Every source value is replaced by the
+// imported ones and the precedence is restored; custom sources are registered as
+// needed. Registered paths keep their registered defaults, and paths that are not
+// registered are registered with the exported default.
+func (c *Config) Import(r io.Reader) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	registerGobTypes()
+
+	var data exportData
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return fmt.Errorf("failed to import config: %w", err)
+	}
+	if data.Version < 1 || data.Version > exportFormatVersion {
+		return fmt.Errorf("unsupported export format version %d", data.Version)
+	}
+	for path := range data.Items {
+		for _, segment := range strings.Split(path, ".") {
+			if !isValidKeySegment(segment) {
+				return errInvalidSegment(path, segment)
+			}
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Register custom sources that appear in the data
+	addSource := func(source Source) {
+		if c.validateSource(source) == nil || source == "" {
+			return
+		}
+		if c.customData == nil {
+			c.customData = make(map[Source]map[string]any)
+		}
+		c.customData[source] = make(map[string]any)
+	}
+	for _, source := range data.Precedence {
+		addSource(source)
+	}
+	for _, exported := range data.Items {
+		for source := range exported.Values {
+			addSource(source)
+		}
+	}
+
+	// Clear all source values, then apply the imported ones
+	for _, source := range []Source{SourceFile, SourceEnv, SourceCLI} {
+		c.clearSourceData(source)
+	}
+	for source := range c.customData {
+		c.clearSourceData(source)
+	}
+	if len(data.Precedence) > 0 {
+		c.options.Sources = data.Precedence
+	}
+
+	for path, item := range c.items {
+		item.values = make(map[Source]any)
+		item.setTimes = nil
+		c.storeItem(path, item)
+	}
+	for path, exported := range data.Items {
+		item, registered := c.items[path]
+		if !registered {
+			item = configItem{defaultValue: exported.Default, values: make(map[Source]any)}
+		}
+		for source, value := range exported.Values {
+			at, stamped := exported.SetTimes[source]
+			item.setSource(source, value, at)
+			if !stamped {
+				delete(item.setTimes, source)
+			}
+			if cache := c.sourceData(source); cache != nil {
+				cache[path] = value
+			}
+		}
+		c.storeItem(path, item)
+	}
+	for path, item := range c.items {
+		item.currentValue = c.computeValue(item)
+		c.storeItem(path, item)
+	}
+
+	c.invalidateCache()
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+// FILE: lixenwraith/config/profile.go
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadProfile selects a profile overlay and reloads the tracked config file.
+// The base file is loaded first, then <base>.<profile>.<ext> (config.prod.toml
+// for config.toml) is merged over it at SourceFile; a missing overlay is skipped.
+// The profile also applies to later loads and watcher reloads of the file.
+// An empty profile clears the overlay.
+func (c *Config) LoadProfile(profile string) error {
+	if err := c.checkLoadable(); err != nil {
+		return err
+	}
+	if err := validateProfile(profile); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	c.profile = profile
+	path := c.configFilePath
+	c.mutex.Unlock()
+
+	if path == "" {
+		return errors.New("no config file loaded to apply the profile to")
+	}
+	return c.loadFile(path, nil)
+}
+
+// Profile returns the active profile name, or empty if none is set
+func (c *Config) Profile() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.profile
+}
+
+// WithProfile sets baseName as the config file and selects the profile overlay
+// named by the envVar environment variable, read when WithProfile is called.
+// With APP_ENV=prod, WithProfile("APP_ENV", "config.toml") loads config.toml
+// and then config.prod.toml over it. An unset or empty variable loads only the base.
+func (b *Builder) WithProfile(envVar, baseName string) *Builder {
+	profile := os.Getenv(envVar)
+	if err := validateProfile(profile); err != nil {
+		b.err = fmt.Errorf("invalid profile from %s: %w", envVar, err)
+		return b
+	}
+	b.file = baseName
+	b.profile = profile
+	return b
+}
+
+// profilePath returns the overlay path for a base file: config.toml becomes config.<profile>.toml
+func profilePath(base, profile string) string {
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + profile + ext
+}
+
+// validateProfile rejects profile names that would place the overlay outside the base file's directory
+func validateProfile(profile string) error {
+	if strings.ContainsAny(profile, `/\`) || strings.Contains(profile, "..") {
+		return fmt.Errorf("profile name %q must not contain path separators or '..'", profile)
+	}
+	return nil
+}
\ No newline at end of file
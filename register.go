@@ -3,9 +3,13 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"maps"
 	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Register makes a configuration path known to the Config instance.
@@ -13,28 +17,67 @@ import (
 // Each segment of the path must be a valid TOML key identifier.
 // defaultValue is the value returned by Get if no specific value has been set.
 func (c *Config) Register(path string, defaultValue any) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	if err := validateRegistrationPath(path); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.registerLocked(path, defaultValue)
+	return nil
+}
+
+// GetOrRegister returns the current value of path, first registering it with
+// defaultValue if it is not registered yet. The check and the registration happen
+// under one lock, so concurrent callers agree on a single registration. An existing
+// registration is kept as is, including its default and source values.
+func (c *Config) GetOrRegister(path string, defaultValue any) (any, error) {
+	if err := validateRegistrationPath(path); err != nil {
+		return nil, err
+	}
+
+	c.resolveLazyDefaults(path)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if item, registered := c.items[path]; registered {
+		return copyValue(item.currentValue), nil
+	}
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	c.registerLocked(path, defaultValue)
+	return copyValue(defaultValue), nil
+}
+
+// validateRegistrationPath checks that path is non-empty and that each segment is a valid key
+func validateRegistrationPath(path string) error {
 	if path == "" {
 		return fmt.Errorf("registration path cannot be empty")
 	}
 
 	// Validate path segments
-	segments := strings.Split(path, ".")
-	for _, segment := range segments {
+	for _, segment := range strings.Split(path, ".") {
 		if !isValidKeySegment(segment) {
-			return fmt.Errorf("invalid path segment %q in path %q", segment, path)
+			return errInvalidSegment(path, segment)
 		}
 	}
+	return nil
+}
 
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	c.items[path] = configItem{
+// registerLocked stores a fresh item for path with defaultValue. Caller must hold the write lock.
+func (c *Config) registerLocked(path string, defaultValue any) {
+	c.storeItem(path, configItem{
 		defaultValue: defaultValue,
 		currentValue: defaultValue, // Initially set to default
 		values:       make(map[Source]any),
-	}
-
-	return nil
+	})
+	c.invalidateCache()
 }
 
 // RegisterWithEnv registers a path with an explicit environment variable mapping
@@ -43,8 +86,12 @@ func (c *Config) RegisterWithEnv(path string, defaultValue any, envVar string) e
 		return err
 	}
 
+	c.mutex.Lock()
+	c.envNames[path] = envVar
+	c.mutex.Unlock()
+
 	// Check if the environment variable exists and load it
-	if value, exists := os.LookupEnv(envVar); exists {
+	if value, exists := c.lookupEnv(envVar); exists {
 		parsed := parseValue(value)
 		return c.SetSource(SourceEnv, path, parsed)
 	}
@@ -52,16 +99,210 @@ func (c *Config) RegisterWithEnv(path string, defaultValue any, envVar string) e
 	return nil
 }
 
+// lazyDefault is a default computed on first use by a RegisterFunc function
+type lazyDefault struct {
+	once  sync.Once
+	fn    func() any
+	value any
+	done  atomic.Bool
+}
+
+// get calls the function once and returns its result
+func (l *lazyDefault) get() any {
+	l.once.Do(func() {
+		l.value = l.fn()
+		l.done.Store(true)
+	})
+	return l.value
+}
+
+// RegisterFunc registers a path whose default is computed by defaultFn, for defaults
+// that are expensive or have side effects, such as hostname lookups or generated IDs.
+// defaultFn is called at most once, the first time the value is read or resolved
+// while no source provides one; once called, its result is the path's default.
+// It runs without the config lock, so it may read other paths, but not its own.
+func (c *Config) RegisterFunc(path string, defaultFn func() any) error {
+	if defaultFn == nil {
+		return fmt.Errorf("default function for path %s cannot be nil", path)
+	}
+	if err := c.Register(path, nil); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item := c.items[path]
+	item.lazyDefault = &lazyDefault{fn: defaultFn}
+	c.storeItem(path, item)
+	return nil
+}
+
+// resolveLazyDefaults evaluates the RegisterFunc defaults that no source provides,
+// for the given paths or for all paths if none are given, and records the results
+// as the defaults. The functions run without the lock, so they may read other paths.
+// Read paths call it before taking the lock; it is a no-op unless one is pending.
+func (c *Config) resolveLazyDefaults(paths ...string) {
+	if c.lazyPending.Load() == 0 {
+		return
+	}
+
+	c.mutex.RLock()
+	pending := make(map[string]*lazyDefault)
+	if len(paths) == 0 {
+		for path, item := range c.items {
+			if c.lazyDefaultUnresolved(item) {
+				pending[path] = item.lazyDefault
+			}
+		}
+	}
+	for _, path := range paths {
+		if item, registered := c.items[path]; registered && c.lazyDefaultUnresolved(item) {
+			pending[path] = item.lazyDefault
+		}
+	}
+	c.mutex.RUnlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	for _, lazy := range pending {
+		lazy.get()
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for path, lazy := range pending {
+		item, registered := c.items[path]
+		if !registered || item.lazyDefault != lazy {
+			continue // Re-registered or given a new default meanwhile
+		}
+		item.defaultValue = lazy.value
+		item.lazyDefault = nil
+		item.currentValue = c.computeValue(item)
+		c.storeItem(path, item)
+	}
+	c.invalidateCache()
+}
+
+// lazyDefaultUnresolved reports whether item has a RegisterFunc default not yet
+// recorded and no source value overriding it
+func (c *Config) lazyDefaultUnresolved(item configItem) bool {
+	if item.lazyDefault == nil {
+		return false
+	}
+	for _, source := range c.options.Sources {
+		if val, exists := item.values[source]; exists && val != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// RegisterAlias adds an alternate flag name for a registered path, such as "p" for "server.port".
+// Aliases are honored by GenerateFlags, BindFlags, and command-line loading.
+func (c *Config) RegisterAlias(alias, path string) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+
+	if alias == "" {
+		return fmt.Errorf("alias cannot be empty")
+	}
+
+	segments := strings.Split(alias, ".")
+	for _, segment := range segments {
+		if !isValidKeySegment(segment) {
+			return fmt.Errorf("invalid alias segment %q in alias %q", segment, alias)
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.items[path]; !exists {
+		return errNotRegistered(path)
+	}
+	if _, exists := c.items[alias]; exists {
+		return fmt.Errorf("alias %q conflicts with a registered path", alias)
+	}
+	if target, exists := c.aliases[alias]; exists {
+		return fmt.Errorf("alias %q already registered for path %s", alias, target)
+	}
+
+	c.aliases[alias] = path
+	return nil
+}
+
+// resolveAlias returns the target path for an alias, or name unchanged
+func (c *Config) resolveAlias(name string) string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if path, exists := c.aliases[name]; exists {
+		return path
+	}
+	return name
+}
+
 // RegisterRequired registers a path and marks it as required
-// The configuration will fail validation if this value is not provided
+// ValidateRequired fails if no source provides a value for it
 func (c *Config) RegisterRequired(path string, defaultValue any) error {
-	// For now, just register normally
-	// The required paths will be tracked separately in a future enhancement
-	return c.Register(path, defaultValue)
+	if err := c.Register(path, defaultValue); err != nil {
+		return err
+	}
+	c.updateItem(path, func(item *configItem) {
+		item.required = true
+	})
+	return nil
+}
+
+// requiredCondition makes a path required while another path's value equals a given value
+type requiredCondition struct {
+	path   string
+	equals any // nil matches any non-zero value
+}
+
+// describe returns the condition's expected value for error messages
+func (rc requiredCondition) describe() string {
+	if rc.equals == nil {
+		return "set"
+	}
+	return fmt.Sprintf("%v", rc.equals)
+}
+
+// RegisterRequiredIf makes a registered path required while condPath's resolved value
+// equals condEquals, converted to condEquals' type (so env "true" matches true).
+// A nil condEquals makes path required whenever condPath has a non-zero value.
+// Conditions are checked by ValidateRequired; several may be added for one path.
+func (c *Config) RegisterRequiredIf(path string, condPath string, condEquals any) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, exists := c.items[path]
+	if !exists {
+		return errNotRegistered(path)
+	}
+	if _, exists := c.items[condPath]; !exists {
+		return errNotRegistered(condPath)
+	}
+
+	item.requiredIf = append(item.requiredIf, requiredCondition{path: condPath, equals: condEquals})
+	c.storeItem(path, item)
+	return nil
 }
 
 // Unregister removes a configuration path and all its children.
 func (c *Config) Unregister(path string) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -78,22 +319,67 @@ func (c *Config) Unregister(path string) error {
 		}
 		// If neither the path nor any children exist, return error
 		if !hasChildren {
-			return fmt.Errorf("path not registered: %s", path)
+			return errNotRegistered(path)
 		}
 	}
 
+	c.removePath(path)
+	c.invalidateCache()
+	return nil
+}
+
+// UnregisterKeepValues removes a single registered path and returns the values it held
+// from each source, so they can be restored with SetSource after re-registering the path,
+// possibly with a new default type. Defaults are not included in the returned map.
+// A path with registered children is rejected, since their values would be lost.
+func (c *Config) UnregisterKeepValues(path string) (map[Source]any, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, exists := c.items[path]
+	if !exists {
+		return nil, errNotRegistered(path)
+	}
+	if c.hasChildren(path) {
+		return nil, fmt.Errorf("path %s has registered children; unregister them first or use Unregister", path)
+	}
+
+	saved := make(map[Source]any, len(item.values))
+	for source, value := range item.values {
+		saved[source] = copyValue(value)
+	}
+
+	c.removePath(path)
+	c.invalidateCache()
+	return saved, nil
+}
+
+// removePath deletes a path, its children, and aliases pointing at them.
+// Must be called with the lock held.
+func (c *Config) removePath(path string) {
 	// Remove the path itself if it exists
-	delete(c.items, path)
+	c.deleteItem(path)
+	delete(c.envNames, path)
 
 	// Remove any child paths
 	prefix := path + "."
 	for childPath := range c.items {
 		if strings.HasPrefix(childPath, prefix) {
-			delete(c.items, childPath)
+			c.deleteItem(childPath)
+			delete(c.envNames, childPath)
 		}
 	}
 
-	return nil
+	// Remove aliases pointing at removed paths
+	for alias, target := range c.aliases {
+		if target == path || strings.HasPrefix(target, prefix) {
+			delete(c.aliases, alias)
+		}
+	}
 }
 
 // RegisterStruct registers configuration values derived from a struct.
@@ -105,18 +391,79 @@ func (c *Config) RegisterStruct(prefix string, structWithDefaults any) error {
 
 // RegisterStructWithTags is like RegisterStruct but allows custom tag names
 func (c *Config) RegisterStructWithTags(prefix string, structWithDefaults any, tagName string) error {
+	return c.registerStruct("RegisterStructWithTags", prefix, structWithDefaults, tagName, false)
+}
+
+// MergeStruct is like RegisterStruct but only registers paths that are not yet registered.
+// Existing items keep their defaults and source values, so several structs sharing a
+// prefix can be composed. A field whose default type differs from the registered default
+// is reported as a conflict.
+func (c *Config) MergeStruct(prefix string, structWithDefaults any) error {
+	return c.registerStruct("MergeStruct", prefix, structWithDefaults, "toml", true)
+}
+
+// registerStruct registers the fields of a struct, combining field errors into one error
+func (c *Config) registerStruct(caller, prefix string, structWithDefaults any, tagName string, merge bool) error {
+	fieldErrs, err := c.registerStructFields(caller, prefix, structWithDefaults, tagName, merge)
+	if err != nil {
+		return err
+	}
+
+	if len(fieldErrs) > 0 {
+		messages := make([]string, len(fieldErrs))
+		for i, fieldErr := range fieldErrs {
+			messages[i] = fieldErr.Error()
+		}
+		return fmt.Errorf("failed to register %d field(s): %s", len(fieldErrs), strings.Join(messages, "; "))
+	}
+
+	return nil
+}
+
+// FieldRegistrationError describes a struct field that failed to register
+type FieldRegistrationError struct {
+	Field string // Go field path, e.g. "Server.Port"
+	Path  string // Configuration path, e.g. "server.port"
+	Err   error
+}
+
+// Error implements the error interface
+func (e FieldRegistrationError) Error() string {
+	return fmt.Sprintf("field %s (path %s): %v", e.Field, e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e FieldRegistrationError) Unwrap() error {
+	return e.Err
+}
+
+// RegisterStructStrict is like RegisterStruct but reports each failing field as a
+// FieldRegistrationError instead of one combined message. Registration continues past
+// failing fields, so the other fields are registered. The error is non-nil only when
+// the struct itself cannot be registered, such as a nil pointer or a non-struct value.
+func (c *Config) RegisterStructStrict(prefix string, structWithDefaults any) ([]FieldRegistrationError, error) {
+	return c.registerStructFields("RegisterStructStrict", prefix, structWithDefaults, "toml", false)
+}
+
+// registerStructFields validates the struct and tag name, then registers its fields,
+// collecting the errors of fields that fail to register
+func (c *Config) registerStructFields(caller, prefix string, structWithDefaults any, tagName string, merge bool) ([]FieldRegistrationError, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+
 	v := reflect.ValueOf(structWithDefaults)
 
 	// Handle pointer or direct struct value
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
-			return fmt.Errorf("RegisterStructWithTags requires a non-nil struct pointer or value")
+			return nil, fmt.Errorf("%s requires a non-nil struct pointer or value", caller)
 		}
 		v = v.Elem()
 	}
 
 	if v.Kind() != reflect.Struct {
-		return fmt.Errorf("RegisterStructWithTags requires a struct or struct pointer, got %T", structWithDefaults)
+		return nil, fmt.Errorf("%s requires a struct or struct pointer, got %T", caller, structWithDefaults)
 	}
 
 	// Validate tag name
@@ -124,51 +471,153 @@ func (c *Config) RegisterStructWithTags(prefix string, structWithDefaults any, t
 	case "toml", "json", "yaml":
 		// Supported tags
 	default:
-		return fmt.Errorf("unsupported tag name %q, must be one of: toml, json, yaml", tagName)
+		return nil, fmt.Errorf("unsupported tag name %q, must be one of: toml, json, yaml", tagName)
 	}
 
-	var errors []string
+	var fieldErrs []FieldRegistrationError
 
 	// Use helper function for recursive registration with specified tag
-	c.registerFields(v, prefix, "", &errors, tagName)
+	c.registerFields(v, prefix, "", &fieldErrs, tagName, merge)
+	return fieldErrs, nil
+}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("failed to register %d field(s): %s", len(errors), strings.Join(errors, "; "))
-	}
+// registerFields is a helper function that handles the recursive field registration.
+// With merge set, fields whose path is already registered are left untouched.
+func (c *Config) registerFields(v reflect.Value, pathPrefix, fieldPath string, fieldErrs *[]FieldRegistrationError, tagName string, merge bool) {
+	walkStructFields(v, pathPrefix, fieldPath, tagName, func(f structField) {
+		field := f.field
+		currentPath := f.path
+		fieldPath := f.fieldPath
 
-	return nil
+		// Check for additional tags
+		envTag := field.Tag.Get("env") // Explicit env var name
+		required := field.Tag.Get("required") == "true"
+		usage := field.Tag.Get("usage") // Description for flags and help output
+		sensitive := field.Tag.Get("sensitive") == "true"
+
+		// Register non-struct fields
+		defaultValue := f.value.Interface()
+
+		// Pointer-to-scalar fields register the pointee, or nil when unset so Scan leaves them nil
+		if f.value.Kind() == reflect.Ptr && f.value.Type().Elem().Kind() != reflect.Struct {
+			if f.value.IsNil() {
+				defaultValue = nil
+			} else {
+				defaultValue = f.value.Elem().Interface()
+			}
+		}
+
+		if merge {
+			if existing, registered := c.registeredDefault(currentPath); registered {
+				if existing != nil && defaultValue != nil && reflect.TypeOf(existing) != reflect.TypeOf(defaultValue) {
+					*fieldErrs = append(*fieldErrs, FieldRegistrationError{
+						Field: fieldPath + field.Name,
+						Path:  currentPath,
+						Err:   fmt.Errorf("type conflict: registered %T, got %T", existing, defaultValue),
+					})
+				}
+				return
+			}
+		}
+
+		var err error
+		if required {
+			err = c.RegisterRequired(currentPath, defaultValue)
+		} else {
+			err = c.Register(currentPath, defaultValue)
+		}
+
+		if err != nil {
+			*fieldErrs = append(*fieldErrs, FieldRegistrationError{Field: fieldPath + field.Name, Path: currentPath, Err: err})
+		}
+
+		// Interface-typed fields such as any hold values of any type, so their default
+		// (often nil) does not determine the type of later values
+		untyped := f.value.Kind() == reflect.Interface
+
+		if (usage != "" || sensitive || untyped) && err == nil {
+			c.updateItem(currentPath, func(item *configItem) {
+				item.usage = usage
+				item.sensitive = sensitive
+				item.untyped = untyped
+			})
+		}
+
+		// Handle explicit env tag
+		if envTag != "" && err == nil {
+			c.mutex.Lock()
+			c.envNames[currentPath] = envTag
+			c.mutex.Unlock()
+
+			if value, exists := c.lookupEnv(envTag); exists {
+				parsed := parseValue(value)
+				if setErr := c.SetSource(SourceEnv, currentPath, parsed); setErr != nil {
+					*fieldErrs = append(*fieldErrs, FieldRegistrationError{
+						Field: fieldPath + field.Name,
+						Path:  currentPath,
+						Err:   fmt.Errorf("env %s: %w", envTag, setErr),
+					})
+				}
+			}
+		}
+	})
 }
 
-// registerFields is a helper function that handles the recursive field registration.
-func (c *Config) registerFields(v reflect.Value, pathPrefix, fieldPath string, errors *[]string, tagName string) {
+// structField is a leaf field found by walkStructFields
+type structField struct {
+	path      string // Dotted configuration path
+	fieldPath string // Go field path prefix for messages, e.g. "Server."
+	field     reflect.StructField
+	value     reflect.Value
+}
+
+// walkStructFields visits the leaf fields of a struct, deriving dotted paths from tagName tags.
+// Nested structs are recursed into; atomic types such as time.Time and *url.URL are leaves (see isAtomicStructType).
+func walkStructFields(v reflect.Value, pathPrefix, fieldPath, tagName string, visit func(f structField)) {
 	t := v.Type()
 
 	for i := 0; i < v.NumField(); i++ {
 		field := t.Field(i)
 		fieldValue := v.Field(i)
 
-		if !field.IsExported() {
-			continue
-		}
-
 		// Get tag value based on tagName parameter
 		tag := field.Tag.Get(tagName)
 		if tag == "-" {
 			continue
 		}
+		tagKey, tagOpts := parseTag(tag)
 
-		// Fall back to field name if no tag
-		key := field.Name
-		if tag != "" {
-			parts := strings.Split(tag, ",")
-			if parts[0] != "" {
-				key = parts[0]
+		// Embedded structs without a tag name promote their fields to the parent,
+		// as in encoding/json. This includes exported fields of unexported embeds.
+		if field.Anonymous && tagKey == "" {
+			if embedded, ok := embeddedStruct(fieldValue); ok {
+				walkStructFields(embedded, pathPrefix, fieldPath+field.Name+".", tagName, visit)
+				continue
 			}
 		}
 
-		// Check for additional tags
-		envTag := field.Tag.Get("env") // Explicit env var name
-		required := field.Tag.Get("required") == "true"
+		if !field.IsExported() {
+			continue
+		}
+
+		// Inline structs and maps are flattened into the parent, as in yaml ",inline".
+		// The mapstructure ",squash" option flattens structs the same way, and Scan honors it.
+		if tagOpts.has("inline") || tagOpts.has("squash") {
+			if inlined, ok := embeddedStruct(fieldValue); ok {
+				walkStructFields(inlined, pathPrefix, fieldPath+field.Name+".", tagName, visit)
+				continue
+			}
+			if fieldValue.Kind() == reflect.Map && fieldValue.Type().Key().Kind() == reflect.String {
+				walkInlineMap(fieldValue, pathPrefix, fieldPath, field, visit)
+				continue
+			}
+		}
+
+		// Fall back to field name if no tag
+		key := field.Name
+		if tagKey != "" {
+			key = tagKey
+		}
 
 		// Build full path
 		currentPath := key
@@ -180,23 +629,28 @@ func (c *Config) registerFields(v reflect.Value, pathPrefix, fieldPath string, e
 		}
 
 		// TODO: use mapstructure instead of logic with reflection
-		// Handle nested structs recursively
+		// Maps of structs register each default entry's fields, e.g. servers.web.port
 		fieldType := fieldValue.Type()
+		if fieldValue.Kind() == reflect.Map && fieldValue.Len() > 0 &&
+			fieldType.Key().Kind() == reflect.String && isNestedStructType(fieldType.Elem()) {
+			keys := fieldValue.MapKeys()
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			for _, key := range keys {
+				if entry, ok := embeddedStruct(fieldValue.MapIndex(key)); ok {
+					entryPrefix := currentPath + "." + key.String() + "."
+					walkStructFields(entry, entryPrefix, fieldPath+field.Name+"["+key.String()+"].", tagName, visit)
+				}
+			}
+			continue
+		}
+
+		// Handle nested structs recursively
 		isStruct := fieldValue.Kind() == reflect.Struct
 		isPtrToStruct := fieldValue.Kind() == reflect.Ptr && fieldType.Elem().Kind() == reflect.Struct
 
 		if isStruct || isPtrToStruct {
-			// Check if the field's TYPE is one that should be treated as a single value,
-			// even though it's a struct. These types have custom decode hooks.
-			fieldType := fieldValue.Type()
-			isAtomicStruct := false
-			switch fieldType.String() {
-			case "time.Time", "*net.IPNet", "*url.URL", "net.IP": // Match the exact type names
-				isAtomicStruct = true
-			}
-
 			// Only recurse if it's a "normal" struct, not an atomic one.
-			if !isAtomicStruct {
+			if !isAtomicStructType(fieldType) {
 				nestedValue := fieldValue
 				if isPtrToStruct {
 					if fieldValue.IsNil() {
@@ -206,35 +660,122 @@ func (c *Config) registerFields(v reflect.Value, pathPrefix, fieldPath string, e
 				}
 
 				nestedPrefix := currentPath + "."
-				c.registerFields(nestedValue, nestedPrefix, fieldPath+field.Name+".", errors, tagName)
+				walkStructFields(nestedValue, nestedPrefix, fieldPath+field.Name+".", tagName, visit)
 				continue
 			}
-			// If it is an atomic struct, we fall through and register it as a single value.
+			// If it is an atomic struct, we fall through and visit it as a single value.
 		}
 
-		// Register non-struct fields
-		defaultValue := fieldValue.Interface()
+		visit(structField{
+			path:      currentPath,
+			fieldPath: fieldPath,
+			field:     field,
+			value:     fieldValue,
+		})
+	}
+}
 
-		var err error
-		if required {
-			err = c.RegisterRequired(currentPath, defaultValue)
-		} else {
-			err = c.Register(currentPath, defaultValue)
-		}
+// tagOptions holds the comma-separated options following a tag name
+type tagOptions []string
 
-		if err != nil {
-			*errors = append(*errors, fmt.Sprintf("field %s%s (path %s): %v", fieldPath, field.Name, currentPath, err))
+// parseTag splits a struct tag into its name and options.
+// Options other than inline (e.g. omitempty) do not affect registration paths.
+func parseTag(tag string) (string, tagOptions) {
+	name, opts, found := strings.Cut(tag, ",")
+	if !found {
+		return name, nil
+	}
+	return name, strings.Split(opts, ",")
+}
+
+// has reports whether the option is present
+func (o tagOptions) has(option string) bool {
+	for _, opt := range o {
+		if opt == option {
+			return true
 		}
+	}
+	return false
+}
 
-		// Handle explicit env tag
-		if envTag != "" && err == nil {
-			if value, exists := os.LookupEnv(envTag); exists {
-				parsed := parseValue(value)
-				if setErr := c.SetSource(SourceEnv, currentPath, parsed); setErr != nil {
-					*errors = append(*errors, fmt.Sprintf("field %s%s env %s: %v", fieldPath, field.Name, envTag, setErr))
-				}
-			}
+// walkInlineMap visits each entry of an inline map as a field of the parent, in key order.
+// Entries take the map field's name for messages but none of its tags.
+func walkInlineMap(m reflect.Value, pathPrefix, fieldPath string, field reflect.StructField, visit func(f structField)) {
+	if pathPrefix != "" && !strings.HasSuffix(pathPrefix, ".") {
+		pathPrefix += "."
+	}
+
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	entryField := field
+	entryField.Tag = ""
+	for _, key := range keys {
+		visit(structField{
+			path:      pathPrefix + key.String(),
+			fieldPath: fieldPath,
+			field:     entryField,
+			value:     m.MapIndex(key),
+		})
+	}
+}
+
+// embeddedStruct returns the struct value of an embedded field, dereferencing pointers.
+// Returns false for non-struct embeds, atomic structs, and nil pointers.
+func embeddedStruct(v reflect.Value) (reflect.Value, bool) {
+	if isAtomicStructType(v.Type()) {
+		return reflect.Value{}, false
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
 		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// isNestedStructType reports whether t is a struct, or pointer to struct, that registration recurses into
+func isNestedStructType(t reflect.Type) bool {
+	if isAtomicStructType(t) {
+		return false
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// isAtomicStructType reports whether t is treated as a single value even though it is
+// a struct (or pointer to one). These types have custom decode hooks.
+func isAtomicStructType(t reflect.Type) bool {
+	switch t.String() {
+	case "time.Time", "*net.IPNet", "*url.URL", "net.IP", "*time.Location", "*regexp.Regexp": // Match the exact type names
+		return true
+	}
+	return false
+}
+
+// registeredDefault returns the default value of a registered path
+func (c *Config) registeredDefault(path string) (any, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	item, exists := c.items[path]
+	return item.defaultValue, exists
+}
+
+// updateItem applies fn to a registered item under the write lock
+func (c *Config) updateItem(path string, fn func(item *configItem)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if item, exists := c.items[path]; exists {
+		fn(&item)
+		c.storeItem(path, item)
 	}
 }
 
@@ -283,7 +824,76 @@ func (c *Config) Scan(target any, basePath ...string) error {
 	return c.unmarshal("", target, basePath...)
 }
 
+// ScanWithMetadata decodes like Scan and reports config keys that matched no
+// struct field (Unused) and struct fields that no config key populated (Unset)
+func (c *Config) ScanWithMetadata(target any, basePath ...string) (Metadata, error) {
+	var md Metadata
+	if err := c.unmarshalWithMetadata("", target, &md, basePath...); err != nil {
+		return Metadata{}, err
+	}
+	return md, nil
+}
+
+// ScanMapped decodes values from arbitrary config paths into target, so the struct's
+// shape need not follow the config layout. mapping maps a struct field path, using
+// the struct's tag names or field names and dots for nested structs, to a config
+// path. Config paths may be registered paths, sections, or element paths such as
+// "servers[0].host". Fields without a mapping are left unchanged.
+//
+//	cfg.ScanMapped(&conn, map[string]string{
+//		"Timeout": "server.read_timeout",
+//		"Host":    "db.host",
+//	})
+func (c *Config) ScanMapped(target any, mapping map[string]string) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("unmarshal target must be non-nil pointer, got %T", target)
+	}
+
+	c.resolveLazyDefaults()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var sections map[string]any // Built on first section lookup
+	data := make(map[string]any)
+	for _, fieldPath := range slices.Sorted(maps.Keys(mapping)) {
+		configPath := mapping[fieldPath]
+
+		var value any
+		if item, registered := c.items[configPath]; registered {
+			value = item.currentValue
+		} else if base, rest, ok := c.resolveIndexedPath(configPath); ok {
+			if value, ok = getIndexedValue(c.items[base].currentValue, rest); !ok {
+				return fmt.Errorf("field %s: element %s out of range", fieldPath, configPath)
+			}
+		} else if c.hasChildren(configPath) {
+			if sections == nil {
+				sections = make(map[string]any)
+				for path, item := range c.items {
+					setNestedValue(sections, path, item.currentValue)
+				}
+			}
+			value = navigateToPath(sections, configPath)
+		} else {
+			return fmt.Errorf("field %s: %w", fieldPath, errNotRegistered(configPath))
+		}
+
+		setNestedValue(data, fieldPath, value)
+	}
+
+	if err := c.decodeInto(target, data, nil); err != nil {
+		return fmt.Errorf("decode failed for mapped scan: %w", err)
+	}
+	return nil
+}
+
 // ScanSource decodes configuration from specific source using unified unmarshal
 func (c *Config) ScanSource(source Source, target any, basePath ...string) error {
+	c.mutex.RLock()
+	err := c.validateSource(source)
+	c.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
 	return c.unmarshal(source, target, basePath...)
 }
\ No newline at end of file
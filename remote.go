@@ -0,0 +1,254 @@
+// FILE: lixenwraith/config/remote.go
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// LoadURL fetches configuration over HTTP(S) and applies it at SourceFile precedence.
+// Format must be "toml", "json", "yaml", or "auto" to detect from the URL path's
+// extension, then from content. The body
+// is capped by the MaxFileSize security option and the request is bound to ctx, so
+// use a context with a timeout. Non-2xx responses are errors. Like LoadReader, the
+// URL is not tracked for watching.
+func (c *Config) LoadURL(ctx context.Context, url string, format string) error {
+	if err := c.checkLoadable(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid config URL '%s': %w", url, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch config from '%s': %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to fetch config from '%s': %s", url, resp.Status)
+	}
+
+	data, err := c.readLimited(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read config from '%s': %w", url, err)
+	}
+
+	if format == "" || format == "auto" {
+		if detected := detectFileFormat(req.URL.Path); detected != "" {
+			format = detected
+		}
+	}
+
+	fileConfig, err := c.parseReaderData(data, format)
+	if err != nil {
+		return err
+	}
+
+	return c.applyFileConfig(fileConfig, "", nil)
+}
+
+// RemoteSource is a key-value backend, such as Consul or etcd, that supplies
+// configuration values. Maps may be nested or use dotted keys.
+type RemoteSource interface {
+	// Fetch returns the current values
+	Fetch(ctx context.Context) (map[string]any, error)
+	// Watch returns a channel of full value sets, sent whenever the backend changes.
+	// The channel should be closed when ctx is done. Sources without live updates
+	// return a nil channel.
+	Watch(ctx context.Context) (<-chan map[string]any, error)
+}
+
+// remote tracks an added RemoteSource
+type remote struct {
+	source Source
+	paths  map[string]bool // Paths whose value in source was set by this remote
+	cancel context.CancelFunc
+}
+
+// AddRemoteSource fetches values from src and stores them in the precedence source
+// slot, which must be in the current precedence order and not SourceDefault. Keys
+// matching no registered path are ignored. If src supports live updates, each
+// update replaces the values set by this remote and is reported to watch
+// subscribers with ChangeCauseRemote. Use RemoveRemoteSource to stop updates.
+func (c *Config) AddRemoteSource(name string, src RemoteSource, precedence Source) error {
+	if err := c.checkLoadable(); err != nil {
+		return err
+	}
+	if name == "" || src == nil {
+		return errors.New("remote source requires a name and a source")
+	}
+
+	c.mutex.RLock()
+	_, exists := c.remotes[name]
+	inPrecedence := slices.Contains(c.options.Sources, precedence)
+	c.mutex.RUnlock()
+	if exists {
+		return fmt.Errorf("remote source %q already added", name)
+	}
+	if precedence == SourceDefault || !inPrecedence {
+		return fmt.Errorf("invalid source %q for remote %q", precedence, name)
+	}
+
+	// Subscribe before fetching so no update between the two is missed
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := src.Watch(ctx)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to watch remote source %q: %w", name, err)
+	}
+	data, err := src.Fetch(ctx)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to fetch remote source %q: %w", name, err)
+	}
+
+	c.mutex.Lock()
+	if _, exists := c.remotes[name]; exists {
+		c.mutex.Unlock()
+		cancel()
+		return fmt.Errorf("remote source %q already added", name)
+	}
+	if c.remotes == nil {
+		c.remotes = make(map[string]*remote)
+	}
+	r := &remote{source: precedence, paths: make(map[string]bool), cancel: cancel}
+	c.remotes[name] = r
+	c.mutex.Unlock()
+
+	c.applyRemote(r, data)
+
+	if updates != nil {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case data, ok := <-updates:
+					if !ok {
+						return
+					}
+					c.applyRemote(r, data)
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// RemoveRemoteSource stops live updates from a remote source and removes the values it set
+func (c *Config) RemoveRemoteSource(name string) error {
+	c.mutex.Lock()
+	r, exists := c.remotes[name]
+	if !exists {
+		c.mutex.Unlock()
+		return fmt.Errorf("remote source %q not found", name)
+	}
+	delete(c.remotes, name)
+	r.cancel()
+	changes := c.replaceRemoteValues(r, nil)
+	c.mutex.Unlock()
+
+	c.notifyRemoteChanges(changes)
+	return nil
+}
+
+// applyRemote replaces the values set by r with data. Updates arriving after the
+// remote was removed, or while the config rejects loads, are dropped.
+func (c *Config) applyRemote(r *remote, data map[string]any) {
+	if err := c.checkLoadable(); err != nil {
+		c.logger().Warnf("config: ignoring update from remote source %s: %v", r.source, err)
+		return
+	}
+	normalizeNumbers(data)
+	collected, _ := c.collectRegistered(data)
+
+	c.mutex.Lock()
+	if !c.hasRemote(r) {
+		c.mutex.Unlock()
+		return
+	}
+	changes := c.replaceRemoteValues(r, collected)
+	c.mutex.Unlock()
+
+	c.notifyRemoteChanges(changes)
+}
+
+// forgetRemotePaths empties the paths recorded by remotes writing to source, after a
+// reload cleared or replaced that source. Must be called with the write lock held.
+func (c *Config) forgetRemotePaths(source Source) {
+	for _, r := range c.remotes {
+		if r.source == source {
+			clear(r.paths)
+		}
+	}
+}
+
+// hasRemote reports whether r is still added. Must be called with the lock held.
+func (c *Config) hasRemote(r *remote) bool {
+	for _, added := range c.remotes {
+		if added == r {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceRemoteValues sets values in r's source slot, unsets paths r set before that
+// are absent from values, and returns the resulting changes sorted by path.
+// Must be called with the write lock held.
+func (c *Config) replaceRemoteValues(r *remote, values map[string]any) []ChangeEvent {
+	var changes []ChangeEvent
+	record := func(path string, old any) {
+		if value := c.items[path].currentValue; !reflect.DeepEqual(old, value) {
+			changes = append(changes, ChangeEvent{Path: path, OldValue: old, NewValue: value, Cause: ChangeCauseRemote})
+		}
+	}
+
+	for path := range r.paths {
+		if _, kept := values[path]; kept {
+			continue
+		}
+		delete(r.paths, path)
+		if item, registered := c.items[path]; registered {
+			old := item.currentValue
+			c.unsetItemSource(r.source, path, item)
+			record(path, old)
+		}
+	}
+	for path, value := range values {
+		item, registered := c.items[path]
+		if !registered {
+			continue
+		}
+		old := item.currentValue
+		c.setItemSource(r.source, path, item, value)
+		r.paths[path] = true
+		record(path, old)
+	}
+
+	c.invalidateCache()
+	slices.SortFunc(changes, func(a, b ChangeEvent) int { return strings.Compare(a.Path, b.Path) })
+	return changes
+}
+
+// notifyRemoteChanges sends remote value changes to watch subscribers, if any
+func (c *Config) notifyRemoteChanges(changes []ChangeEvent) {
+	c.mutex.RLock()
+	w := c.watcher
+	c.mutex.RUnlock()
+	if w == nil {
+		return
+	}
+	for _, change := range changes {
+		w.notifyChange(change)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,214 @@
+// FILE: lixenwraith/config/remote_test.go
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadURL tests loading configuration over HTTP
+func TestLoadURL(t *testing.T) {
+	const body = "[server]\nhost = \"remote\"\nport = 9090\n"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/config.toml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("LoadsTOML", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		require.NoError(t, cfg.LoadURL(context.Background(), server.URL+"/config.toml", "toml"))
+
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "remote", host)
+		port, _ := cfg.GetSource("server.port", SourceFile)
+		assert.Equal(t, int64(9090), port)
+	})
+
+	t.Run("AutoFormat", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		require.NoError(t, cfg.LoadURL(context.Background(), server.URL+"/config.toml", "auto"))
+
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "remote", host)
+	})
+
+	t.Run("SizeCap", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		cfg.SetSecurityOptions(SecurityOptions{MaxFileSize: int64(len(body) - 1)})
+
+		err := cfg.LoadURL(context.Background(), server.URL+"/config.toml", "toml")
+		assert.ErrorContains(t, err, "exceeds maximum size")
+
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "localhost", host, "rejected data leaves values untouched")
+	})
+
+	t.Run("HTTPError", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		err := cfg.LoadURL(context.Background(), server.URL+"/missing", "toml")
+		assert.ErrorContains(t, err, "404")
+	})
+
+	t.Run("ContextTimeout", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := cfg.LoadURL(ctx, server.URL+"/slow", "toml")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Frozen", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		cfg.Freeze()
+		err := cfg.LoadURL(context.Background(), server.URL+"/config.toml", "toml")
+		assert.ErrorIs(t, err, ErrFrozen)
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		err := cfg.LoadURL(context.Background(), server.URL+"/config.toml", "ini")
+		assert.ErrorContains(t, err, "unsupported file format")
+	})
+}
+
+// fakeRemote is an in-memory RemoteSource that pushes updates on demand
+type fakeRemote struct {
+	data    map[string]any
+	updates chan map[string]any
+	err     error
+}
+
+func (f *fakeRemote) Fetch(ctx context.Context) (map[string]any, error) {
+	return f.data, f.err
+}
+
+func (f *fakeRemote) Watch(ctx context.Context) (<-chan map[string]any, error) {
+	if f.updates == nil {
+		return nil, nil
+	}
+	return f.updates, nil
+}
+
+// TestRemoteSource tests merging and live updates from a RemoteSource
+func TestRemoteSource(t *testing.T) {
+	t.Run("FetchMergesAtSource", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		cfg.Register("feature.enabled", false)
+		src := &fakeRemote{data: map[string]any{
+			"server":  map[string]any{"host": "kv-host"},
+			"unknown": "ignored",
+		}}
+		require.NoError(t, cfg.AddRemoteSource("kv", src, SourceEnv))
+
+		host, _ := cfg.GetSource("server.host", SourceEnv)
+		assert.Equal(t, "kv-host", host)
+
+		// A higher-precedence source still wins
+		require.NoError(t, cfg.SetSource(SourceCLI, "server.host", "cli-host"))
+		host, _ = cfg.Get("server.host")
+		assert.Equal(t, "cli-host", host)
+	})
+
+	t.Run("PushedUpdate", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		cfg.Register("feature.enabled", false)
+		src := &fakeRemote{
+			data:    map[string]any{"server.port": int64(9000), "feature.enabled": true},
+			updates: make(chan map[string]any),
+		}
+		require.NoError(t, cfg.AddRemoteSource("kv", src, SourceFile))
+		defer cfg.RemoveRemoteSource("kv")
+
+		events := cfg.WatchEvents()
+		defer cfg.StopAutoUpdate()
+
+		// The update drops feature.enabled and changes server.port
+		src.updates <- map[string]any{"server": map[string]any{"port": 9100}}
+
+		var got []ChangeEvent
+		timeout := time.After(testEventuallyTimeout)
+		for len(got) < 2 {
+			select {
+			case event := <-events:
+				got = append(got, event)
+			case <-timeout:
+				t.Fatalf("timed out waiting for remote change events, got %v", got)
+			}
+		}
+
+		assert.Equal(t, ChangeEvent{Path: "feature.enabled", OldValue: true, NewValue: false, Cause: ChangeCauseRemote}, got[0])
+		assert.Equal(t, ChangeEvent{Path: "server.port", OldValue: int64(9000), NewValue: int64(9100), Cause: ChangeCauseRemote}, got[1])
+
+		_, fromRemote := cfg.GetSource("feature.enabled", SourceFile)
+		assert.False(t, fromRemote)
+	})
+
+	t.Run("RemoveRemoteSource", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		cfg.Register("feature.enabled", false)
+		src := &fakeRemote{data: map[string]any{"server.host": "kv-host"}}
+		require.NoError(t, cfg.AddRemoteSource("kv", src, SourceEnv))
+		require.NoError(t, cfg.SetSource(SourceEnv, "server.port", int64(7000)))
+
+		require.NoError(t, cfg.RemoveRemoteSource("kv"))
+		host, _ := cfg.Get("server.host")
+		assert.Equal(t, "localhost", host)
+		port, _ := cfg.Get("server.port")
+		assert.Equal(t, int64(7000), port, "values not set by the remote are kept")
+
+		assert.Error(t, cfg.RemoveRemoteSource("kv"))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cfg := New()
+		cfg.Register("server.host", "localhost")
+		cfg.Register("server.port", int64(8080))
+		cfg.Register("feature.enabled", false)
+		assert.Error(t, cfg.AddRemoteSource("kv", &fakeRemote{}, SourceDefault))
+		assert.Error(t, cfg.AddRemoteSource("kv", &fakeRemote{}, Source("consul")))
+		assert.Error(t, cfg.AddRemoteSource("kv", &fakeRemote{err: errors.New("unreachable")}, SourceEnv))
+
+		require.NoError(t, cfg.AddRemoteSource("kv", &fakeRemote{}, SourceEnv))
+		assert.Error(t, cfg.AddRemoteSource("kv", &fakeRemote{}, SourceEnv), "duplicate name")
+	})
+}
\ No newline at end of file
@@ -0,0 +1,142 @@
+// FILE: lixenwraith/config/schema.go
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDialect is the JSON Schema draft declared by ExportJSONSchema
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ExportJSONSchema returns a JSON Schema document describing the registered paths.
+// Dotted paths become nested objects; each leaf's type and default come from its
+// registered default value, and its description from the usage tag. Paths with a
+// nil default, and paths registered from interface-typed fields, accept any value.
+func (c *Config) ExportJSONSchema() ([]byte, error) {
+	root := map[string]any{
+		"$schema":    jsonSchemaDialect,
+		"type":       "object",
+		"properties": map[string]any{},
+	}
+
+	c.mutex.RLock()
+	for path, item := range c.items {
+		leaf := schemaForValue(item.defaultValue)
+		if item.untyped {
+			leaf = schemaForValue(nil) // Interface-typed fields accept any value
+		}
+		if item.usage != "" {
+			leaf["description"] = item.usage
+		}
+
+		// Walk or create the parent objects of the leaf
+		segments := strings.Split(path, ".")
+		properties := root["properties"].(map[string]any)
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := properties[segment].(map[string]any)
+			if !ok || child["type"] != "object" || child["properties"] == nil {
+				child = map[string]any{"type": "object", "properties": map[string]any{}}
+				properties[segment] = child
+			}
+			properties = child["properties"].(map[string]any)
+		}
+		properties[segments[len(segments)-1]] = leaf
+	}
+	c.mutex.RUnlock()
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON schema: %w", err)
+	}
+	return data, nil
+}
+
+// schemaForValue returns the schema of a leaf with the given default value
+func schemaForValue(value any) map[string]any {
+	schema := make(map[string]any)
+	if value == nil {
+		return schema
+	}
+
+	for key, v := range schemaForType(reflect.TypeOf(value)) {
+		schema[key] = v
+	}
+	if def, ok := schemaDefault(value, schema["type"] == "string"); ok {
+		schema["default"] = def
+	}
+	return schema
+}
+
+// schemaForType maps a Go type to JSON Schema type keywords. Types decoded from
+// strings by the built-in decode hooks, such as time.Duration, are strings.
+func schemaForType(t reflect.Type) map[string]any {
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		return map[string]any{"type": "string"}
+	case reflect.TypeOf(time.Time{}):
+		return map[string]any{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(&url.URL{}):
+		return map[string]any{"type": "string", "format": "uri"}
+	case reflect.TypeOf(net.IP{}), reflect.TypeOf(&net.IPNet{}), reflect.TypeOf(net.HardwareAddr{}),
+		reflect.TypeOf(&time.Location{}), reflect.TypeOf(&regexp.Regexp{}):
+		return map[string]any{"type": "string"}
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		schema := map[string]any{"type": "array"}
+		if t.Elem().Kind() != reflect.Interface {
+			schema["items"] = schemaForType(t.Elem())
+		}
+		return schema
+	case reflect.Map, reflect.Struct:
+		return map[string]any{"type": "object"}
+	}
+	return map[string]any{}
+}
+
+// schemaDefault returns the JSON form of a default value, or false if it has none.
+// Values of string-typed schemas, such as durations and addresses, use their text form.
+func schemaDefault(value any, isString bool) (any, bool) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, false
+	}
+
+	if isString {
+		switch v := value.(type) {
+		case time.Time:
+			return v.Format(time.RFC3339Nano), true
+		case fmt.Stringer:
+			return v.String(), true
+		}
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		value = rv.Elem().Interface()
+	}
+	if _, err := json.Marshal(value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
\ No newline at end of file
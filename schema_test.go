@@ -0,0 +1,78 @@
+// FILE: lixenwraith/config/schema_test.go
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportJSONSchema tests JSON Schema generation from registered paths
+func TestExportJSONSchema(t *testing.T) {
+	type ServerConfig struct {
+		Host    string        `toml:"host" usage:"Listen address"`
+		Port    int64         `toml:"port"`
+		Timeout time.Duration `toml:"timeout"`
+	}
+	type AppConfig struct {
+		Server ServerConfig `toml:"server"`
+		Debug  bool         `toml:"debug"`
+		Ratio  float64      `toml:"ratio"`
+		Tags   []string     `toml:"tags"`
+	}
+
+	cfg := New()
+	require.NoError(t, cfg.RegisterStruct("", &AppConfig{
+		Server: ServerConfig{Host: "localhost", Port: 8080, Timeout: 5 * time.Second},
+		Ratio:  0.5,
+		Tags:   []string{"a"},
+	}))
+	require.NoError(t, cfg.Register("extra", nil))
+
+	data, err := cfg.ExportJSONSchema()
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	assert.Equal(t, jsonSchemaDialect, schema["$schema"])
+	assert.Equal(t, "object", schema["type"])
+
+	properties := schema["properties"].(map[string]any)
+
+	t.Run("NestedObject", func(t *testing.T) {
+		server, ok := properties["server"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "object", server["type"])
+
+		fields := server["properties"].(map[string]any)
+		host := fields["host"].(map[string]any)
+		assert.Equal(t, "string", host["type"])
+		assert.Equal(t, "localhost", host["default"])
+		assert.Equal(t, "Listen address", host["description"])
+
+		port := fields["port"].(map[string]any)
+		assert.Equal(t, "integer", port["type"])
+		assert.Equal(t, float64(8080), port["default"])
+
+		timeout := fields["timeout"].(map[string]any)
+		assert.Equal(t, "string", timeout["type"])
+		assert.Equal(t, "5s", timeout["default"])
+	})
+
+	t.Run("ScalarTypes", func(t *testing.T) {
+		assert.Equal(t, "boolean", properties["debug"].(map[string]any)["type"])
+		assert.Equal(t, false, properties["debug"].(map[string]any)["default"])
+		assert.Equal(t, "number", properties["ratio"].(map[string]any)["type"])
+
+		tags := properties["tags"].(map[string]any)
+		assert.Equal(t, "array", tags["type"])
+		assert.Equal(t, map[string]any{"type": "string"}, tags["items"])
+	})
+
+	t.Run("NilDefaultAcceptsAny", func(t *testing.T) {
+		assert.Equal(t, map[string]any{}, properties["extra"])
+	})
+}
\ No newline at end of file
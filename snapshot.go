@@ -0,0 +1,175 @@
+// FILE: lixenwraith/config/snapshot.go
+package config
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// ConfigSnapshot is an immutable copy of a Config's resolved values at capture time.
+// Reads need no locking, so a snapshot can be shared freely across goroutines.
+// It does not track later changes; take a new snapshot after the config changes.
+type ConfigSnapshot struct {
+	values     map[string]any
+	precedence []Source
+	version    int64
+	decodeHook mapstructure.DecodeHookFunc // Converts string values as GetTyped does
+	tagName    string
+}
+
+// Snapshot captures the current value of every registered path
+func (c *Config) Snapshot() *ConfigSnapshot {
+	c.resolveLazyDefaults()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	snap := &ConfigSnapshot{
+		values:     make(map[string]any, len(c.items)),
+		precedence: append([]Source(nil), c.options.Sources...),
+		version:    c.version.Load(),
+		decodeHook: c.getDecodeHook(),
+		tagName:    c.tagName,
+	}
+	for path, item := range c.items {
+		snap.values[path] = copyValue(item.currentValue)
+	}
+	return snap
+}
+
+// Get returns the value captured for path. Element paths such as "server.hosts[0]" are supported.
+func (s *ConfigSnapshot) Get(path string) (any, bool) {
+	if value, ok := s.values[path]; ok {
+		return value, true
+	}
+
+	base, rest, ok := splitIndexedPath(path)
+	if !ok {
+		return nil, false
+	}
+	value, registered := s.values[base]
+	if !registered {
+		return nil, false
+	}
+	segments, err := parseIndexedPath(rest)
+	if err != nil {
+		return nil, false
+	}
+	return getIndexedValue(value, segments)
+}
+
+// String returns the value at path if it is a string
+func (s *ConfigSnapshot) String(path string) (string, bool) {
+	value, _ := s.Get(path)
+	str, ok := value.(string)
+	return str, ok
+}
+
+// decodeString converts a string value, such as one from env or the command line, to
+// type t the way GetTyped does. It returns false for other values and failed conversions.
+func (s *ConfigSnapshot) decodeString(value any, t reflect.Type) (any, bool) {
+	str, isString := value.(string)
+	if !isString {
+		return nil, false
+	}
+	converted, err := decodeAs(str, t, s.tagName, s.decodeHook)
+	return converted, err == nil
+}
+
+// Int64 returns the value at path if it is any integer type or a string holding an integer
+func (s *ConfigSnapshot) Int64(path string) (int64, bool) {
+	value, _ := s.Get(path)
+	if converted, ok := s.decodeString(value, reflect.TypeOf(int64(0))); ok {
+		return converted.(int64), true
+	}
+	if _, isDuration := value.(time.Duration); isDuration {
+		return 0, false
+	}
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.CanInt():
+		return rv.Int(), true
+	case rv.CanUint() && rv.Uint() <= 1<<63-1:
+		return int64(rv.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
+// Float64 returns the value at path if it is a float or integer type or a string holding a number
+func (s *ConfigSnapshot) Float64(path string) (float64, bool) {
+	value, _ := s.Get(path)
+	if converted, ok := s.decodeString(value, reflect.TypeOf(float64(0))); ok {
+		return converted.(float64), true
+	}
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.CanFloat():
+		return rv.Float(), true
+	case rv.CanInt():
+		if _, isDuration := value.(time.Duration); isDuration {
+			return 0, false
+		}
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
+// Bool returns the value at path if it is a bool or a string accepted by parseBool,
+// such as "yes" or "off"
+func (s *ConfigSnapshot) Bool(path string) (bool, bool) {
+	value, _ := s.Get(path)
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := parseBool(v)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// Duration returns the value at path if it is a time.Duration or a string holding
+// one, such as "30s"
+func (s *ConfigSnapshot) Duration(path string) (time.Duration, bool) {
+	value, _ := s.Get(path)
+	if converted, ok := s.decodeString(value, reflect.TypeOf(time.Duration(0))); ok {
+		return converted.(time.Duration), true
+	}
+	d, ok := value.(time.Duration)
+	return d, ok
+}
+
+// Time returns the value at path if it is a time.Time, such as a TOML datetime or
+// local date, or a string in a form accepted by Scan, such as RFC 3339
+func (s *ConfigSnapshot) Time(path string) (time.Time, bool) {
+	value, _ := s.Get(path)
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := parseTime(v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// Len returns the number of paths captured in the snapshot
+func (s *ConfigSnapshot) Len() int {
+	return len(s.values)
+}
+
+// Precedence returns the source precedence in effect at capture time
+func (s *ConfigSnapshot) Precedence() []Source {
+	return append([]Source(nil), s.precedence...)
+}
+
+// Version returns the config version at capture time. Two snapshots with the
+// same version hold the same values.
+func (s *ConfigSnapshot) Version() int64 {
+	return s.version
+}
\ No newline at end of file
@@ -0,0 +1,238 @@
+// FILE: lixenwraith/config/snapshot_test.go
+package config
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSnapshot tests capturing and reading immutable snapshots
+func TestSnapshot(t *testing.T) {
+	cfg := New()
+	cfg.Register("server.host", "localhost")
+	cfg.Register("server.port", int64(8080))
+	cfg.Register("server.timeout", 5*time.Second)
+	cfg.Register("server.ratio", 0.5)
+	cfg.Register("debug", false)
+	cfg.Register("server.hosts", []string{"a", "b"})
+
+	t.Run("TypedAccessors", func(t *testing.T) {
+		snap := cfg.Snapshot()
+		assert.Equal(t, 6, snap.Len())
+
+		host, ok := snap.String("server.host")
+		assert.True(t, ok)
+		assert.Equal(t, "localhost", host)
+
+		port, ok := snap.Int64("server.port")
+		assert.True(t, ok)
+		assert.Equal(t, int64(8080), port)
+
+		timeout, ok := snap.Duration("server.timeout")
+		assert.True(t, ok)
+		assert.Equal(t, 5*time.Second, timeout)
+
+		ratio, ok := snap.Float64("server.ratio")
+		assert.True(t, ok)
+		assert.Equal(t, 0.5, ratio)
+
+		debug, ok := snap.Bool("debug")
+		assert.True(t, ok)
+		assert.False(t, debug)
+
+		second, ok := snap.Get("server.hosts[1]")
+		assert.True(t, ok)
+		assert.Equal(t, "b", second)
+
+		_, ok = snap.String("server.port")
+		assert.False(t, ok, "wrong type")
+		_, ok = snap.Int64("server.timeout")
+		assert.False(t, ok, "durations are not plain integers")
+		_, ok = snap.Get("missing")
+		assert.False(t, ok)
+
+		assert.Equal(t, cfg.GetPrecedence(), snap.Precedence())
+	})
+
+	t.Run("StringValues", func(t *testing.T) {
+		env := New()
+		env.Register("server.port", int64(8080))
+		env.Register("server.timeout", 5*time.Second)
+		env.Register("debug", false)
+		env.Register("server.ratio", 0.5)
+		t.Setenv("APP_SERVER_PORT", "9090")
+		t.Setenv("APP_SERVER_TIMEOUT", "30s")
+		t.Setenv("APP_DEBUG", "true")
+		t.Setenv("APP_SERVER_RATIO", "0.75")
+		require.NoError(t, env.LoadEnv("APP_"))
+		snap := env.Snapshot()
+
+		port, ok := snap.Int64("server.port")
+		assert.True(t, ok)
+		assert.Equal(t, int64(9090), port)
+		timeout, ok := snap.Duration("server.timeout")
+		assert.True(t, ok)
+		assert.Equal(t, 30*time.Second, timeout)
+		debug, ok := snap.Bool("debug")
+		assert.True(t, ok)
+		assert.True(t, debug)
+		ratio, ok := snap.Float64("server.ratio")
+		assert.True(t, ok)
+		assert.Equal(t, 0.75, ratio)
+
+		typed, err := GetTyped[int64](env, "server.port")
+		require.NoError(t, err)
+		assert.Equal(t, typed, port, "snapshot and GetTyped agree")
+
+		_, ok = snap.Int64("server.timeout")
+		assert.False(t, ok, "30s is not an integer")
+		_, ok = snap.Bool("server.port")
+		assert.False(t, ok)
+	})
+
+	t.Run("StableAcrossSet", func(t *testing.T) {
+		snap := cfg.Snapshot()
+
+		require.NoError(t, cfg.Set("server.host", "changed"))
+		require.NoError(t, cfg.Set("server.hosts", []string{"x"}))
+		require.NoError(t, cfg.SetPrecedence(SourceEnv, SourceCLI, SourceFile, SourceDefault))
+
+		host, _ := snap.String("server.host")
+		assert.Equal(t, "localhost", host)
+		hosts, _ := snap.Get("server.hosts")
+		assert.Equal(t, []string{"a", "b"}, hosts)
+		assert.Equal(t, SourceCLI, snap.Precedence()[0])
+
+		fresh := cfg.Snapshot()
+		host, _ = fresh.String("server.host")
+		assert.Equal(t, "changed", host)
+		assert.NotEqual(t, snap.Version(), fresh.Version())
+	})
+
+	t.Run("IsolatedFromCallers", func(t *testing.T) {
+		snap := cfg.Snapshot()
+		hosts, _ := snap.Get("server.hosts")
+		hosts.([]string)[0] = "mutated"
+
+		current, _ := cfg.Get("server.hosts")
+		assert.Equal(t, []string{"x"}, current)
+	})
+}
+
+// BenchmarkSnapshotGet benchmarks lock-free snapshot reads against locked Get
+func BenchmarkSnapshotGet(b *testing.B) {
+	cfg := New()
+	paths := make([]string, 100)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("value%d", i)
+		cfg.Register(paths[i], int64(i))
+	}
+
+	b.Run("ConfigGet", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				_, _ = cfg.Get(paths[i%len(paths)])
+				i++
+			}
+		})
+	})
+
+	b.Run("SnapshotGet", func(b *testing.B) {
+		snap := cfg.Snapshot()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				_, _ = snap.Get(paths[i%len(paths)])
+				i++
+			}
+		})
+	})
+}
+
+// TestExportImport tests round-tripping source values through Export and Import
+func TestExportImport(t *testing.T) {
+	const sourceRemote Source = "remote"
+
+	cfg := New()
+	cfg.Register("server.port", 8080)
+	cfg.Register("server.host", "localhost")
+	cfg.Register("timeout", 30*time.Second)
+	cfg.Register("tags", []string{"a"})
+	cfg.Register("metadata", map[string]any{"team": "core"})
+	require.NoError(t, cfg.RegisterSource(sourceRemote))
+	require.NoError(t, cfg.SetPrecedence(SourceCLI, SourceEnv, sourceRemote, SourceFile, SourceDefault))
+
+	require.NoError(t, cfg.SetSource(SourceFile, "server.port", int64(9090)))
+	require.NoError(t, cfg.SetSource(SourceEnv, "server.port", "9191"))
+	require.NoError(t, cfg.SetSource(SourceCLI, "server.host", "cli-host"))
+	require.NoError(t, cfg.SetSource(sourceRemote, "timeout", time.Minute))
+	require.NoError(t, cfg.SetSource(SourceFile, "tags", []any{"x", "y"}))
+	require.NoError(t, cfg.SetSource(SourceFile, "metadata", map[string]any{"team": "edge", "zones": []any{"eu"}}))
+
+	var buf bytes.Buffer
+	require.NoError(t, cfg.Export(&buf))
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		restored := New()
+		restored.Register("server.port", 8080)
+		require.NoError(t, restored.Import(bytes.NewReader(buf.Bytes())))
+
+		assert.Equal(t, cfg.GetPrecedence(), restored.GetPrecedence())
+		for _, path := range []string{"server.port", "server.host", "timeout", "tags", "metadata"} {
+			assert.Equal(t, cfg.GetSources(path), restored.GetSources(path), path)
+
+			want, _ := cfg.Get(path)
+			got, registered := restored.Get(path)
+			assert.True(t, registered, path)
+			assert.Equal(t, want, got, path)
+		}
+
+		// Source timestamps and caches are restored
+		want, _ := cfg.SourceTimestamp("timeout", sourceRemote)
+		got, ok := restored.SourceTimestamp("timeout", sourceRemote)
+		assert.True(t, ok)
+		assert.True(t, want.Equal(got))
+		assert.Equal(t, "9191", restored.envData["server.port"])
+
+		// Unregistered paths take the exported default
+		require.NoError(t, restored.UnsetSource(SourceCLI, "server.host"))
+		host, _ := restored.Get("server.host")
+		assert.Equal(t, "localhost", host)
+	})
+
+	t.Run("ReplacesExistingValues", func(t *testing.T) {
+		restored := New()
+		restored.Register("server.port", 8080)
+		restored.Register("debug", false)
+		require.NoError(t, restored.SetSource(SourceCLI, "debug", true))
+		require.NoError(t, restored.Import(bytes.NewReader(buf.Bytes())))
+
+		debug, _ := restored.Get("debug")
+		assert.Equal(t, false, debug)
+		assert.Empty(t, restored.GetSources("debug"))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		assert.Error(t, New().Import(bytes.NewReader([]byte("not gob"))))
+
+		var future bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&future).Encode(exportData{Version: exportFormatVersion + 1}))
+		err := New().Import(&future)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported export format version")
+
+		unsupported := New()
+		unsupported.Register("pattern", regexp.MustCompile("a+"))
+		assert.Error(t, unsupported.Export(io.Discard))
+	})
+}
\ No newline at end of file
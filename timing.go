@@ -13,6 +13,7 @@ const (
 	DefaultDebounce      = 500 * time.Millisecond // File change coalescence period
 	DefaultPollInterval  = time.Second            // Standard file monitoring frequency
 	DefaultReloadTimeout = 5 * time.Second        // Maximum duration for reload operations
+	MaxPollBackoff       = 30 * time.Second       // Cap on poll delay after repeated stat errors
 )
 
 // Derived timing relationships for internal use.
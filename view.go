@@ -0,0 +1,75 @@
+// FILE: lixenwraith/config/view.go
+package config
+
+// ConfigView is a scoped handle on a Config that prepends a path prefix.
+// It shares storage with its parent, so writes through the view are visible in the parent.
+type ConfigView struct {
+	config *Config
+	prefix string
+}
+
+// Subtree returns a view scoped to prefix, where view.Get("port") reads "<prefix>.port"
+func (c *Config) Subtree(prefix string) *ConfigView {
+	return &ConfigView{config: c, prefix: prefix}
+}
+
+// Subtree returns a nested view scoped to prefix within this view
+func (v *ConfigView) Subtree(prefix string) *ConfigView {
+	return &ConfigView{config: v.config, prefix: v.fullPath(prefix)}
+}
+
+// Prefix returns the full path prefix of the view
+func (v *ConfigView) Prefix() string {
+	return v.prefix
+}
+
+// Config returns the underlying Config
+func (v *ConfigView) Config() *Config {
+	return v.config
+}
+
+// Get retrieves the merged value for a path relative to the view
+func (v *ConfigView) Get(path string) (any, bool) {
+	return v.config.Get(v.fullPath(path))
+}
+
+// GetSource retrieves a value from a specific source for a path relative to the view
+func (v *ConfigView) GetSource(path string, source Source) (any, bool) {
+	return v.config.GetSource(v.fullPath(path), source)
+}
+
+// GetSources returns all sources that have a value for a path relative to the view
+func (v *ConfigView) GetSources(path string) map[Source]any {
+	return v.config.GetSources(v.fullPath(path))
+}
+
+// Set updates a value relative to the view in the highest priority source
+func (v *ConfigView) Set(path string, value any) error {
+	return v.config.Set(v.fullPath(path), value)
+}
+
+// SetSource sets a value for a specific source relative to the view
+func (v *ConfigView) SetSource(source Source, path string, value any) error {
+	return v.config.SetSource(source, v.fullPath(path), value)
+}
+
+// Scan decodes the view's section, or a subsection of it, into target
+func (v *ConfigView) Scan(target any, basePath ...string) error {
+	path := ""
+	if len(basePath) > 0 {
+		path = basePath[0]
+	}
+	return v.config.Scan(target, v.fullPath(path))
+}
+
+// fullPath prepends the view prefix to a relative path
+func (v *ConfigView) fullPath(path string) string {
+	switch {
+	case v.prefix == "":
+		return path
+	case path == "":
+		return v.prefix
+	default:
+		return v.prefix + "." + path
+	}
+}
\ No newline at end of file
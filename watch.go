@@ -3,9 +3,13 @@ package config
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"os"
 	"reflect"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,6 +22,10 @@ type WatchOptions struct {
 	// PollInterval for file stat checks (minimum 100ms)
 	PollInterval time.Duration
 
+	// PollJitter adds a random delay in [0, PollJitter] to each poll so that many
+	// instances watching a shared filesystem do not stat in lockstep
+	PollJitter time.Duration
+
 	// Debounce duration to avoid rapid reloads
 	Debounce time.Duration
 
@@ -29,8 +37,39 @@ type WatchOptions struct {
 
 	// VerifyPermissions checks file hasn't been replaced with different permissions
 	VerifyPermissions bool
+
+	// PermissionPolicy applies when VerifyPermissions detects a change (default PermissionBlock)
+	PermissionPolicy PermissionPolicy
+
+	// UseContentHash reloads only when a SHA-256 of the file content changes, ignoring
+	// mtime-only touches and catching edits that keep mtime and size. Each poll reads the
+	// file, up to the MaxFileSize security option when set.
+	UseContentHash bool
+
+	// InitialEvent delivers every registered path, in sorted order, on a newly
+	// subscribed channel before any change, so one handler covers startup and updates
+	InitialEvent bool
+
+	// RefreshTarget repopulates the WithTarget struct in place after each reload, before
+	// subscribers are notified, so holders of the original pointer see new values.
+	// Read it through RLockTarget; unsynchronized reads race with the refresh.
+	RefreshTarget bool
 }
 
+// PermissionPolicy selects how the watcher reacts when group or world permission
+// bits of the watched file change
+type PermissionPolicy int
+
+const (
+	// PermissionBlock sends "permissions_changed" and skips reloading until the
+	// permissions are restored (default)
+	PermissionBlock PermissionPolicy = iota
+	// PermissionWarn sends "permissions_changed" once and keeps reloading
+	PermissionWarn
+	// PermissionIgnore accepts the new permissions silently
+	PermissionIgnore
+)
+
 // DefaultWatchOptions returns sensible defaults for file watching
 func DefaultWatchOptions() WatchOptions {
 	return WatchOptions{
@@ -52,11 +91,48 @@ type watcher struct {
 	lastModTime      time.Time
 	lastSize         int64
 	lastMode         os.FileMode
+	lastHash         [sha256.Size]byte
 	watching         atomic.Bool
 	reloadInProgress atomic.Bool
+	reloads          sync.WaitGroup        // In-flight reloads, including their load goroutines
 	watchers         map[int64]chan string // subscriber channels
+	events           map[int64]chan ChangeEvent
 	watcherID        atomic.Int64
 	debounceTimer    *time.Timer
+	log              func() Logger // Config.logger, so SetLogger applies to a running watcher
+
+	// Counters reported by WatchStats
+	reloadCount  atomic.Int64
+	reloadErrors atomic.Int64
+	coalesced    atomic.Int64
+	dropped      atomic.Int64
+	lastReload   atomic.Int64 // UnixNano of the last successful reload
+}
+
+// Causes reported in ChangeEvent.Cause
+const (
+	ChangeCauseReload     = "reload"     // The watched file, or the env after SetLoadOptions with ReloadEnv, was reloaded
+	ChangeCausePrecedence = "precedence" // SetPrecedence or SetLoadOptions reordered sources
+	ChangeCauseRemote     = "remote"     // A remote source fetched or pushed new values
+)
+
+// ChangeEvent describes a change of a path's resolved value
+type ChangeEvent struct {
+	Path     string
+	OldValue any
+	NewValue any
+	Cause    string // ChangeCauseReload, ChangeCausePrecedence, or ChangeCauseRemote
+}
+
+// WatchStats reports activity of the current file watcher. Counters start at zero
+// each time watching starts.
+type WatchStats struct {
+	Reloads              int64     // Successful reloads
+	ReloadErrors         int64     // Reloads that failed or timed out
+	Coalesced            int64     // Changes merged into a pending reload by debouncing
+	DroppedNotifications int64     // Notifications skipped because a subscriber channel was full
+	LastReload           time.Time // Time of the last successful reload, zero if none
+	Subscribers          int       // Active watch channels
 }
 
 // configWatcher extends Config with watching capabilities
@@ -76,6 +152,9 @@ func (c *Config) AutoUpdateWithOptions(opts WatchOptions) {
 	if opts.PollInterval < MinPollInterval {
 		opts.PollInterval = MinPollInterval
 	}
+	if opts.PollJitter < 0 {
+		opts.PollJitter = 0
+	}
 	if opts.MaxWatchers <= 0 {
 		opts.MaxWatchers = 100
 	}
@@ -88,8 +167,8 @@ func (c *Config) AutoUpdateWithOptions(opts WatchOptions) {
 
 	// Get path of current file to watch
 	filePath := c.getConfigFilePath()
-	if filePath == "" {
-		// No file configured, nothing to watch
+	if filePath == "" && len(c.remotes) == 0 {
+		// No file or remote source configured, nothing to watch
 		return
 	}
 
@@ -107,7 +186,14 @@ func (c *Config) AutoUpdateWithOptions(opts WatchOptions) {
 			cancel:   cancel,
 			opts:     opts,
 			filePath: filePath,
+			log:      c.logger,
 			watchers: make(map[int64]chan string),
+			events:   make(map[int64]chan ChangeEvent),
+		}
+
+		// Without a file, the watcher only relays remote source changes
+		if filePath == "" {
+			return
 		}
 
 		// Get initial file state
@@ -116,21 +202,63 @@ func (c *Config) AutoUpdateWithOptions(opts WatchOptions) {
 			c.watcher.lastSize = info.Size()
 			c.watcher.lastMode = info.Mode()
 		}
+		if opts.UseContentHash {
+			maxSize := int64(0)
+			if c.securityOpts != nil {
+				maxSize = c.securityOpts.MaxFileSize
+			}
+			if sum, err := hashFile(filePath, maxSize); err == nil {
+				c.watcher.lastHash = sum
+			}
+		}
 
 		// Start watching
 		go c.watcher.watchLoop(c)
 	}
 }
 
-// StopAutoUpdate stops automatic configuration reloading
+// StopAutoUpdate stops automatic configuration reloading.
+// A reload already in progress may still complete after it returns; use
+// StopAutoUpdateAndWait to wait for it.
 func (c *Config) StopAutoUpdate() {
+	c.detachWatcher()
+}
+
+// StopAutoUpdateAndWait stops automatic reloading like StopAutoUpdate, then waits up to
+// timeout for any in-flight reload to finish, so no reload modifies the configuration
+// after it returns nil.
+func (c *Config) StopAutoUpdateAndWait(timeout time.Duration) error {
+	w := c.detachWatcher()
+	if w == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		w.reloads.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out after %v waiting for in-flight reload", timeout)
+	}
+}
+
+// detachWatcher removes the current watcher and stops it outside the config lock,
+// so a poll or reload waiting on the lock can finish. Returns the stopped watcher.
+func (c *Config) detachWatcher() *watcher {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	w := c.watcher
+	c.watcher = nil
+	c.mutex.Unlock()
 
-	if c.watcher != nil {
-		c.watcher.stop()
-		c.watcher = nil
+	if w != nil {
+		w.stop()
 	}
+	return w
 }
 
 // Watch returns a channel that receives paths of changed configuration values
@@ -172,38 +300,209 @@ func (c *Config) WatchFile(filePath string, formatHint ...string) error {
 // WatchWithOptions returns a channel with custom watch options
 // should not restart the watcher if it's already running with the same file
 func (c *Config) WatchWithOptions(opts WatchOptions) <-chan string {
+	watcher := c.activeWatcher(opts)
+	if watcher == nil {
+		// No file to watch, return closed channel
+		ch := make(chan string)
+		close(ch)
+		return ch
+	}
+
+	var initial []string
+	if opts.InitialEvent {
+		for path := range c.GetRegisteredPaths() {
+			initial = append(initial, path)
+		}
+		sort.Strings(initial)
+	}
+
+	return watcher.subscribe(initial)
+}
+
+// WatchContext returns a channel like Watch that is closed and unsubscribed when ctx
+// is done. If the call started the watcher, the watcher is stopped with it.
+func (c *Config) WatchContext(ctx context.Context) <-chan string {
 	c.mutex.RLock()
-	watcher := c.watcher
-	filePath := c.configFilePath
+	existing := c.watcher
 	c.mutex.RUnlock()
 
-	// If no file configured, return closed channel
-	if filePath == "" {
+	watcher := c.activeWatcher(DefaultWatchOptions())
+	if watcher == nil {
 		ch := make(chan string)
 		close(ch)
 		return ch
 	}
 
-	// If watcher exists and is watching the current file, just subscribe
+	ch := addSubscriber(watcher, watcher.watchers, nil, ctx.Done())
+
+	if watcher != existing {
+		go func() {
+			select {
+			case <-ctx.Done():
+				c.stopWatcher(watcher)
+			case <-watcher.ctx.Done():
+			}
+		}()
+	}
+	return ch
+}
+
+// stopWatcher stops w if it is still the current watcher
+func (c *Config) stopWatcher(w *watcher) {
+	c.mutex.Lock()
+	if c.watcher != w {
+		c.mutex.Unlock()
+		return
+	}
+	c.watcher = nil
+	c.mutex.Unlock()
+
+	w.stop()
+}
+
+// WatchEvents returns a channel of ChangeEvent values carrying the old and new resolved
+// value of each changed path, for file reloads and precedence changes. Status
+// notifications such as "file_deleted" are only sent on Watch channels.
+func (c *Config) WatchEvents() <-chan ChangeEvent {
+	watcher := c.activeWatcher(DefaultWatchOptions())
+	if watcher == nil {
+		ch := make(chan ChangeEvent)
+		close(ch)
+		return ch
+	}
+	return watcher.subscribeEvents()
+}
+
+// activeWatcher returns the watcher for the current config file, starting one with
+// opts if needed. Returns nil when neither a file nor a remote source is configured.
+func (c *Config) activeWatcher(opts WatchOptions) *watcher {
+	c.mutex.RLock()
+	watcher := c.watcher
+	filePath := c.configFilePath
+	hasRemotes := len(c.remotes) > 0
+	c.mutex.RUnlock()
+
+	if filePath == "" && !hasRemotes {
+		return nil
+	}
+
+	// If watcher exists and is watching the current file, reuse it
 	if watcher != nil && watcher.filePath == filePath && watcher.watching.Load() {
-		return watcher.subscribe()
+		return watcher
 	}
 
-	// First ensure auto-update is running
+	// Ensure auto-update is running
 	c.AutoUpdateWithOptions(opts)
 
 	c.mutex.RLock()
-	watcher = c.watcher
+	defer c.mutex.RUnlock()
+	return c.watcher
+}
+
+// WatchStruct returns a channel that receives a freshly decoded *T after each reload
+// that changes configuration values. Status notifications such as "file_deleted" do
+// not produce a snapshot. A slow consumer only sees the latest snapshot. The channel
+// closes when auto-update stops. A reload whose values do not decode into T sends
+// nothing and is reported through the logger.
+func WatchStruct[T any](c *Config) <-chan *T {
+	changes := c.Watch()
+	out := make(chan *T, 1)
+	lastVersion := c.version.Load()
+
+	go func() {
+		defer close(out)
+		for range changes {
+			// One reload notifies once per changed path; decode once per version
+			version := c.version.Load()
+			if version == lastVersion {
+				continue
+			}
+			lastVersion = version
+
+			snapshot, err := decodeStructSnapshot[T](c)
+			if err != nil {
+				c.logger().Errorf("config watcher: decode of %T snapshot failed: %v", snapshot, err)
+				continue
+			}
+
+			// Replace an unread snapshot rather than block the watcher
+			select {
+			case out <- snapshot:
+			default:
+				select {
+				case <-out:
+				default:
+				}
+				out <- snapshot
+			}
+		}
+	}()
+
+	return out
+}
+
+// decodeStructSnapshot returns a copy of the WithTarget struct when it is a *T,
+// refreshing the struct cache as AsStruct does. Other types are decoded afresh.
+func decodeStructSnapshot[T any](c *Config) (*T, error) {
+	if c.structCache != nil {
+		if target, ok := c.structCache.target.(*T); ok {
+			if err := c.populateStruct(); err != nil {
+				return nil, err
+			}
+			// Decoding allocates new slices and maps, so the copy shares none with later refreshes
+			c.structCache.mu.RLock()
+			snapshot := *target
+			c.structCache.mu.RUnlock()
+			return &snapshot, nil
+		}
+	}
+
+	snapshot := new(T)
+	if err := c.unmarshal("", snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// ChangedFields compares two struct snapshots of the same type and returns the sorted
+// dotted paths whose values differ, using the same tag-to-path mapping as RegisterStruct.
+// Returns nil if the values are not structs (or pointers to structs) of the same type.
+func (c *Config) ChangedFields(old, new any) []string {
+	oldValue := reflect.Indirect(reflect.ValueOf(old))
+	newValue := reflect.Indirect(reflect.ValueOf(new))
+	if oldValue.Kind() != reflect.Struct || newValue.Kind() != reflect.Struct || oldValue.Type() != newValue.Type() {
+		return nil
+	}
+
+	c.mutex.RLock()
+	tagName := c.tagName
 	c.mutex.RUnlock()
 
-	if watcher == nil {
-		// No file to watch, return closed channel
-		ch := make(chan string)
-		close(ch)
-		return ch
+	collect := func(v reflect.Value) map[string]any {
+		values := make(map[string]any)
+		walkStructFields(v, "", "", tagName, func(f structField) {
+			values[f.path] = f.value.Interface()
+		})
+		return values
 	}
+	oldValues := collect(oldValue)
+	newValues := collect(newValue)
 
-	return watcher.subscribe()
+	var changed []string
+	for path, newVal := range newValues {
+		if oldVal, existed := oldValues[path]; !existed || !reflect.DeepEqual(oldVal, newVal) {
+			changed = append(changed, path)
+		}
+	}
+	// Paths only present in old, such as fields under a pointer that became nil
+	for path := range oldValues {
+		if _, exists := newValues[path]; !exists {
+			changed = append(changed, path)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
 }
 
 // IsWatching returns true if auto-update is enabled
@@ -224,7 +523,41 @@ func (c *Config) WatcherCount() int {
 
 	c.watcher.mu.RLock()
 	defer c.watcher.mu.RUnlock()
-	return len(c.watcher.watchers)
+	return c.watcher.subscriberCount()
+}
+
+// WatchStats returns counters for the current watcher, or zero stats when not watching
+func (c *Config) WatchStats() WatchStats {
+	c.mutex.RLock()
+	w := c.watcher
+	c.mutex.RUnlock()
+
+	if w == nil {
+		return WatchStats{}
+	}
+
+	stats := WatchStats{
+		Reloads:              w.reloadCount.Load(),
+		ReloadErrors:         w.reloadErrors.Load(),
+		Coalesced:            w.coalesced.Load(),
+		DroppedNotifications: w.dropped.Load(),
+	}
+	if nanos := w.lastReload.Load(); nanos != 0 {
+		stats.LastReload = time.Unix(0, nanos)
+	}
+
+	w.mu.RLock()
+	stats.Subscribers = w.subscriberCount()
+	w.mu.RUnlock()
+	return stats
+}
+
+// logger returns the config's logger, or a no-op logger for a watcher built without one
+func (w *watcher) logger() Logger {
+	if w.log == nil {
+		return nopLogger{}
+	}
+	return w.log()
 }
 
 // watchLoop is the main file watching loop
@@ -234,67 +567,127 @@ func (w *watcher) watchLoop(c *Config) {
 	}
 	defer w.watching.Store(false)
 
-	ticker := time.NewTicker(w.opts.PollInterval)
-	defer ticker.Stop()
+	// Consecutive stat failures, used to back off polling
+	failures := 0
+	timer := time.NewTimer(w.pollDelay(failures))
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-w.ctx.Done():
 			return
-		case <-ticker.C:
-			w.checkAndReload(c)
+		case <-timer.C:
+			if err := w.checkAndReload(c); err != nil {
+				// Report the first failure; the loop backs off while it persists
+				if failures == 0 {
+					w.logger().Warnf("config watcher: cannot check %s: %v", w.filePath, err)
+				} else {
+					w.logger().Debugf("config watcher: cannot check %s: %v (attempt %d)", w.filePath, err, failures+1)
+				}
+				failures++
+			} else {
+				failures = 0
+			}
+			timer.Reset(w.pollDelay(failures))
 		}
 	}
 }
 
-// checkAndReload checks if file changed and triggers reload
-func (w *watcher) checkAndReload(c *Config) {
-	info, err := os.Stat(w.filePath)
+// pollDelay returns the wait before the next poll: PollInterval doubled for each
+// consecutive stat failure up to MaxPollBackoff, plus random jitter
+func (w *watcher) pollDelay(failures int) time.Duration {
+	limit := max(MaxPollBackoff, w.opts.PollInterval)
+	delay := w.opts.PollInterval
+	for i := 0; i < failures && delay < limit; i++ {
+		delay *= 2
+	}
+	delay = min(delay, limit)
+
+	if w.opts.PollJitter > 0 {
+		delay += rand.N(w.opts.PollJitter + 1)
+	}
+	return delay
+}
+
+// watchStat is the stat function used by the poll loop, replaceable in tests
+var watchStat = os.Stat
+
+// checkAndReload checks if file changed and triggers reload.
+// Returns the stat error, if any, so the poll loop can back off.
+func (w *watcher) checkAndReload(c *Config) error {
+	info, err := watchStat(w.filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// File was deleted, notify watchers
 			w.notifyWatchers("file_deleted")
 		}
-		return
+		return err
 	}
 
 	// Check for changes
 	changed := false
+	var sum [sha256.Size]byte
+
+	if w.opts.UseContentHash {
+		// Compare content, ignoring mod-time and size
+		c.mutex.RLock()
+		maxSize := int64(0)
+		if c.securityOpts != nil {
+			maxSize = c.securityOpts.MaxFileSize
+		}
+		c.mutex.RUnlock()
 
-	// Compare modification time and size
-	if !info.ModTime().Equal(w.lastModTime) || info.Size() != w.lastSize {
+		sum, err = hashFile(w.filePath, maxSize)
+		if err != nil {
+			return err
+		}
+		changed = sum != w.lastHash
+	} else if !info.ModTime().Equal(w.lastModTime) || info.Size() != w.lastSize {
+		// Compare modification time and size
 		changed = true
 	}
 
 	// SECURITY: Verify permissions haven't changed suspiciously
 	if w.opts.VerifyPermissions && w.lastMode != 0 {
-		if info.Mode() != w.lastMode {
-			// Permission change detected
-			if (info.Mode() & 0077) != (w.lastMode & 0077) {
-				// World/group permissions changed - potential security issue
+		// World/group permissions changed - potential security issue
+		if (info.Mode() & 0077) != (w.lastMode & 0077) {
+			switch w.opts.PermissionPolicy {
+			case PermissionIgnore:
+			case PermissionWarn:
+				w.logger().Warnf("config watcher: permissions of %s changed from %v to %v", w.filePath, w.lastMode, info.Mode())
+				w.notifyWatchers("permissions_changed")
+			default:
+				w.logger().Warnf("config watcher: permissions of %s changed from %v to %v, not reloading", w.filePath, w.lastMode, info.Mode())
 				w.notifyWatchers("permissions_changed")
 				// Don't reload on permission change for security
-				return
+				return nil
 			}
+			// Accept the new permissions so the change is reported once
+			w.lastMode = info.Mode()
 		}
 	}
 
 	if changed {
-		// Update tracked state
+		// Update tracked state; a blocked change above is seen again on the next poll
+		if w.opts.UseContentHash {
+			w.lastHash = sum
+		}
 		w.lastModTime = info.ModTime()
 		w.lastSize = info.Size()
 		w.lastMode = info.Mode()
 
 		// Debounce rapid changes
 		w.mu.Lock()
-		if w.debounceTimer != nil {
-			w.debounceTimer.Stop()
+		if w.debounceTimer != nil && w.debounceTimer.Stop() {
+			// A reload was still pending; this change joins it
+			w.coalesced.Add(1)
 		}
 		w.debounceTimer = time.AfterFunc(w.opts.Debounce, func() {
 			w.performReload(c)
 		})
 		w.mu.Unlock()
 	}
+	return nil
 }
 
 // performReload reloads the configuration file
@@ -305,6 +698,17 @@ func (w *watcher) performReload(c *Config) {
 	}
 	defer w.reloadInProgress.Store(false)
 
+	// Register with the wait group unless stop has begun; stop cancels before taking
+	// w.mu, so no reload is added once it has passed that point
+	w.mu.Lock()
+	if w.ctx.Err() != nil {
+		w.mu.Unlock()
+		return
+	}
+	w.reloads.Add(1)
+	w.mu.Unlock()
+	defer w.reloads.Done()
+
 	// Create a timeout context for reload
 	ctx, cancel := context.WithTimeout(w.ctx, w.opts.ReloadTimeout)
 	defer cancel()
@@ -314,62 +718,96 @@ func (w *watcher) performReload(c *Config) {
 
 	// Reload file in a goroutine with timeout
 	done := make(chan error, 1)
+	w.reloads.Add(1)
 	go func() {
-		done <- c.loadFile(w.filePath)
+		defer w.reloads.Done()
+		done <- c.loadFile(w.filePath, nil)
 	}()
 
 	select {
 	case err := <-done:
 		if err != nil {
 			// Reload failed, notify error
+			w.reloadErrors.Add(1)
+			w.logger().Errorf("config watcher: reload of %s failed: %v", w.filePath, err)
 			w.notifyWatchers(fmt.Sprintf("reload_error:%v", err))
 			return
 		}
+		w.reloadCount.Add(1)
+		w.lastReload.Store(time.Now().UnixNano())
+		w.logger().Debugf("config watcher: reloaded %s", w.filePath)
+
+		if w.opts.RefreshTarget && c.structCache != nil {
+			if err := c.populateStruct(); err != nil {
+				w.logger().Errorf("config watcher: refreshing target after reload of %s failed: %v", w.filePath, err)
+			}
+		}
 
 		// Compare and notify changes
 		newValues := c.snapshot()
 		for path, newVal := range newValues {
 			if oldVal, existed := oldValues[path]; !existed || !reflect.DeepEqual(oldVal, newVal) {
-				w.notifyWatchers(path)
+				w.notifyChange(ChangeEvent{Path: path, OldValue: oldVal, NewValue: newVal, Cause: ChangeCauseReload})
 			}
 		}
 
 		// Check for deletions
-		for path := range oldValues {
+		for path, oldVal := range oldValues {
 			if _, exists := newValues[path]; !exists {
-				w.notifyWatchers(path)
+				w.notifyChange(ChangeEvent{Path: path, OldValue: oldVal, Cause: ChangeCauseReload})
 			}
 		}
 
 	case <-ctx.Done():
-		// Reload timeout
-		w.notifyWatchers("reload_timeout")
+		// Reload timeout, unless the watcher is stopping
+		if w.ctx.Err() == nil {
+			w.reloadErrors.Add(1)
+			w.logger().Errorf("config watcher: reload of %s timed out after %v", w.filePath, w.opts.ReloadTimeout)
+			w.notifyWatchers("reload_timeout")
+		}
 	}
 }
 
-// subscribe creates a new watcher channel
-func (w *watcher) subscribe() <-chan string {
+// subscribe creates a new watcher channel, queueing the initial paths ahead of any change
+func (w *watcher) subscribe(initial []string) <-chan string {
+	return addSubscriber(w, w.watchers, initial, nil)
+}
+
+// subscribeEvents creates a new ChangeEvent channel
+func (w *watcher) subscribeEvents() <-chan ChangeEvent {
+	return addSubscriber(w, w.events, nil, nil)
+}
+
+// addSubscriber registers a channel in subs, closed when the watcher stops or done
+// is closed. Path and event subscribers share the MaxWatchers limit.
+func addSubscriber[T any](w *watcher, subs map[int64]chan T, initial []T, done <-chan struct{}) <-chan T {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
 	// Check watcher limit
-	if len(w.watchers) >= w.opts.MaxWatchers {
+	if w.subscriberCount() >= w.opts.MaxWatchers {
 		// Return closed channel to prevent resource exhaustion
-		ch := make(chan string)
+		ch := make(chan T)
 		close(ch)
 		return ch
 	}
 
-	// Create buffered channel to prevent blocking
-	ch := make(chan string, 10)
+	// Create buffered channel to prevent blocking, large enough for the initial values
+	ch := make(chan T, max(10, len(initial)))
+	for _, value := range initial {
+		ch <- value
+	}
 	id := w.watcherID.Add(1)
-	w.watchers[id] = ch
+	subs[id] = ch
 
 	// Cleanup goroutine
 	go func() {
-		<-w.ctx.Done()
+		select {
+		case <-w.ctx.Done():
+		case <-done:
+		}
 		w.mu.Lock()
-		delete(w.watchers, id)
+		delete(subs, id)
 		close(ch)
 		w.mu.Unlock()
 	}()
@@ -377,23 +815,54 @@ func (w *watcher) subscribe() <-chan string {
 	return ch
 }
 
+// subscriberCount returns the number of path and event channels. Caller must hold w.mu.
+func (w *watcher) subscriberCount() int {
+	return len(w.watchers) + len(w.events)
+}
+
 // notifyWatchers sends change notification to all subscribers
 func (w *watcher) notifyWatchers(path string) {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
-	for id, ch := range w.watchers {
+	for _, ch := range w.watchers {
 		select {
 		case ch <- path:
 			// Sent successfully
 		default:
-			// Channel full or closed, skip
-			// Could implement removal of dead watchers here
-			_ = id
+			// Channel full, skip
+			w.dropped.Add(1)
+			w.logger().Warnf("config watcher: dropped notification %q, subscriber channel full", path)
 		}
 	}
 }
 
+// notifyEvent sends a change event to all event subscribers
+func (w *watcher) notifyEvent(event ChangeEvent) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, ch := range w.events {
+		select {
+		case ch <- event:
+		default:
+			w.dropped.Add(1)
+			w.logger().Warnf("config watcher: dropped change event for %s, subscriber channel full", event.Path)
+		}
+	}
+}
+
+// notifyChange sends a value change as a path notification and as a ChangeEvent.
+// Precedence changes use the "precedence:" prefix on the path notification.
+func (w *watcher) notifyChange(event ChangeEvent) {
+	if event.Cause == ChangeCausePrecedence {
+		w.notifyWatchers("precedence:" + event.Path)
+	} else {
+		w.notifyWatchers(event.Path)
+	}
+	w.notifyEvent(event)
+}
+
 // stop terminates the watcher
 func (w *watcher) stop() {
 	if w.cancel != nil {
@@ -415,6 +884,29 @@ func (w *watcher) stop() {
 	}
 }
 
+// hashFile returns the SHA-256 of the file content, reading at most maxSize bytes when positive
+func hashFile(path string, maxSize int64) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+
+	file, err := os.Open(path)
+	if err != nil {
+		return sum, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if maxSize > 0 {
+		reader = io.LimitReader(file, maxSize)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, reader); err != nil {
+		return sum, err
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
 // getConfigFilePath returns the current config file path
 func (c *Config) getConfigFilePath() string {
 	// Access the tracked config file path
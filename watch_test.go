@@ -2,10 +2,12 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -173,6 +175,62 @@ func TestWatchFileDeleted(t *testing.T) {
 	}
 }
 
+// TestWatchInitialEvent tests delivery of current paths on subscribe
+func TestWatchInitialEvent(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "test.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`key00 = "loaded"`), 0644))
+
+	cfg := New()
+	// More paths than the default channel buffer
+	var expected []string
+	for i := 0; i < 12; i++ {
+		path := fmt.Sprintf("key%02d", i)
+		cfg.Register(path, "default")
+		expected = append(expected, path)
+	}
+	require.NoError(t, cfg.LoadFile(configPath))
+
+	opts := WatchOptions{
+		PollInterval: testPollInterval,
+		Debounce:     testDebounce,
+		InitialEvent: true,
+	}
+	changes := cfg.WatchWithOptions(opts)
+	defer cfg.StopAutoUpdate()
+
+	// Current paths arrive before any file change, in sorted order
+	var received []string
+	for range expected {
+		select {
+		case path := <-changes:
+			received = append(received, path)
+		case <-time.After(testEventuallyTimeout):
+			t.Fatal("Timeout waiting for initial events")
+		}
+	}
+	assert.Equal(t, expected, received)
+	val, _ := cfg.Get("key00")
+	assert.Equal(t, "loaded", val)
+
+	// Later subscribers without the option get no initial events
+	plain := cfg.Watch()
+	select {
+	case path := <-plain:
+		t.Errorf("Unexpected event %q without InitialEvent", path)
+	case <-time.After(testPollWindow):
+	}
+
+	// Changes follow on the same channel
+	require.NoError(t, os.WriteFile(configPath, []byte(`key00 = "updated"`), 0644))
+	select {
+	case path := <-changes:
+		assert.Equal(t, "key00", path)
+	case <-time.After(testWatchTimeout):
+		t.Error("Timeout waiting for change notification")
+	}
+}
+
 // TestWatchPermissionChange tests permission change detection
 func TestWatchPermissionChange(t *testing.T) {
 	// Skip on Windows where permission model is different
@@ -487,6 +545,224 @@ func TestStopAutoUpdate(t *testing.T) {
 	cfg.StopAutoUpdate()
 }
 
+// TestStopAutoUpdateAndWait tests that stopping waits for in-flight reloads
+func TestStopAutoUpdateAndWait(t *testing.T) {
+	t.Run("NoReloadAfterWait", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+
+		cfg := New()
+		cfg.Register("test", "default")
+		require.NoError(t, cfg.LoadFile(configPath))
+
+		changes := cfg.WatchWithOptions(WatchOptions{
+			PollInterval: testPollInterval,
+			Debounce:     testDebounce,
+		})
+
+		// Stop around the time the reload starts
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "changed"`), 0644))
+		time.Sleep(testPollInterval + testDebounce)
+		require.NoError(t, cfg.StopAutoUpdateAndWait(testReloadTimeout))
+		assert.False(t, cfg.IsWatching())
+
+		version := cfg.version.Load()
+		value, _ := cfg.Get("test")
+
+		// Drain notifications already queued; the channel then closes with nothing more
+		timeout := time.After(testWatchTimeout)
+	drain:
+		for {
+			select {
+			case _, ok := <-changes:
+				if !ok {
+					break drain
+				}
+			case <-timeout:
+				t.Fatal("Channel not closed after stop")
+			}
+		}
+
+		time.Sleep(testPollWindow)
+		assert.Equal(t, version, cfg.version.Load(), "no reload should modify config after the wait")
+		after, _ := cfg.Get("test")
+		assert.Equal(t, value, after)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+
+		cfg := New()
+		cfg.Register("test", "default")
+		require.NoError(t, cfg.LoadFile(configPath))
+		cfg.AutoUpdate()
+		waitForWatchingState(t, cfg, true, "Watcher should be active")
+
+		// Simulate a reload that outlives the timeout
+		w := cfg.watcher
+		w.reloads.Add(1)
+		err := cfg.StopAutoUpdateAndWait(testDebounce)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "in-flight reload")
+		w.reloads.Done()
+	})
+
+	t.Run("NotWatching", func(t *testing.T) {
+		assert.NoError(t, New().StopAutoUpdateAndWait(testDebounce))
+	})
+}
+
+// TestWatchStruct tests typed snapshots delivered on reload
+func TestWatchStruct(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "test.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+[server]
+port = 8080
+host = "localhost"
+`), 0644))
+
+	type AppConfig struct {
+		Server struct {
+			Port int    `toml:"port"`
+			Host string `toml:"host"`
+		} `toml:"server"`
+	}
+
+	cfg, err := NewBuilder().
+		WithTarget(&AppConfig{}).
+		WithFile(configPath).
+		Build()
+	require.NoError(t, err)
+
+	cfg.AutoUpdateWithOptions(WatchOptions{
+		PollInterval: testPollInterval,
+		Debounce:     testDebounce,
+	})
+
+	snapshots := WatchStruct[AppConfig](cfg)
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+[server]
+port = 9090
+host = "example.com"
+`), 0644))
+
+	select {
+	case snapshot := <-snapshots:
+		require.NotNil(t, snapshot)
+		assert.Equal(t, 9090, snapshot.Server.Port)
+		assert.Equal(t, "example.com", snapshot.Server.Host)
+	case <-time.After(testWatchTimeout):
+		t.Fatal("Timeout waiting for typed snapshot")
+	}
+
+	// Channel closes when auto-update stops
+	cfg.StopAutoUpdate()
+	select {
+	case _, ok := <-snapshots:
+		assert.False(t, ok, "Channel should be closed after stop")
+	case <-time.After(testWatchTimeout):
+		t.Error("Timeout waiting for channel close")
+	}
+}
+
+// TestRefreshTarget tests in-place repopulation of the target struct on reload
+func TestRefreshTarget(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte("[server]\nport = 8080\n"), 0644))
+
+	type AppConfig struct {
+		Server struct {
+			Port int `toml:"port"`
+		} `toml:"server"`
+	}
+
+	held := &AppConfig{}
+	cfg, err := NewBuilder().
+		WithTarget(held).
+		WithFile(configPath).
+		Build()
+	require.NoError(t, err)
+
+	changes := cfg.WatchWithOptions(WatchOptions{
+		PollInterval:  testPollInterval,
+		Debounce:      testDebounce,
+		RefreshTarget: true,
+	})
+	defer cfg.StopAutoUpdate()
+
+	require.NoError(t, os.WriteFile(configPath, []byte("[server]\nport = 9090\n"), 0644))
+	select {
+	case path := <-changes:
+		assert.Equal(t, "server.port", path)
+	case <-time.After(testWatchTimeout):
+		t.Fatal("Timeout waiting for reload")
+	}
+
+	// Refreshed by the watcher before notifying, without an AsStruct call
+	cfg.structCache.mu.RLock()
+	assert.Equal(t, 9090, held.Server.Port)
+	cfg.structCache.mu.RUnlock()
+
+	var port int
+	require.NoError(t, RLockTarget(cfg, func(target *AppConfig) {
+		assert.Same(t, held, target)
+		port = target.Server.Port
+	}))
+	assert.Equal(t, 9090, port)
+
+	// Changes made outside a reload are applied by RLockTarget
+	require.NoError(t, cfg.Set("server.port", 7070))
+	require.NoError(t, RLockTarget(cfg, func(target *AppConfig) {
+		port = target.Server.Port
+	}))
+	assert.Equal(t, 7070, port)
+
+	err = RLockTarget(cfg, func(*struct{ Other int }) {})
+	assert.ErrorContains(t, err, "target struct is")
+	assert.Error(t, RLockTarget(New(), func(*AppConfig) {}))
+}
+
+// TestChangedFields tests field-level comparison of struct snapshots
+func TestChangedFields(t *testing.T) {
+	type TLS struct {
+		Enabled bool   `toml:"enabled"`
+		Cert    string `toml:"cert"`
+	}
+	type AppConfig struct {
+		Server struct {
+			Port int    `toml:"port"`
+			Host string `toml:"host"`
+			TLS  *TLS   `toml:"tls"`
+		} `toml:"server"`
+		Tags []string `toml:"tags"`
+	}
+
+	cfg := New()
+
+	var oldCfg AppConfig
+	oldCfg.Server.Port = 8080
+	oldCfg.Server.Host = "localhost"
+	oldCfg.Tags = []string{"a"}
+
+	newCfg := oldCfg
+	newCfg.Server.Port = 9090
+
+	assert.Equal(t, []string{"server.port"}, cfg.ChangedFields(oldCfg, &newCfg))
+	assert.Empty(t, cfg.ChangedFields(&oldCfg, &oldCfg))
+
+	// Fields under a newly set pointer are reported
+	newCfg = oldCfg
+	newCfg.Server.TLS = &TLS{Enabled: true}
+	newCfg.Tags = []string{"a", "b"}
+	assert.Equal(t, []string{"server.tls.cert", "server.tls.enabled", "tags"}, cfg.ChangedFields(oldCfg, newCfg))
+
+	// Mismatched types are not comparable
+	assert.Nil(t, cfg.ChangedFields(oldCfg, struct{}{}))
+}
+
 // BenchmarkWatchOverhead benchmarks the overhead of file watching
 func BenchmarkWatchOverhead(b *testing.B) {
 	tmpDir := b.TempDir()
@@ -517,4 +793,582 @@ func BenchmarkWatchOverhead(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_, _ = cfg.Get(fmt.Sprintf("value%d", i%100))
 	}
+}
+
+// TestPollJitterAndBackoff tests randomized poll intervals and backoff on stat errors
+func TestPollJitterAndBackoff(t *testing.T) {
+	t.Run("Delay", func(t *testing.T) {
+		w := &watcher{opts: WatchOptions{PollInterval: testPollInterval}}
+		assert.Equal(t, testPollInterval, w.pollDelay(0))
+		assert.Equal(t, 2*testPollInterval, w.pollDelay(1))
+		assert.Equal(t, 8*testPollInterval, w.pollDelay(3))
+		assert.Equal(t, MaxPollBackoff, w.pollDelay(100))
+
+		w.opts.PollJitter = testPollInterval
+		for i := 0; i < 100; i++ {
+			delay := w.pollDelay(0)
+			assert.GreaterOrEqual(t, delay, testPollInterval)
+			assert.LessOrEqual(t, delay, 2*testPollInterval)
+		}
+	})
+
+	// recordStats replaces watchStat for path, recording call times and failing while fail returns true
+	recordStats := func(t *testing.T, path string, fail func(call int) bool) func() []time.Time {
+		var mu sync.Mutex
+		var calls []time.Time
+		original := watchStat
+		watchStat = func(name string) (os.FileInfo, error) {
+			if name != path {
+				return original(name)
+			}
+			mu.Lock()
+			calls = append(calls, time.Now())
+			call := len(calls)
+			mu.Unlock()
+			if fail(call) {
+				return nil, fmt.Errorf("mount unavailable")
+			}
+			return original(name)
+		}
+		t.Cleanup(func() { watchStat = original })
+
+		return func() []time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]time.Time(nil), calls...)
+		}
+	}
+
+	intervals := func(calls []time.Time) []time.Duration {
+		var result []time.Duration
+		for i := 1; i < len(calls); i++ {
+			result = append(result, calls[i].Sub(calls[i-1]))
+		}
+		return result
+	}
+
+	t.Run("Jitter", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`value = 1`), 0644))
+
+		cfg := New()
+		cfg.Register("value", 0)
+		require.NoError(t, cfg.LoadFile(configPath))
+
+		calls := recordStats(t, configPath, func(int) bool { return false })
+		cfg.AutoUpdateWithOptions(WatchOptions{
+			PollInterval: testPollInterval,
+			PollJitter:   testPollInterval,
+			Debounce:     testDebounce,
+		})
+		require.Eventually(t, func() bool { return len(calls()) >= 7 }, testWatchTimeout, testSpinWaitInterval)
+		cfg.StopAutoUpdate()
+
+		gaps := intervals(calls())
+		shortest, longest := gaps[0], gaps[0]
+		for _, gap := range gaps {
+			assert.GreaterOrEqual(t, gap, testPollInterval)
+			shortest = min(shortest, gap)
+			longest = max(longest, gap)
+		}
+		assert.Greater(t, longest-shortest, testPollInterval/10, "polls should not run at a fixed interval")
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`value = 1`), 0644))
+
+		cfg := New()
+		cfg.Register("value", 0)
+		require.NoError(t, cfg.LoadFile(configPath))
+
+		// The first two polls fail, then the mount recovers
+		calls := recordStats(t, configPath, func(call int) bool { return call <= 2 })
+		cfg.AutoUpdateWithOptions(WatchOptions{
+			PollInterval: testPollInterval,
+			Debounce:     testDebounce,
+		})
+		require.Eventually(t, func() bool { return len(calls()) >= 4 }, testWatchTimeout, testSpinWaitInterval)
+		cfg.StopAutoUpdate()
+
+		gaps := intervals(calls())
+		assert.GreaterOrEqual(t, gaps[0], 2*testPollInterval, "first failure doubles the delay")
+		assert.GreaterOrEqual(t, gaps[1], 4*testPollInterval, "second failure doubles it again")
+		assert.Less(t, gaps[2], 2*testPollInterval, "success resets the delay")
+	})
+}
+
+// TestWatchContentHash tests change detection by content instead of mod-time and size
+func TestWatchContentHash(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`value = 1`), 0644))
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	originalTime := info.ModTime()
+
+	cfg := New()
+	cfg.Register("value", 0)
+	require.NoError(t, cfg.LoadFile(configPath))
+
+	changes := cfg.WatchWithOptions(WatchOptions{
+		PollInterval:   testPollInterval,
+		Debounce:       testDebounce,
+		UseContentHash: true,
+	})
+	defer cfg.StopAutoUpdate()
+
+	t.Run("TouchOnly", func(t *testing.T) {
+		later := originalTime.Add(time.Hour)
+		require.NoError(t, os.Chtimes(configPath, later, later))
+
+		select {
+		case path := <-changes:
+			t.Errorf("Unexpected notification %q for a touch-only change", path)
+		case <-time.After(testPollWindow + testDebounceSettle):
+		}
+	})
+
+	t.Run("SameModTimeContentChange", func(t *testing.T) {
+		// Same size and restored mtime: invisible to mod-time/size comparison
+		require.NoError(t, os.WriteFile(configPath, []byte(`value = 2`), 0644))
+		require.NoError(t, os.Chtimes(configPath, originalTime, originalTime))
+
+		select {
+		case path := <-changes:
+			assert.Equal(t, "value", path)
+		case <-time.After(testWatchTimeout):
+			t.Fatal("Timeout waiting for content change notification")
+		}
+		val, _ := cfg.Get("value")
+		assert.Equal(t, int64(2), val)
+	})
+}
+
+// TestWatchPermissionPolicy tests the block, warn, and ignore permission policies
+func TestWatchPermissionPolicy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping permission test on Windows")
+	}
+
+	t.Run("Block", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+
+		cfg := New()
+		cfg.Register("test", "default")
+		require.NoError(t, cfg.LoadFile(configPath))
+
+		changes := cfg.WatchWithOptions(WatchOptions{
+			PollInterval:      testPollInterval,
+			Debounce:          testDebounce,
+			VerifyPermissions: true,
+			PermissionPolicy:  PermissionBlock,
+		})
+		t.Cleanup(cfg.StopAutoUpdate)
+
+		require.NoError(t, os.Chmod(configPath, 0666))
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "rotated"`), 0644))
+
+		var received []string
+		deadline := time.After(3 * testPollWindow)
+	collect:
+		for {
+			select {
+			case path := <-changes:
+				received = append(received, path)
+			case <-deadline:
+				break collect
+			}
+		}
+		assert.Contains(t, received, "permissions_changed")
+		assert.NotContains(t, received, "test")
+		val, _ := cfg.Get("test")
+		assert.Equal(t, "value", val)
+	})
+
+	t.Run("BlockThenRestoreWithContentHash", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+
+		cfg := New()
+		cfg.Register("test", "default")
+		require.NoError(t, cfg.LoadFile(configPath))
+
+		changes := cfg.WatchWithOptions(WatchOptions{
+			PollInterval:      testPollInterval,
+			Debounce:          testDebounce,
+			VerifyPermissions: true,
+			PermissionPolicy:  PermissionBlock,
+			UseContentHash:    true,
+		})
+		t.Cleanup(cfg.StopAutoUpdate)
+
+		require.NoError(t, os.Chmod(configPath, 0666))
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "rotated"`), 0644))
+		select {
+		case path := <-changes:
+			require.Equal(t, "permissions_changed", path)
+		case <-time.After(testWatchTimeout):
+			t.Fatal("Timeout waiting for permission notification")
+		}
+
+		// The blocked content change is reloaded once permissions are restored
+		require.NoError(t, os.Chmod(configPath, 0644))
+		deadline := time.After(testWatchTimeout)
+		for {
+			select {
+			case path := <-changes:
+				if path == "test" {
+					val, _ := cfg.Get("test")
+					assert.Equal(t, "rotated", val)
+					return
+				}
+			case <-deadline:
+				t.Fatal("Timeout waiting for the blocked change to reload")
+			}
+		}
+	})
+
+	t.Run("Warn", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+
+		cfg := New()
+		cfg.Register("test", "default")
+		require.NoError(t, cfg.LoadFile(configPath))
+
+		changes := cfg.WatchWithOptions(WatchOptions{
+			PollInterval:      testPollInterval,
+			Debounce:          testDebounce,
+			VerifyPermissions: true,
+			PermissionPolicy:  PermissionWarn,
+		})
+		t.Cleanup(cfg.StopAutoUpdate)
+
+		require.NoError(t, os.Chmod(configPath, 0666))
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "rotated"`), 0644))
+
+		var received []string
+		deadline := time.After(3 * testPollWindow)
+	collect:
+		for {
+			select {
+			case path := <-changes:
+				received = append(received, path)
+			case <-deadline:
+				break collect
+			}
+		}
+		assert.Equal(t, []string{"permissions_changed", "test"}, received)
+		val, _ := cfg.Get("test")
+		assert.Equal(t, "rotated", val)
+	})
+
+	t.Run("Ignore", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+
+		cfg := New()
+		cfg.Register("test", "default")
+		require.NoError(t, cfg.LoadFile(configPath))
+
+		changes := cfg.WatchWithOptions(WatchOptions{
+			PollInterval:      testPollInterval,
+			Debounce:          testDebounce,
+			VerifyPermissions: true,
+			PermissionPolicy:  PermissionIgnore,
+		})
+		t.Cleanup(cfg.StopAutoUpdate)
+
+		require.NoError(t, os.Chmod(configPath, 0666))
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "rotated"`), 0644))
+
+		var received []string
+		deadline := time.After(3 * testPollWindow)
+	collect:
+		for {
+			select {
+			case path := <-changes:
+				received = append(received, path)
+			case <-deadline:
+				break collect
+			}
+		}
+		assert.Equal(t, []string{"test"}, received)
+		val, _ := cfg.Get("test")
+		assert.Equal(t, "rotated", val)
+	})
+}
+
+// TestWatchStats tests watcher activity counters
+func TestWatchStats(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+
+	cfg := New()
+	cfg.Register("test", "default")
+	require.NoError(t, cfg.LoadFile(configPath))
+	assert.Equal(t, WatchStats{}, cfg.WatchStats())
+
+	started := time.Now()
+	changes := cfg.WatchWithOptions(WatchOptions{
+		PollInterval: testPollInterval,
+		Debounce:     testDebounce,
+	})
+	defer cfg.StopAutoUpdate()
+
+	// expect writes content and waits for the notification it produces
+	expect := func(content, notification string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+		select {
+		case path := <-changes:
+			assert.Contains(t, path, notification)
+		case <-time.After(testWatchTimeout):
+			t.Fatalf("Timeout waiting for %s", notification)
+		}
+	}
+
+	expect(`test = "one"`, "test")
+	expect(`test = "second"`, "test")
+	expect(`test = `, "reload_error")
+
+	stats := cfg.WatchStats()
+	assert.Equal(t, int64(2), stats.Reloads)
+	assert.Equal(t, int64(1), stats.ReloadErrors)
+	assert.Equal(t, int64(0), stats.DroppedNotifications)
+	assert.Equal(t, 1, stats.Subscribers)
+	assert.True(t, stats.LastReload.After(started))
+
+	// A second subscriber that never reads fills up and drops the overflow, as does the first
+	cfg.Watch()
+	w := cfg.watcher
+	for i := 0; i < 12; i++ {
+		w.notifyWatchers("test")
+	}
+	stats = cfg.WatchStats()
+	assert.Equal(t, 2, stats.Subscribers)
+	assert.Equal(t, int64(4), stats.DroppedNotifications)
+}
+
+// TestWatchEvents tests ChangeEvent delivery for file reloads
+func TestWatchEvents(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+
+	cfg := New()
+	cfg.Register("test", "default")
+	cfg.Register("other", "default")
+	require.NoError(t, cfg.LoadFile(configPath))
+
+	cfg.AutoUpdateWithOptions(WatchOptions{
+		PollInterval: testPollInterval,
+		Debounce:     testDebounce,
+	})
+	defer cfg.StopAutoUpdate()
+	events := cfg.WatchEvents()
+	assert.Equal(t, 1, cfg.WatcherCount())
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`test = "changed"`), 0644))
+	select {
+	case event := <-events:
+		assert.Equal(t, ChangeEvent{Path: "test", OldValue: "value", NewValue: "changed", Cause: ChangeCauseReload}, event)
+	case <-time.After(testWatchTimeout):
+		t.Fatal("Timeout waiting for change event")
+	}
+
+	// Without a file, the channel is closed
+	_, ok := <-New().WatchEvents()
+	assert.False(t, ok)
+}
+
+// TestWatchContext tests context-bound subscriptions
+func TestWatchContext(t *testing.T) {
+	waitClosed := func(t *testing.T, ch <-chan string) {
+		for {
+			select {
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+			case <-time.After(testEventuallyTimeout):
+				t.Fatal("Channel not closed after cancellation")
+			}
+		}
+	}
+
+	t.Run("StopsWatcherItStarted", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+		cfg := New()
+		cfg.Register("test", "default")
+		require.NoError(t, cfg.LoadFile(configPath))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch := cfg.WatchContext(ctx)
+		waitForWatchingState(t, cfg, true, "WatchContext should start the watcher")
+		assert.Equal(t, 1, cfg.WatcherCount())
+
+		cancel()
+		waitClosed(t, ch)
+		waitForWatchingState(t, cfg, false, "Watcher should stop with the context")
+		assert.Equal(t, 0, cfg.WatcherCount())
+	})
+
+	t.Run("KeepsExistingWatcher", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+		cfg := New()
+		cfg.Register("test", "default")
+		require.NoError(t, cfg.LoadFile(configPath))
+		cfg.AutoUpdate()
+		defer cfg.StopAutoUpdate()
+		waitForWatchingState(t, cfg, true, "Watcher should be active")
+		plain := cfg.Watch()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := cfg.WatchContext(ctx)
+		assert.Equal(t, 2, cfg.WatcherCount())
+
+		cancel()
+		waitClosed(t, ch)
+		assert.Eventually(t, func() bool { return cfg.WatcherCount() == 1 }, testEventuallyTimeout, testSpinWaitInterval)
+		assert.True(t, cfg.IsWatching())
+
+		// The remaining subscription stays open
+		select {
+		case _, ok := <-plain:
+			assert.True(t, ok, "Unrelated channel should stay open")
+		default:
+		}
+	})
+}
+
+// captureLogger records formatted diagnostics by level
+type captureLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *captureLogger) add(level, format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, level+": "+fmt.Sprintf(format, args...))
+}
+
+func (l *captureLogger) Debugf(format string, args ...any) { l.add("debug", format, args...) }
+func (l *captureLogger) Warnf(format string, args ...any)  { l.add("warn", format, args...) }
+func (l *captureLogger) Errorf(format string, args ...any) { l.add("error", format, args...) }
+
+// has reports whether an entry starts with prefix
+func (l *captureLogger) has(prefix string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, entry := range l.entries {
+		if strings.HasPrefix(entry, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestLogger tests diagnostics reported through the configured logger
+func TestLogger(t *testing.T) {
+	t.Run("ReloadError", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = "value"`), 0644))
+
+		logger := &captureLogger{}
+		cfg, err := NewBuilder().
+			WithDefaults(&struct {
+				Test string `toml:"test"`
+			}{Test: "default"}).
+			WithFile(configPath).
+			WithLogger(logger).
+			Build()
+		require.NoError(t, err)
+
+		changes := cfg.WatchWithOptions(WatchOptions{
+			PollInterval: testPollInterval,
+			Debounce:     testDebounce,
+		})
+		defer cfg.StopAutoUpdate()
+
+		require.NoError(t, os.WriteFile(configPath, []byte(`test = `), 0644))
+		select {
+		case path := <-changes:
+			require.Contains(t, path, "reload_error")
+		case <-time.After(testWatchTimeout):
+			t.Fatal("Timeout waiting for reload error")
+		}
+		assert.True(t, logger.has("error: config watcher: reload of "+configPath+" failed"))
+	})
+
+	t.Run("WatchStructDecodeError", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "test.toml")
+		require.NoError(t, os.WriteFile(configPath, []byte("[server]\nport = 8080\n"), 0644))
+
+		type AppConfig struct {
+			Server struct {
+				Port int `toml:"port"`
+			} `toml:"server"`
+		}
+
+		logger := &captureLogger{}
+		cfg, err := NewBuilder().
+			WithTarget(&AppConfig{}).
+			WithFile(configPath).
+			WithLogger(logger).
+			Build()
+		require.NoError(t, err)
+
+		cfg.AutoUpdateWithOptions(WatchOptions{
+			PollInterval: testPollInterval,
+			Debounce:     testDebounce,
+		})
+		defer cfg.StopAutoUpdate()
+		snapshots := WatchStruct[AppConfig](cfg)
+
+		require.NoError(t, os.WriteFile(configPath, []byte("[server]\nport = \"not-a-port\"\n"), 0644))
+		require.Eventually(t, func() bool {
+			return logger.has("error: config watcher: decode of *config.AppConfig snapshot failed")
+		}, testWatchTimeout, testPollInterval)
+		select {
+		case snapshot := <-snapshots:
+			t.Fatalf("unexpected snapshot %+v", snapshot)
+		default:
+		}
+
+		// The next valid reload delivers a snapshot taken from the target cache
+		require.NoError(t, os.WriteFile(configPath, []byte("[server]\nport = 9090\n"), 0644))
+		select {
+		case snapshot := <-snapshots:
+			assert.Equal(t, 9090, snapshot.Server.Port)
+			target, err := cfg.AsStruct()
+			require.NoError(t, err)
+			assert.NotSame(t, target, snapshot)
+		case <-time.After(testWatchTimeout):
+			t.Fatal("Timeout waiting for typed snapshot")
+		}
+	})
+
+	t.Run("UnknownKeys", func(t *testing.T) {
+		logger := &captureLogger{}
+		cfg := New()
+		cfg.SetLogger(logger)
+		require.NoError(t, cfg.Register("server.port", 8080))
+
+		require.NoError(t, cfg.LoadReader(strings.NewReader("[server]\nport = 9090\nprot = 1\n"), "toml"))
+		assert.True(t, logger.has("warn: config: ignoring unknown keys in config data: server.prot"))
+	})
+
+	t.Run("DefaultDiscards", func(t *testing.T) {
+		cfg := New()
+		require.NoError(t, cfg.Register("server.port", 8080))
+		assert.NoError(t, cfg.LoadReader(strings.NewReader("extra = 1\n"), "toml"))
+
+		cfg.SetLogger(&captureLogger{})
+		cfg.SetLogger(nil)
+		assert.Equal(t, nopLogger{}, cfg.logger())
+	})
 }
\ No newline at end of file